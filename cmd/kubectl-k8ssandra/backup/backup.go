@@ -0,0 +1,217 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/k8ssandra/k8ssandra-client/pkg/cassdcutil"
+	"github.com/k8ssandra/k8ssandra-client/pkg/kubernetes"
+	"github.com/k8ssandra/k8ssandra-client/pkg/medusa"
+	medusaapi "github.com/k8ssandra/k8ssandra-operator/apis/medusa/v1alpha1"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/shared"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	createExample = `
+	# take a full backup of datacenter dc1 and wait for it to finish
+	%[1]s create dc1
+
+	# take a differential backup with a chosen name
+	%[1]s create dc1 --name nightly-1 --type differential
+
+	# only create the MedusaBackupJob, do not wait for it
+	%[1]s create dc1 --wait=false
+	`
+
+	errNoDatacenterDefined = fmt.Errorf("no target datacenter given")
+	errNoBackupDefined     = fmt.Errorf("no target backup given")
+	errInvalidBackupType   = fmt.Errorf("--type must be %s or %s", shared.FullBackup, shared.DifferentialBackup)
+	errJobDeleted          = fmt.Errorf("the job was deleted before it finished")
+)
+
+// nameTimeFormat is used for the generated names of the backups and restores
+const nameTimeFormat = "20060102-150405"
+
+type ClientOptions struct {
+	configFlags *genericclioptions.ConfigFlags
+	genericclioptions.IOStreams
+}
+
+// NewClientOptions provides an instance of ClientOptions with default values
+func NewClientOptions(streams genericclioptions.IOStreams) *ClientOptions {
+	return &ClientOptions{
+		configFlags: genericclioptions.NewConfigFlags(true),
+		IOStreams:   streams,
+	}
+}
+
+// NewCmd provides a cobra command wrapping ClientOptions
+func NewCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	o := NewClientOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:   "backup [subcommand] [flags]",
+		Short: "create, list and delete Medusa backups",
+	}
+
+	// Add subcommands
+	cmd.AddCommand(NewCreateCmd(streams))
+	cmd.AddCommand(NewListCmd(streams))
+	cmd.AddCommand(NewDescribeCmd(streams))
+	cmd.AddCommand(NewDeleteCmd(streams))
+
+	o.configFlags.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+type createOptions struct {
+	configFlags *genericclioptions.ConfigFlags
+	genericclioptions.IOStreams
+	namespace   string
+	dcName      string
+	name        string
+	backupType  string
+	wait        bool
+	timeout     time.Duration
+	kubeClient  client.WithWatch
+	cassManager *cassdcutil.CassManager
+}
+
+func newCreateOptions(streams genericclioptions.IOStreams) *createOptions {
+	return &createOptions{
+		configFlags: genericclioptions.NewConfigFlags(true),
+		IOStreams:   streams,
+	}
+}
+
+// NewCreateCmd provides a cobra command wrapping createOptions
+func NewCreateCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	o := newCreateOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:          "create [datacenter] [flags]",
+		Short:        "take a Medusa backup of a datacenter",
+		Example:      fmt.Sprintf(createExample, "kubectl k8ssandra backup"),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.Complete(c, args); err != nil {
+				return err
+			}
+			if err := o.Validate(); err != nil {
+				return err
+			}
+			if err := o.Run(); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	fl := cmd.Flags()
+	fl.StringVar(&o.name, "name", "", "name of the backup, defaults to the datacenter name and the current time")
+	fl.StringVar(&o.backupType, "type", string(shared.FullBackup), fmt.Sprintf("type of the backup, %s or %s", shared.FullBackup, shared.DifferentialBackup))
+	fl.BoolVarP(&o.wait, "wait", "w", true, "wait until the backup has finished, fails if it failed on any pod")
+	fl.DurationVar(&o.timeout, "timeout", 1*time.Hour, "how long to wait for the backup to finish")
+	o.configFlags.AddFlags(fl)
+	return cmd
+}
+
+// Complete parses the arguments and necessary flags to options
+func (c *createOptions) Complete(cmd *cobra.Command, args []string) error {
+	var err error
+
+	if len(args) < 1 {
+		return errNoDatacenterDefined
+	}
+
+	c.dcName = args[0]
+
+	c.namespace, _, err = c.configFlags.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	restConfig, err := c.configFlags.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+
+	c.kubeClient, err = kubernetes.GetWatchClient(restConfig)
+	if err != nil {
+		return err
+	}
+
+	c.cassManager = cassdcutil.NewManager(c.kubeClient)
+
+	return nil
+}
+
+// Validate ensures that all required arguments and flag values are provided
+func (c *createOptions) Validate() error {
+	if c.backupType != string(shared.FullBackup) && c.backupType != string(shared.DifferentialBackup) {
+		return errInvalidBackupType
+	}
+
+	return nil
+}
+
+// Run creates the MedusaBackupJob and waits for it to finish if requested
+func (c *createOptions) Run() error {
+	ctx := context.Background()
+
+	dc, err := c.cassManager.CassandraDatacenter(ctx, c.dcName, c.namespace)
+	if err != nil {
+		return err
+	}
+
+	if err := medusa.CheckDeployed(dc); err != nil {
+		return err
+	}
+
+	name := c.name
+	if name == "" {
+		name = fmt.Sprintf("%s-%s", dc.Name, time.Now().UTC().Format(nameTimeFormat))
+	}
+
+	job := medusa.NewBackupJob(dc, name, shared.BackupType(c.backupType))
+	if err := c.kubeClient.Create(ctx, job); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(c.Out, "medusabackupjob/%s created\n", job.Name)
+
+	if !c.wait {
+		return nil
+	}
+
+	previous := ""
+	last, err := kubernetes.WaitFor(ctx, c.kubeClient, job, c.timeout, func(obj client.Object) (bool, error) {
+		if obj == nil {
+			return false, errJobDeleted
+		}
+
+		current := obj.(*medusaapi.MedusaBackupJob)
+		if progress := backupProgress(current); progress != previous {
+			fmt.Fprintln(c.Out, progress)
+			previous = progress
+		}
+
+		return medusa.BackupJobFinished(current), nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := medusa.BackupJobError(last.(*medusaapi.MedusaBackupJob)); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(c.Out, "Backup %s finished\n", job.Name)
+	return nil
+}
@@ -0,0 +1,153 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	"github.com/k8ssandra/k8ssandra-client/pkg/cassdcutil"
+	"github.com/k8ssandra/k8ssandra-client/pkg/medusa"
+	medusaapi "github.com/k8ssandra/k8ssandra-operator/apis/medusa/v1alpha1"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/shared"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func testClient(t *testing.T, objects ...client.Object) client.WithWatch {
+	scheme := runtime.NewScheme()
+	require.NoError(t, cassdcapi.AddToScheme(scheme))
+	require.NoError(t, medusaapi.AddToScheme(scheme))
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+}
+
+// testDatacenter returns a datacenter of one node with Medusa deployed
+func testDatacenter() *cassdcapi.CassandraDatacenter {
+	return &cassdcapi.CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{Name: "dc1", Namespace: "ns1"},
+		Spec: cassdcapi.CassandraDatacenterSpec{
+			ClusterName: "demo",
+			Size:        1,
+			PodTemplateSpec: &corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "cassandra"}, {Name: "medusa"}}},
+			},
+		},
+	}
+}
+
+func testBackup(nodes int) *medusaapi.MedusaBackup {
+	backup := &medusaapi.MedusaBackup{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup1", Namespace: "ns1"},
+		Spec:       medusaapi.MedusaBackupSpec{CassandraDatacenter: "dc1", Type: shared.FullBackup},
+		Status: medusaapi.MedusaBackupStatus{
+			StartTime:     metav1.NewTime(time.Now().Add(-time.Minute)),
+			FinishTime:    metav1.NewTime(time.Now()),
+			TotalNodes:    int32(nodes),
+			FinishedNodes: int32(nodes),
+			Status:        medusa.StatusSuccess,
+		},
+	}
+	for i := 0; i < nodes; i++ {
+		backup.Status.Nodes = append(backup.Status.Nodes, &medusaapi.MedusaBackupNode{Datacenter: "dc1", Rack: "default"})
+	}
+	return backup
+}
+
+func testRestoreOptions(t *testing.T, in string, objects ...client.Object) (*restoreOptions, client.WithWatch, *bytes.Buffer) {
+	kubeClient := testClient(t, objects...)
+	streams, stdin, out, _ := genericiooptions.NewTestIOStreams()
+	stdin.WriteString(in)
+
+	o := newRestoreOptions(streams)
+	o.namespace = "ns1"
+	o.backupName = "backup1"
+	o.kubeClient = kubeClient
+	o.cassManager = cassdcutil.NewManager(kubeClient)
+	return o, kubeClient, out
+}
+
+func restoreJobs(t *testing.T, kubeClient client.Client) []medusaapi.MedusaRestoreJob {
+	jobs := &medusaapi.MedusaRestoreJobList{}
+	require.NoError(t, kubeClient.List(context.TODO(), jobs))
+	return jobs.Items
+}
+
+func TestCreateValidateType(t *testing.T) {
+	o := newCreateOptions(genericiooptions.NewTestIOStreamsDiscard())
+
+	o.backupType = "incremental"
+	require.ErrorIs(t, o.Validate(), errInvalidBackupType)
+
+	for _, backupType := range []shared.BackupType{shared.FullBackup, shared.DifferentialBackup} {
+		o.backupType = string(backupType)
+		require.NoError(t, o.Validate())
+	}
+}
+
+func TestRestoreDeclined(t *testing.T) {
+	require := require.New(t)
+	o, kubeClient, out := testRestoreOptions(t, "n\n", testDatacenter(), testBackup(1))
+
+	require.NoError(o.Run())
+	require.Contains(out.String(), "Restore backup backup1 to cassandradatacenter/dc1?")
+	require.Contains(out.String(), "Nothing restored")
+	require.Empty(restoreJobs(t, kubeClient))
+}
+
+func TestRestoreConfirmed(t *testing.T) {
+	require := require.New(t)
+	o, kubeClient, out := testRestoreOptions(t, "y\n", testDatacenter(), testBackup(1))
+	o.name = "restore1"
+
+	require.NoError(o.Run())
+	require.Contains(out.String(), "medusarestorejob/restore1 created")
+
+	jobs := restoreJobs(t, kubeClient)
+	require.Len(jobs, 1)
+	require.Equal("backup1", jobs[0].Spec.Backup)
+	require.Equal("dc1", jobs[0].Spec.CassandraDatacenter)
+}
+
+func TestRestoreTopologyMismatch(t *testing.T) {
+	require := require.New(t)
+
+	// The backup has two nodes, the datacenter one: the restore is refused before asking for confirmation
+	o, kubeClient, out := testRestoreOptions(t, "y\n", testDatacenter(), testBackup(2))
+
+	err := o.Run()
+	require.ErrorContains(err, "the topology of the datacenter does not match the backup")
+	require.Empty(out.String())
+	require.Empty(restoreJobs(t, kubeClient))
+}
+
+func TestDeleteConfirmation(t *testing.T) {
+	require := require.New(t)
+	kubeClient := testClient(t, testBackup(1))
+
+	deleted := []string{}
+	streams, stdin, out, _ := genericiooptions.NewTestIOStreams()
+	o := newDeleteOptions(streams)
+	o.namespace = "ns1"
+	o.backupName = "backup1"
+	o.kubeClient = kubeClient
+	o.deleteFiles = func(ctx context.Context, backup *medusaapi.MedusaBackup) error {
+		deleted = append(deleted, backup.Name)
+		return nil
+	}
+
+	stdin.WriteString("n\n")
+	require.NoError(o.Run())
+	require.Contains(out.String(), "Nothing deleted")
+	require.Empty(deleted)
+
+	o.yes = true
+	require.NoError(o.Run())
+	require.Contains(out.String(), "Backup backup1 deleted")
+	require.Equal([]string{"backup1"}, deleted)
+}
@@ -0,0 +1,115 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/k8ssandra/k8ssandra-client/pkg/kubernetes"
+	"github.com/k8ssandra/k8ssandra-client/pkg/medusa"
+	"github.com/k8ssandra/k8ssandra-client/pkg/util"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	deleteExample = `
+	# delete the files of a backup from the storage bucket and its MedusaBackup and MedusaBackupJob
+	%[1]s delete <backup>
+
+	# delete without asking for confirmation
+	%[1]s delete <backup> --yes
+	`
+)
+
+type deleteOptions struct {
+	configFlags *genericclioptions.ConfigFlags
+	genericclioptions.IOStreams
+	namespace   string
+	backupName  string
+	yes         bool
+	kubeClient  client.Client
+	deleteFiles medusa.StorageDeleter
+}
+
+func newDeleteOptions(streams genericclioptions.IOStreams) *deleteOptions {
+	return &deleteOptions{
+		configFlags: genericclioptions.NewConfigFlags(true),
+		IOStreams:   streams,
+	}
+}
+
+// NewDeleteCmd provides a cobra command wrapping deleteOptions
+func NewDeleteCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	o := newDeleteOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:   "delete [backup] [flags]",
+		Short: "delete a Medusa backup",
+		Long: `Deletes the files of a backup from the storage bucket through Medusa, then the MedusaBackup and the
+MedusaBackupJob of the backup. Medusa is reached through a ready pod of the datacenter the backup was taken
+from. The Kubernetes objects are kept if the files could not be deleted.`,
+		Example:      fmt.Sprintf(deleteExample, "kubectl k8ssandra backup"),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.Complete(c, args); err != nil {
+				return err
+			}
+			if err := o.Run(); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	fl := cmd.Flags()
+	fl.BoolVarP(&o.yes, "yes", "y", false, "delete the backup without asking for confirmation")
+	o.configFlags.AddFlags(fl)
+	return cmd
+}
+
+// Complete parses the arguments and necessary flags to options
+func (c *deleteOptions) Complete(cmd *cobra.Command, args []string) error {
+	var err error
+
+	if len(args) < 1 {
+		return errNoBackupDefined
+	}
+
+	c.backupName = args[0]
+
+	c.namespace, _, err = c.configFlags.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	restConfig, err := c.configFlags.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+
+	c.kubeClient, err = kubernetes.GetClient(restConfig)
+	if err != nil {
+		return err
+	}
+
+	c.deleteFiles = medusa.NewStorageDeleter(restConfig, c.kubeClient)
+
+	return nil
+}
+
+// Run deletes the backup after confirmation
+func (c *deleteOptions) Run() error {
+	if !c.yes && !util.Confirm(c.In, c.Out, fmt.Sprintf("Delete backup %s? Its files are deleted from the storage bucket.", c.backupName)) {
+		fmt.Fprintln(c.Out, "Nothing deleted")
+		return nil
+	}
+
+	if err := medusa.DeleteBackup(context.Background(), c.kubeClient, c.namespace, c.backupName, c.deleteFiles); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(c.Out, "Backup %s deleted\n", c.backupName)
+	return nil
+}
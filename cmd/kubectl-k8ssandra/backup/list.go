@@ -0,0 +1,166 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/k8ssandra/k8ssandra-client/pkg/kubernetes"
+	"github.com/k8ssandra/k8ssandra-client/pkg/medusa"
+	medusaapi "github.com/k8ssandra/k8ssandra-operator/apis/medusa/v1alpha1"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	listExample = `
+	# list the Medusa backups of the current namespace, oldest first
+	%[1]s list
+
+	# list the backups of datacenter dc1
+	%[1]s list --dc dc1
+
+	# list the backups of every namespace
+	%[1]s list --all-namespaces
+	`
+
+	describeExample = `
+	# show the status and the nodes of a backup
+	%[1]s describe <backup>
+	`
+)
+
+type listOptions struct {
+	configFlags *genericclioptions.ConfigFlags
+	genericclioptions.IOStreams
+	namespace     string
+	allNamespaces bool
+	dcName        string
+	backupName    string
+}
+
+func newListOptions(streams genericclioptions.IOStreams) *listOptions {
+	return &listOptions{
+		configFlags: genericclioptions.NewConfigFlags(true),
+		IOStreams:   streams,
+	}
+}
+
+// NewListCmd provides a cobra command wrapping listOptions
+func NewListCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	o := newListOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:          "list [flags]",
+		Short:        "list Medusa backups with their status and size",
+		Example:      fmt.Sprintf(listExample, "kubectl k8ssandra backup"),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.Complete(c, args); err != nil {
+				return err
+			}
+			if err := o.List(); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	fl := cmd.Flags()
+	fl.StringVar(&o.dcName, "dc", "", "list only the backups of the given CassandraDatacenter")
+	fl.BoolVarP(&o.allNamespaces, "all-namespaces", "A", false, "list the backups across all namespaces")
+	o.configFlags.AddFlags(fl)
+	return cmd
+}
+
+// NewDescribeCmd provides a cobra command wrapping listOptions
+func NewDescribeCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	o := newListOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:          "describe [backup]",
+		Short:        "show the status and the nodes of a Medusa backup",
+		Example:      fmt.Sprintf(describeExample, "kubectl k8ssandra backup"),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if len(args) < 1 {
+				return errNoBackupDefined
+			}
+			o.backupName = args[0]
+
+			if err := o.Complete(c, args); err != nil {
+				return err
+			}
+			if err := o.Describe(); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	o.configFlags.AddFlags(cmd.Flags())
+	return cmd
+}
+
+// Complete parses the arguments and necessary flags to options
+func (c *listOptions) Complete(cmd *cobra.Command, args []string) error {
+	var err error
+
+	c.namespace, _, err = c.configFlags.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	if c.allNamespaces {
+		c.namespace = ""
+	}
+
+	return nil
+}
+
+// List prints the backups of the namespace
+func (c *listOptions) List() error {
+	restConfig, err := c.configFlags.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+
+	kubeClient, err := kubernetes.GetClient(restConfig)
+	if err != nil {
+		return err
+	}
+
+	backups, err := medusa.ListBackups(context.Background(), kubeClient, c.namespace, c.dcName)
+	if err != nil {
+		return err
+	}
+
+	if len(backups) == 0 {
+		fmt.Fprintln(c.ErrOut, "No backups found")
+		return nil
+	}
+
+	return printBackupTable(c.Out, backups, c.allNamespaces)
+}
+
+// Describe prints the details of a single backup
+func (c *listOptions) Describe() error {
+	restConfig, err := c.configFlags.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+
+	kubeClient, err := kubernetes.GetClient(restConfig)
+	if err != nil {
+		return err
+	}
+
+	backup := &medusaapi.MedusaBackup{}
+	if err := kubeClient.Get(context.Background(), client.ObjectKey{Namespace: c.namespace, Name: c.backupName}, backup); err != nil {
+		return err
+	}
+
+	return printBackupDetails(c.Out, backup)
+}
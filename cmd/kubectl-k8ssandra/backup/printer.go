@@ -0,0 +1,109 @@
+package backup
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	medusaapi "github.com/k8ssandra/k8ssandra-operator/apis/medusa/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+// printBackupTable writes one line per backup
+func printBackupTable(out io.Writer, backups []medusaapi.MedusaBackup, withNamespace bool) error {
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+
+	if withNamespace {
+		fmt.Fprint(w, "NAMESPACE\t")
+	}
+	fmt.Fprintln(w, "NAME\tDATACENTER\tTYPE\tSTATUS\tNODES\tFILES\tSIZE\tSTARTED\tFINISHED")
+
+	for i := range backups {
+		b := &backups[i]
+		if withNamespace {
+			fmt.Fprintf(w, "%s\t", b.Namespace)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d/%d\t%d\t%s\t%s\t%s\n",
+			b.Name,
+			b.Spec.CassandraDatacenter,
+			orNone(string(b.Spec.Type)),
+			orNone(b.Status.Status),
+			b.Status.FinishedNodes,
+			b.Status.TotalNodes,
+			b.Status.TotalFiles,
+			orNone(b.Status.TotalSize),
+			age(&b.Status.StartTime),
+			age(&b.Status.FinishTime),
+		)
+	}
+
+	return w.Flush()
+}
+
+// printBackupDetails writes the status of a single backup and the nodes it was taken from
+func printBackupDetails(out io.Writer, b *medusaapi.MedusaBackup) error {
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+
+	fmt.Fprintf(w, "Name:\t%s\n", b.Name)
+	fmt.Fprintf(w, "Datacenter:\t%s\n", b.Spec.CassandraDatacenter)
+	fmt.Fprintf(w, "Type:\t%s\n", orNone(string(b.Spec.Type)))
+	fmt.Fprintf(w, "Status:\t%s\n", orNone(b.Status.Status))
+	fmt.Fprintf(w, "Started:\t%s\n", timestamp(&b.Status.StartTime))
+	fmt.Fprintf(w, "Finished:\t%s\n", timestamp(&b.Status.FinishTime))
+	fmt.Fprintf(w, "Nodes:\t%d finished of %d\n", b.Status.FinishedNodes, b.Status.TotalNodes)
+	fmt.Fprintf(w, "Files:\t%d\n", b.Status.TotalFiles)
+	fmt.Fprintf(w, "Size:\t%s\n", orNone(b.Status.TotalSize))
+
+	if len(b.Status.Nodes) > 0 {
+		fmt.Fprintln(w, "\nHOST\tDATACENTER\tRACK\tTOKENS")
+		for _, node := range b.Status.Nodes {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%d\n", node.Host, node.Datacenter, node.Rack, len(node.Tokens))
+		}
+	}
+
+	return w.Flush()
+}
+
+// backupProgress describes the state of the MedusaBackupJob on the pods
+func backupProgress(job *medusaapi.MedusaBackupJob) string {
+	return fmt.Sprintf("%s: %d in progress, %d finished, %d failed", job.Name, len(job.Status.InProgress), len(job.Status.Finished), len(job.Status.Failed))
+}
+
+// restoreProgress describes the phase the MedusaRestoreJob is in
+func restoreProgress(job *medusaapi.MedusaRestoreJob) string {
+	switch {
+	case job.Status.Message != "":
+		return fmt.Sprintf("%s: %s", job.Name, job.Status.Message)
+	case !job.Status.FinishTime.IsZero():
+		return fmt.Sprintf("%s: finished", job.Name)
+	case !job.Status.DatacenterStopped.IsZero():
+		return fmt.Sprintf("%s: datacenter stopped, restoring the data and starting the datacenter", job.Name)
+	case job.Status.RestorePrepared:
+		return fmt.Sprintf("%s: restore prepared, stopping the datacenter", job.Name)
+	}
+
+	return fmt.Sprintf("%s: preparing the restore", job.Name)
+}
+
+func age(t *metav1.Time) string {
+	if t == nil || t.IsZero() {
+		return "<none>"
+	}
+	return duration.HumanDuration(time.Since(t.Time))
+}
+
+func timestamp(t *metav1.Time) string {
+	if t == nil || t.IsZero() {
+		return "<none>"
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func orNone(value string) string {
+	if value == "" {
+		return "<none>"
+	}
+	return value
+}
@@ -0,0 +1,183 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/k8ssandra/k8ssandra-client/pkg/cassdcutil"
+	"github.com/k8ssandra/k8ssandra-client/pkg/kubernetes"
+	"github.com/k8ssandra/k8ssandra-client/pkg/medusa"
+	"github.com/k8ssandra/k8ssandra-client/pkg/util"
+	medusaapi "github.com/k8ssandra/k8ssandra-operator/apis/medusa/v1alpha1"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	restoreExample = `
+	# restore a backup to the datacenter it was taken from and wait for the datacenter to start again
+	%[1]s restore <backup>
+
+	# restore a backup to another datacenter with the same name and topology
+	%[1]s restore <backup> --dc dc1-restored
+	`
+)
+
+type restoreOptions struct {
+	configFlags *genericclioptions.ConfigFlags
+	genericclioptions.IOStreams
+	namespace   string
+	backupName  string
+	dcName      string
+	name        string
+	wait        bool
+	timeout     time.Duration
+	yes         bool
+	kubeClient  client.WithWatch
+	cassManager *cassdcutil.CassManager
+}
+
+func newRestoreOptions(streams genericclioptions.IOStreams) *restoreOptions {
+	return &restoreOptions{
+		configFlags: genericclioptions.NewConfigFlags(true),
+		IOStreams:   streams,
+	}
+}
+
+// NewRestoreCmd provides a cobra command wrapping restoreOptions
+func NewRestoreCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	o := newRestoreOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:          "restore [backup] [flags]",
+		Short:        "restore a Medusa backup, replacing all the data of the datacenter",
+		Example:      fmt.Sprintf(restoreExample, "kubectl k8ssandra"),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.Complete(c, args); err != nil {
+				return err
+			}
+			if err := o.Run(); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	fl := cmd.Flags()
+	fl.StringVar(&o.dcName, "dc", "", "target CassandraDatacenter, defaults to the datacenter the backup was taken from")
+	fl.StringVar(&o.name, "name", "", "name of the MedusaRestoreJob, defaults to the backup name and the current time")
+	fl.BoolVarP(&o.wait, "wait", "w", true, "wait until the restore has finished and the datacenter has started")
+	fl.DurationVar(&o.timeout, "timeout", 2*time.Hour, "how long to wait for the restore to finish")
+	fl.BoolVarP(&o.yes, "yes", "y", false, "restore the backup without asking for confirmation")
+	o.configFlags.AddFlags(fl)
+	return cmd
+}
+
+// Complete parses the arguments and necessary flags to options
+func (c *restoreOptions) Complete(cmd *cobra.Command, args []string) error {
+	var err error
+
+	if len(args) < 1 {
+		return errNoBackupDefined
+	}
+
+	c.backupName = args[0]
+
+	c.namespace, _, err = c.configFlags.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	restConfig, err := c.configFlags.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+
+	c.kubeClient, err = kubernetes.GetWatchClient(restConfig)
+	if err != nil {
+		return err
+	}
+
+	c.cassManager = cassdcutil.NewManager(c.kubeClient)
+
+	return nil
+}
+
+// Run verifies the backup fits the datacenter, creates the MedusaRestoreJob after confirmation and waits for it to
+// finish if requested
+func (c *restoreOptions) Run() error {
+	ctx := context.Background()
+
+	backup := &medusaapi.MedusaBackup{}
+	if err := c.kubeClient.Get(ctx, client.ObjectKey{Namespace: c.namespace, Name: c.backupName}, backup); err != nil {
+		return err
+	}
+
+	dcName := c.dcName
+	if dcName == "" {
+		dcName = backup.Spec.CassandraDatacenter
+	}
+
+	dc, err := c.cassManager.CassandraDatacenter(ctx, dcName, c.namespace)
+	if err != nil {
+		return err
+	}
+
+	if err := medusa.CheckDeployed(dc); err != nil {
+		return err
+	}
+
+	if err := medusa.CheckTopology(backup, dc); err != nil {
+		return err
+	}
+
+	if !c.yes && !util.Confirm(c.In, c.Out, fmt.Sprintf("Restore backup %s to cassandradatacenter/%s? The datacenter is stopped and all of its data is replaced.", backup.Name, dc.Name)) {
+		fmt.Fprintln(c.Out, "Nothing restored")
+		return nil
+	}
+
+	name := c.name
+	if name == "" {
+		name = fmt.Sprintf("%s-restore-%s", backup.Name, time.Now().UTC().Format(nameTimeFormat))
+	}
+
+	job := medusa.NewRestoreJob(dc, name, backup.Name)
+	if err := c.kubeClient.Create(ctx, job); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(c.Out, "medusarestorejob/%s created\n", job.Name)
+
+	if !c.wait {
+		return nil
+	}
+
+	previous := ""
+	last, err := kubernetes.WaitFor(ctx, c.kubeClient, job, c.timeout, func(obj client.Object) (bool, error) {
+		if obj == nil {
+			return false, errJobDeleted
+		}
+
+		current := obj.(*medusaapi.MedusaRestoreJob)
+		if progress := restoreProgress(current); progress != previous {
+			fmt.Fprintln(c.Out, progress)
+			previous = progress
+		}
+
+		return medusa.RestoreJobFinished(current), nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := medusa.RestoreJobError(last.(*medusaapi.MedusaRestoreJob)); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(c.Out, "Backup %s restored to cassandradatacenter/%s\n", backup.Name, dc.Name)
+	return nil
+}
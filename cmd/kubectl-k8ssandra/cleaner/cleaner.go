@@ -0,0 +1,189 @@
+package cleaner
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/k8ssandra/k8ssandra-client/pkg/cleaner"
+	"github.com/k8ssandra/k8ssandra-client/pkg/kubernetes"
+	"github.com/k8ssandra/k8ssandra-client/pkg/util"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	cleanExample = `
+	# remove the operator finalizers of the datacenters and clusters stuck terminating in the namespace
+	%[1]s clean
+
+	# also delete the volume claims, secrets and services left behind by deleted datacenters
+	%[1]s clean --delete-leftovers
+
+	# only list what would be cleaned in every namespace
+	%[1]s clean --all-namespaces --dry-run=client
+
+	# also clean objects terminating for more than 10 minutes while their operator is running
+	%[1]s clean --stuck-after 10m
+	`
+)
+
+type options struct {
+	configFlags *genericclioptions.ConfigFlags
+	genericclioptions.IOStreams
+	namespace       string
+	allNamespaces   bool
+	deleteLeftovers bool
+	stuckAfter      time.Duration
+	outputFlags     *util.OutputFlags
+	kubeClient      client.Client
+}
+
+func newOptions(streams genericclioptions.IOStreams) *options {
+	return &options{
+		configFlags: genericclioptions.NewConfigFlags(true),
+		outputFlags: util.NewOutputFlags(),
+		IOStreams:   streams,
+	}
+}
+
+// NewCmd provides a cobra command cleaning up after uninstalled operators
+func NewCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	o := newOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:          "clean [flags]",
+		Short:        "remove the operator finalizers of objects stuck terminating and the resources left behind by deleted datacenters",
+		Example:      fmt.Sprintf(cleanExample, "kubectl k8ssandra"),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.Complete(c, args); err != nil {
+				return err
+			}
+			if err := o.Validate(); err != nil {
+				return err
+			}
+			if err := o.Run(); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	fl := cmd.Flags()
+	fl.BoolVar(&o.deleteLeftovers, "delete-leftovers", false, "delete the PersistentVolumeClaims, Secrets and Services left behind by deleted datacenters instead of only listing them")
+	fl.BoolVarP(&o.allNamespaces, "all-namespaces", "A", false, "clean across all namespaces")
+	fl.DurationVar(&o.stuckAfter, "stuck-after", time.Hour, "how long an object must have been terminating to count as stuck while its operator is running")
+	o.outputFlags.AddFlags(cmd)
+	o.configFlags.AddFlags(fl)
+	return cmd
+}
+
+// Complete parses the arguments and necessary flags to options
+func (c *options) Complete(cmd *cobra.Command, args []string) error {
+	var err error
+
+	if err := c.outputFlags.Complete(cmd); err != nil {
+		return err
+	}
+
+	c.namespace, _, err = c.configFlags.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	if c.allNamespaces {
+		c.namespace = ""
+	}
+
+	restConfig, err := c.configFlags.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+
+	c.kubeClient, err = kubernetes.GetClient(restConfig)
+	return err
+}
+
+// Validate ensures that all required arguments and flag values are provided
+func (c *options) Validate() error {
+	return nil
+}
+
+// Run lists the objects to clean and cleans them after confirmation
+func (c *options) Run() error {
+	ctx := context.Background()
+
+	report, err := cleaner.Find(ctx, c.kubeClient, c.namespace, c.stuckAfter)
+	if err != nil {
+		return err
+	}
+
+	if len(report.Stuck) == 0 && len(report.LeftBehind) == 0 {
+		fmt.Fprintln(c.Out, "Nothing to clean")
+		return nil
+	}
+
+	if len(report.Stuck) > 0 {
+		fmt.Fprintln(c.Out, "Stuck terminating, the operator finalizers will be removed:")
+		for _, r := range report.Stuck {
+			fmt.Fprintf(c.Out, "  %s (%s, %s)\n", c.name(r), strings.Join(r.Finalizers, ", "), r.Reason)
+		}
+	}
+
+	if len(report.LeftBehind) > 0 {
+		if c.deleteLeftovers {
+			fmt.Fprintln(c.Out, "Left behind by deleted datacenters, will be deleted:")
+		} else {
+			fmt.Fprintln(c.Out, "Left behind by deleted datacenters, use --delete-leftovers to delete:")
+		}
+		for _, r := range report.LeftBehind {
+			fmt.Fprintf(c.Out, "  %s (datacenter %s)\n", c.name(r), r.Datacenter)
+		}
+	}
+
+	if len(report.Stuck) == 0 && !c.deleteLeftovers {
+		return nil
+	}
+
+	if c.outputFlags.DryRun == cmdutil.DryRunNone && !util.Confirm(c.In, c.Out, "Clean up the objects above?") {
+		fmt.Fprintln(c.Out, "Nothing cleaned")
+		return nil
+	}
+
+	// Finalizers first, so the stuck datacenters are gone before the resources they left behind
+	for _, r := range report.Stuck {
+		obj, err := cleaner.RemoveFinalizers(ctx, c.kubeClient, r, c.outputFlags.DryRun)
+		if err != nil {
+			return fmt.Errorf("unable to remove the finalizers of %s: %w", c.name(r), err)
+		}
+		if err := c.outputFlags.PrintObj(c.Out, c.kubeClient.Scheme(), obj, "finalizers removed"); err != nil {
+			return err
+		}
+	}
+
+	if c.deleteLeftovers {
+		for _, r := range report.LeftBehind {
+			if err := cleaner.Delete(ctx, c.kubeClient, r, c.outputFlags.DryRun); err != nil {
+				return fmt.Errorf("unable to delete %s: %w", c.name(r), err)
+			}
+			if err := c.outputFlags.PrintObj(c.Out, c.kubeClient.Scheme(), r.Object, "deleted"); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (c *options) name(r cleaner.Resource) string {
+	name := fmt.Sprintf("%s/%s", strings.ToLower(r.Kind), r.Name)
+	if c.allNamespaces {
+		name = fmt.Sprintf("%s %s", r.Namespace, name)
+	}
+	return name
+}
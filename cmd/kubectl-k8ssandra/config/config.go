@@ -28,8 +28,6 @@ func NewCmd(streams genericclioptions.IOStreams) *cobra.Command {
 
 	// Add subcommands
 	cmd.AddCommand(NewBuilderCmd(streams))
-	// TODO Add the idea of allowing to modify cassandra-yaml with interactive editor from the
-	// command line
 
 	o.configFlags.AddFlags(cmd.Flags())
 
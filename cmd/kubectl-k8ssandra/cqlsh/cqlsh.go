@@ -0,0 +1,289 @@
+package cqlsh
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	"github.com/k8ssandra/k8ssandra-client/pkg/cassdcutil"
+	"github.com/k8ssandra/k8ssandra-client/pkg/kubernetes"
+	"github.com/k8ssandra/k8ssandra-client/pkg/ui"
+	"github.com/k8ssandra/k8ssandra-client/pkg/util"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	cqlshExample = `
+	# launch an interactive cqlsh shell on a ready node of datacenter dc1
+	%[1]s cqlsh dc1
+
+	# launch an interactive cqlsh shell on a pod
+	%[1]s cqlsh cluster1-dc1-default-sts-0
+
+	# execute a statement and exit
+	%[1]s cqlsh dc1 -e "SELECT * FROM system.local"
+
+	# execute the statements of a local file
+	%[1]s cqlsh dc1 -f schema.cql
+
+	# pass additional arguments to cqlsh
+	%[1]s cqlsh dc1 -e "SELECT * FROM ks.table" -- --request-timeout=60
+	`
+
+	errNoTarget          = fmt.Errorf("no target pod or datacenter given")
+	errExecuteAndFile    = fmt.Errorf("--execute and --file can not be used together")
+	errTargetNotFound    = fmt.Errorf("no pod or CassandraDatacenter found")
+	errNoTemporaryFolder = fmt.Errorf("unable to create a temporary directory for cqlshrc in the container")
+)
+
+type options struct {
+	configFlags *genericclioptions.ConfigFlags
+	genericclioptions.IOStreams
+	namespace   string
+	target      string
+	execute     string
+	file        string
+	cqlshArgs   []string
+	kubeClient  client.Client
+	cassManager *cassdcutil.CassManager
+}
+
+func newOptions(streams genericclioptions.IOStreams) *options {
+	return &options{
+		configFlags: genericclioptions.NewConfigFlags(true),
+		IOStreams:   streams,
+	}
+}
+
+// NewCmd provides a cobra command launching cqlsh on a pod with the superuser credentials
+func NewCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	o := newOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:          "cqlsh [pod|datacenter] [flags] [-- cqlsh args]",
+		Short:        "cqlsh launched on a pod with the superuser credentials and client encryption settings of the datacenter",
+		Example:      fmt.Sprintf(cqlshExample, "kubectl k8ssandra"),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.Complete(c, args); err != nil {
+				return err
+			}
+			if err := o.Validate(); err != nil {
+				return err
+			}
+			if err := o.Run(); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	fl := cmd.Flags()
+	fl.StringVarP(&o.execute, "execute", "e", "", "execute the statement and exit")
+	fl.StringVarP(&o.file, "file", "f", "", "execute the statements of a local file and exit")
+	o.configFlags.AddFlags(fl)
+	return cmd
+}
+
+// Complete parses the arguments and necessary flags to options
+func (c *options) Complete(cmd *cobra.Command, args []string) error {
+	var err error
+
+	if len(args) < 1 || cmd.ArgsLenAtDash() == 0 {
+		return errNoTarget
+	}
+
+	c.target = args[0]
+	c.cqlshArgs = args[1:]
+
+	c.namespace, _, err = c.configFlags.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	restConfig, err := c.configFlags.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+
+	kubeClient, err := kubernetes.GetClientInNamespace(restConfig, c.namespace)
+	if err != nil {
+		return err
+	}
+
+	c.kubeClient = kubeClient
+	c.cassManager = cassdcutil.NewManager(kubeClient)
+
+	return nil
+}
+
+// Validate ensures that all required arguments and flag values are provided
+func (c *options) Validate() error {
+	if c.execute != "" && c.file != "" {
+		return errExecuteAndFile
+	}
+
+	return nil
+}
+
+// Run writes a cqlshrc with the credentials to a private directory in the container and launches cqlsh with it. The
+// credentials are passed through stdin of the exec, never as command line arguments.
+func (c *options) Run() error {
+	ctx := context.Background()
+
+	var script []byte
+	if c.file != "" {
+		var err error
+		script, err = os.ReadFile(c.file)
+		if err != nil {
+			return err
+		}
+	}
+
+	pod, dc, err := c.resolveTarget(ctx)
+	if err != nil {
+		return err
+	}
+
+	auth, err := c.cassManager.CassandraAuthDetails(ctx, dc)
+	if err != nil {
+		return err
+	}
+
+	if clientAuthRequired(dc) {
+		fmt.Fprintln(c.ErrOut, "Warning: the datacenter requires client certificates, which are not configured for cqlsh")
+	}
+
+	var out bytes.Buffer
+	if err := c.exec(pod, []string{"mktemp", "-d", "/tmp/k8ssandra-cqlsh.XXXXXX"}, nil, &out); err != nil {
+		return fmt.Errorf("%w: %v", errNoTemporaryFolder, err)
+	}
+	dir := strings.TrimSpace(out.String())
+	if !strings.HasPrefix(dir, "/tmp/k8ssandra-cqlsh.") {
+		return fmt.Errorf("%w: unexpected path %q", errNoTemporaryFolder, dir)
+	}
+	defer func() {
+		if err := c.exec(pod, []string{"rm", "-rf", dir}, nil, io.Discard); err != nil {
+			fmt.Fprintf(c.ErrOut, "Warning: unable to remove %s from pod %s: %v\n", dir, pod, err)
+		}
+	}()
+
+	files, err := newCqlshFiles(dc, auth, dir)
+	if err != nil {
+		return err
+	}
+
+	if script != nil {
+		files[scriptFile] = string(script)
+	}
+
+	for name, content := range files {
+		if err := c.exec(pod, []string{"sh", "-c", `umask 077 && cat > "$0"`, path.Join(dir, name)}, strings.NewReader(content), io.Discard); err != nil {
+			return fmt.Errorf("unable to write %s in pod %s: %w", name, pod, err)
+		}
+	}
+
+	if _, found := files[truststorePasswordFile]; found {
+		exportCA := []string{"sh", "-c", `umask 077 && keytool -list -rfc -keystore "$0" -storepass:file "$1" > "$2"`,
+			auth.TruststorePath, path.Join(dir, truststorePasswordFile), path.Join(dir, caCertFile)}
+		if err := c.exec(pod, exportCA, nil, io.Discard); err != nil {
+			return fmt.Errorf("unable to export the CA certificates of truststore %s: %w", auth.TruststorePath, err)
+		}
+	}
+
+	return c.cqlsh(pod, dir)
+}
+
+// cqlsh runs cqlsh in the pod, interactively unless a statement or script is given
+func (c *options) cqlsh(pod, dir string) error {
+	command := []string{"cqlsh", "--cqlshrc", path.Join(dir, cqlshrcFile)}
+	switch {
+	case c.execute != "":
+		command = append(command, "-e", c.execute)
+	case c.file != "":
+		command = append(command, "-f", path.Join(dir, scriptFile))
+	}
+	command = append(command, c.cqlshArgs...)
+
+	execOptions, err := util.GetExecOptions(c.IOStreams, c.configFlags)
+	if err != nil {
+		return err
+	}
+
+	execOptions.PodName = pod
+	execOptions.Command = command
+	if c.execute == "" && c.file == "" {
+		execOptions.Stdin = true
+		execOptions.TTY = ui.IsTerminal(c.In)
+	}
+
+	return execOptions.Run()
+}
+
+// exec runs a command in the cassandra container of the pod with the given stdin
+func (c *options) exec(pod string, command []string, in io.Reader, out io.Writer) error {
+	var errOut bytes.Buffer
+	streams := genericclioptions.IOStreams{In: in, Out: out, ErrOut: &errOut}
+
+	execOptions, err := util.GetExecOptions(streams, c.configFlags)
+	if err != nil {
+		return err
+	}
+
+	execOptions.PodName = pod
+	execOptions.Command = command
+	execOptions.Stdin = in != nil
+
+	if err := execOptions.Run(); err != nil {
+		if msg := strings.TrimSpace(errOut.String()); msg != "" {
+			return fmt.Errorf("%w: %s", err, msg)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// resolveTarget returns the pod to run cqlsh in and its datacenter. The target is either a pod, or a datacenter in
+// which case a ready pod of it is used.
+func (c *options) resolveTarget(ctx context.Context) (string, *cassdcapi.CassandraDatacenter, error) {
+	pod := &corev1.Pod{}
+	err := c.kubeClient.Get(ctx, types.NamespacedName{Name: c.target, Namespace: c.namespace}, pod)
+	if err == nil {
+		dc, err := c.cassManager.PodDatacenter(ctx, pod.Name, c.namespace)
+		if err != nil {
+			return "", nil, err
+		}
+		return pod.Name, dc, nil
+	}
+
+	if !apierrors.IsNotFound(err) {
+		return "", nil, err
+	}
+
+	dc, err := c.cassManager.CassandraDatacenter(ctx, c.target, c.namespace)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", nil, fmt.Errorf("%w: %s", errTargetNotFound, c.target)
+		}
+		return "", nil, err
+	}
+
+	ready, err := c.cassManager.ReadyPod(ctx, dc)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return ready.Name, dc, nil
+}
@@ -0,0 +1,96 @@
+package cqlsh
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	"github.com/k8ssandra/k8ssandra-client/pkg/cassdcutil"
+)
+
+const (
+	cqlshrcFile            = "cqlshrc"
+	credentialsFile        = "credentials"
+	truststorePasswordFile = "truststore-password"
+	caCertFile             = "ca.pem"
+	scriptFile             = "script.cql"
+)
+
+var errInvalidCredentials = fmt.Errorf("the superuser credentials can not be written to a cqlshrc file, they contain a line break")
+
+// credentialsFileVersion is the first Cassandra version whose cqlsh reads the password from a separate credentials
+// file, earlier versions read it from the cqlshrc
+var credentialsFileVersion = semver.MustParse("4.1.0")
+
+// cqlshFiles are the files written to a private directory in the container for cqlsh, keyed by their name
+type cqlshFiles map[string]string
+
+// newCqlshFiles creates the cqlshrc with the superuser credentials of the datacenter. With client encryption enabled,
+// cqlsh connects with SSL and validates the server certificates against the CA certificates of the truststore, which
+// are exported to dir as PEM since cqlsh can not read a Java keystore.
+func newCqlshFiles(dc *cassdcapi.CassandraDatacenter, auth *cassdcutil.CassandraAuth, dir string) (cqlshFiles, error) {
+	for _, value := range []string{auth.Username, auth.Password, auth.TruststorePassword} {
+		if strings.ContainsAny(value, "\r\n") {
+			return nil, errInvalidCredentials
+		}
+	}
+
+	files := make(cqlshFiles)
+
+	var rc strings.Builder
+	rc.WriteString("[authentication]\n")
+	fmt.Fprintf(&rc, "username = %s\n", auth.Username)
+	if usesCredentialsFile(dc) {
+		fmt.Fprintf(&rc, "credentials = %s\n", path.Join(dir, credentialsFile))
+		files[credentialsFile] = fmt.Sprintf("[PlainTextAuthProvider]\nusername = %s\npassword = %s\n", auth.Username, auth.Password)
+	} else {
+		fmt.Fprintf(&rc, "password = %s\n", auth.Password)
+	}
+
+	if cassdcutil.ClientEncryptionEnabled(dc) {
+		rc.WriteString("\n[connection]\nssl = true\n")
+		rc.WriteString("\n[ssl]\n")
+		if auth.TruststorePath != "" {
+			rc.WriteString("validate = true\n")
+			fmt.Fprintf(&rc, "certfile = %s\n", path.Join(dir, caCertFile))
+			files[truststorePasswordFile] = auth.TruststorePassword
+		} else {
+			rc.WriteString("validate = false\n")
+		}
+	}
+
+	files[cqlshrcFile] = rc.String()
+
+	return files, nil
+}
+
+// usesCredentialsFile checks if cqlsh of the datacenter's server version expects the password in a credentials file
+func usesCredentialsFile(dc *cassdcapi.CassandraDatacenter) bool {
+	if dc.Spec.ServerType != "" && dc.Spec.ServerType != "cassandra" {
+		return false
+	}
+
+	version, err := semver.NewVersion(dc.Spec.ServerVersion)
+	if err != nil {
+		return false
+	}
+
+	return !version.LessThan(credentialsFileVersion)
+}
+
+// clientAuthRequired checks if the nodes require a client certificate, which the generated cqlshrc does not configure
+func clientAuthRequired(dc *cassdcapi.CassandraDatacenter) bool {
+	required, found := cassdcutil.SubSectionOfCassYaml(dc, "client_encryption_options")["require_client_auth"]
+	if !found {
+		return false
+	}
+	switch value := required.Data().(type) {
+	case bool:
+		return value
+	case string:
+		return strings.EqualFold(value, "true")
+	}
+	return false
+}
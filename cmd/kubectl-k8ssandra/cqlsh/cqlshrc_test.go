@@ -0,0 +1,70 @@
+package cqlsh
+
+import (
+	"encoding/json"
+	"testing"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	"github.com/k8ssandra/k8ssandra-client/pkg/cassdcutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCqlshFilesPasswordInCqlshrc(t *testing.T) {
+	require := require.New(t)
+	dc := &cassdcapi.CassandraDatacenter{
+		Spec: cassdcapi.CassandraDatacenterSpec{ServerType: "cassandra", ServerVersion: "4.0.10"},
+	}
+
+	files, err := newCqlshFiles(dc, &cassdcutil.CassandraAuth{Username: "demo-superuser", Password: "secret"}, "/tmp/k8ssandra-cqlsh.abc")
+	require.NoError(err)
+	require.Equal(cqlshFiles{
+		cqlshrcFile: "[authentication]\nusername = demo-superuser\npassword = secret\n",
+	}, files)
+}
+
+func TestCqlshFilesCredentialsFile(t *testing.T) {
+	require := require.New(t)
+	dc := &cassdcapi.CassandraDatacenter{
+		Spec: cassdcapi.CassandraDatacenterSpec{ServerType: "cassandra", ServerVersion: "5.0.2"},
+	}
+
+	files, err := newCqlshFiles(dc, &cassdcutil.CassandraAuth{Username: "demo-superuser", Password: "secret"}, "/tmp/k8ssandra-cqlsh.abc")
+	require.NoError(err)
+	require.Equal("[authentication]\nusername = demo-superuser\ncredentials = /tmp/k8ssandra-cqlsh.abc/credentials\n", files[cqlshrcFile])
+	require.Equal("[PlainTextAuthProvider]\nusername = demo-superuser\npassword = secret\n", files[credentialsFile])
+	require.NotContains(files[cqlshrcFile], "secret")
+
+	dc.Spec.ServerType = "dse"
+	dc.Spec.ServerVersion = "6.8.50"
+	files, err = newCqlshFiles(dc, &cassdcutil.CassandraAuth{Username: "demo-superuser", Password: "secret"}, "/tmp/k8ssandra-cqlsh.abc")
+	require.NoError(err)
+	require.NotContains(files, credentialsFile)
+}
+
+func TestCqlshFilesClientEncryption(t *testing.T) {
+	require := require.New(t)
+	dc := &cassdcapi.CassandraDatacenter{
+		Spec: cassdcapi.CassandraDatacenterSpec{
+			ServerType:    "cassandra",
+			ServerVersion: "4.0.10",
+			Config:        json.RawMessage(`{"cassandra-yaml": {"client_encryption_options": {"enabled": true, "require_client_auth": "true"}}}`),
+		},
+	}
+	auth := &cassdcutil.CassandraAuth{
+		Username:           "demo-superuser",
+		Password:           "secret",
+		TruststorePath:     "/etc/encryption/truststore.jks",
+		TruststorePassword: "changeit",
+	}
+
+	files, err := newCqlshFiles(dc, auth, "/tmp/k8ssandra-cqlsh.abc")
+	require.NoError(err)
+	require.Contains(files[cqlshrcFile], "[connection]\nssl = true\n")
+	require.Contains(files[cqlshrcFile], "[ssl]\nvalidate = true\ncertfile = /tmp/k8ssandra-cqlsh.abc/ca.pem\n")
+	require.Equal("changeit", files[truststorePasswordFile])
+	require.True(clientAuthRequired(dc))
+
+	auth.Password = "multi\nline"
+	_, err = newCqlshFiles(dc, auth, "/tmp/k8ssandra-cqlsh.abc")
+	require.ErrorIs(err, errInvalidCredentials)
+}
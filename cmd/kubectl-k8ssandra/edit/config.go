@@ -0,0 +1,388 @@
+package edit
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	"github.com/k8ssandra/k8ssandra-client/pkg/cassdcutil"
+	"github.com/k8ssandra/k8ssandra-client/pkg/config"
+	"github.com/k8ssandra/k8ssandra-client/pkg/kubernetes"
+	"github.com/k8ssandra/k8ssandra-client/pkg/util"
+	k8ssandraapi "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/cassandra"
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/cmd/util/editor"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	configExample = `
+	# edit the cassandra.yaml and JVM settings of datacenter dc1
+	%[1]s config dc1
+
+	# apply the edited config without asking for confirmation
+	%[1]s config dc1 --yes
+
+	# use another editor than the one in KUBE_EDITOR or EDITOR
+	KUBE_EDITOR="nano" %[1]s config dc1
+	`
+
+	errNoDatacenterDefined = fmt.Errorf("no target datacenter given")
+	errEditUnchanged       = fmt.Errorf("the edited config is still invalid, edit cancelled")
+)
+
+// EditFunc opens the content in an editor and returns the edited content
+type EditFunc func(content []byte) ([]byte, error)
+
+type configOptions struct {
+	configFlags *genericclioptions.ConfigFlags
+	genericclioptions.IOStreams
+	namespace   string
+	dcName      string
+	yes         bool
+	edit        EditFunc
+	cassManager *cassdcutil.CassManager
+}
+
+func newConfigOptions(streams genericclioptions.IOStreams) *configOptions {
+	return &configOptions{
+		configFlags: genericclioptions.NewConfigFlags(true),
+		IOStreams:   streams,
+		edit:        launchEditor,
+	}
+}
+
+// NewConfigCmd provides a cobra command editing the config of a datacenter
+func NewConfigCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	o := newConfigOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:          "config [datacenter] [flags]",
+		Short:        "edit the cassandra.yaml and JVM settings of a datacenter, or of its K8ssandraCluster if it has one",
+		Example:      fmt.Sprintf(configExample, "kubectl k8ssandra edit"),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.Complete(c, args); err != nil {
+				return err
+			}
+			if err := o.Validate(); err != nil {
+				return err
+			}
+			if err := o.Run(); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	fl := cmd.Flags()
+	fl.BoolVarP(&o.yes, "yes", "y", false, "apply the edited config without asking for confirmation")
+	o.configFlags.AddFlags(fl)
+	return cmd
+}
+
+// Complete parses the arguments and necessary flags to options
+func (c *configOptions) Complete(cmd *cobra.Command, args []string) error {
+	var err error
+
+	if len(args) < 1 {
+		return errNoDatacenterDefined
+	}
+
+	c.dcName = args[0]
+
+	c.namespace, _, err = c.configFlags.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	restConfig, err := c.configFlags.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+
+	kubeClient, err := kubernetes.GetClient(restConfig)
+	if err != nil {
+		return err
+	}
+
+	c.cassManager = cassdcutil.NewManager(kubeClient)
+
+	return nil
+}
+
+// Validate ensures that all required arguments and flag values are provided
+func (c *configOptions) Validate() error {
+	return nil
+}
+
+// Run opens the config in an editor and stores it after showing the changes
+func (c *configOptions) Run() error {
+	ctx := context.Background()
+
+	dc, err := c.cassManager.CassandraDatacenter(ctx, c.dcName, c.namespace)
+	if err != nil {
+		return err
+	}
+
+	kc, err := c.cassManager.DatacenterCluster(ctx, dc)
+	if err != nil {
+		return err
+	}
+
+	var doc configDocument
+	if kc != nil {
+		doc = &clusterConfig{manager: c.cassManager, kc: kc, dcName: dc.Name}
+	} else {
+		doc = &datacenterConfig{manager: c.cassManager, dc: dc}
+	}
+
+	original, err := doc.Original()
+	if err != nil {
+		return err
+	}
+
+	edited, err := c.editUntilValid(doc, original)
+	if err != nil {
+		return err
+	}
+
+	if edited == nil || bytes.Equal(original, edited) {
+		fmt.Fprintln(c.Out, "Edit cancelled, no changes made.")
+		return nil
+	}
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(original)),
+		B:        difflib.SplitLines(string(edited)),
+		FromFile: "original",
+		ToFile:   "edited",
+		Context:  3,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Fprint(c.Out, diff)
+
+	if !c.yes && !util.Confirm(c.In, c.Out, fmt.Sprintf("Apply the changes to %s?", doc.Target())) {
+		fmt.Fprintln(c.Out, "Edit cancelled, no changes made.")
+		return nil
+	}
+
+	if err := doc.Apply(ctx, edited); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(c.Out, "%s edited\n", doc.Target())
+	return nil
+}
+
+// editUntilValid opens the editor until the result is valid, showing the validation error on top of the content. An
+// empty result cancels the edit and returns nil.
+func (c *configOptions) editUntilValid(doc configDocument, original []byte) ([]byte, error) {
+	content := original
+	var previous []byte
+	var validationErr error
+
+	for {
+		edited, err := c.edit(withHeader(doc, content, validationErr))
+		if err != nil {
+			return nil, err
+		}
+
+		edited = stripComments(edited)
+		if len(bytes.TrimSpace(edited)) == 0 {
+			return nil, nil
+		}
+
+		if validationErr != nil && bytes.Equal(edited, previous) {
+			return nil, fmt.Errorf("%w: %v", errEditUnchanged, validationErr)
+		}
+
+		normalized, err := doc.Normalize(edited)
+		if err == nil {
+			return normalized, nil
+		}
+
+		content, previous, validationErr = edited, edited, err
+	}
+}
+
+func withHeader(doc configDocument, content []byte, validationErr error) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# Please edit the config of %s below. Lines beginning with a '#' will be ignored,\n", doc.Target())
+	fmt.Fprintln(&buf, "# and an empty file will abort the edit.")
+	if validationErr != nil {
+		fmt.Fprintln(&buf, "#")
+		for _, line := range strings.Split(validationErr.Error(), "; ") {
+			fmt.Fprintf(&buf, "# %s\n", line)
+		}
+	}
+	fmt.Fprintln(&buf, "#")
+	buf.Write(content)
+	return buf.Bytes()
+}
+
+func stripComments(content []byte) []byte {
+	var buf bytes.Buffer
+	for _, line := range strings.SplitAfter(string(content), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		buf.WriteString(line)
+	}
+	return buf.Bytes()
+}
+
+func launchEditor(content []byte) ([]byte, error) {
+	e := editor.NewDefaultEditor([]string{"KUBE_EDITOR", "EDITOR"})
+	edited, file, err := e.LaunchTempFile("k8ssandra-edit-", ".yaml", bytes.NewReader(content))
+	if file != "" {
+		defer os.Remove(file)
+	}
+	return edited, err
+}
+
+// configDocument is the config being edited as YAML
+type configDocument interface {
+	// Target describes the resource the config is stored in
+	Target() string
+	// Original returns the current config
+	Original() ([]byte, error)
+	// Normalize validates the edited config and returns it in the same form as Original
+	Normalize(edited []byte) ([]byte, error)
+	// Apply stores the normalized config
+	Apply(ctx context.Context, edited []byte) error
+}
+
+// datacenterConfig is Spec.Config of a CassandraDatacenter, in the format cass-operator passes to the config builder
+type datacenterConfig struct {
+	manager *cassdcutil.CassManager
+	dc      *cassdcapi.CassandraDatacenter
+}
+
+func (d *datacenterConfig) Target() string {
+	return fmt.Sprintf("cassandradatacenter/%s", d.dc.Name)
+}
+
+func (d *datacenterConfig) Original() ([]byte, error) {
+	if len(d.dc.Spec.Config) == 0 {
+		return []byte{}, nil
+	}
+	return yaml.JSONToYAML(d.dc.Spec.Config)
+}
+
+func (d *datacenterConfig) Normalize(edited []byte) ([]byte, error) {
+	data, err := yaml.YAMLToJSON(edited)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := config.ValidateConfig(data); err != nil {
+		return nil, err
+	}
+
+	return yaml.JSONToYAML(data)
+}
+
+func (d *datacenterConfig) Apply(ctx context.Context, edited []byte) error {
+	data, err := yaml.YAMLToJSON(edited)
+	if err != nil {
+		return err
+	}
+
+	_, err = d.manager.SetConfig(ctx, d.dc, data, cmdutil.DryRunNone)
+	return err
+}
+
+// clusterConfig is the config of a datacenter in the K8ssandraCluster spec, which k8ssandra-operator renders to the
+// CassandraDatacenter
+type clusterConfig struct {
+	manager *cassdcutil.CassManager
+	kc      *k8ssandraapi.K8ssandraCluster
+	dcName  string
+}
+
+func (k *clusterConfig) Target() string {
+	return fmt.Sprintf("k8ssandracluster/%s (datacenter %s)", k.kc.Name, k.dcName)
+}
+
+func (k *clusterConfig) Original() ([]byte, error) {
+	cfg, err := cassdcutil.ClusterDatacenterConfig(k.kc, k.dcName)
+	if err != nil || cfg == nil {
+		return []byte{}, err
+	}
+	return yaml.Marshal(cfg)
+}
+
+func (k *clusterConfig) Normalize(edited []byte) ([]byte, error) {
+	cfg, err := k.parse(edited)
+	if err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(cfg)
+}
+
+func (k *clusterConfig) Apply(ctx context.Context, edited []byte) error {
+	cfg, err := k.parse(edited)
+	if err != nil {
+		return err
+	}
+
+	_, err = k.manager.SetClusterDatacenterConfig(ctx, k.kc, k.dcName, cfg, cmdutil.DryRunNone)
+	return err
+}
+
+// parse decodes the config rejecting unknown fields, the JVM options of the K8ssandraCluster are typed fields
+func (k *clusterConfig) parse(edited []byte) (*k8ssandraapi.CassandraConfig, error) {
+	cfg := &k8ssandraapi.CassandraConfig{}
+	if err := yaml.UnmarshalStrict(edited, cfg); err != nil {
+		return nil, err
+	}
+
+	if err := k.validate(cfg); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// validate renders the config to the sections of the CassandraDatacenter config the way k8ssandra-operator does,
+// merged with the config of the cluster, and validates the result like the config of a CassandraDatacenter
+func (k *clusterConfig) validate(cfg *k8ssandraapi.CassandraConfig) error {
+	if k.kc.Spec.Cassandra == nil {
+		return fmt.Errorf("k8ssandracluster/%s has no datacenters", k.kc.Name)
+	}
+
+	i := slices.IndexFunc(k.kc.Spec.Cassandra.Datacenters, func(dc k8ssandraapi.CassandraDatacenterTemplate) bool {
+		return dc.Meta.Name == k.dcName
+	})
+	if i < 0 {
+		return fmt.Errorf("datacenter %s is not part of k8ssandracluster/%s", k.dcName, k.kc.Name)
+	}
+
+	template := k.kc.Spec.Cassandra.Datacenters[i].DeepCopy()
+	template.CassandraConfig = cfg
+
+	dcConfig := cassandra.Coalesce(k.kc.CassClusterName(), k.kc.Spec.Cassandra, template)
+	if err := cassandra.ValidateDatacenterConfig(dcConfig); err != nil {
+		return err
+	}
+
+	dc, err := cassandra.NewDatacenter(client.ObjectKeyFromObject(k.kc), dcConfig)
+	if err != nil {
+		return err
+	}
+
+	return config.ValidateConfig(dc.Spec.Config)
+}
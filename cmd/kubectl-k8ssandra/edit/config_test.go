@@ -0,0 +1,129 @@
+package edit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	"github.com/k8ssandra/k8ssandra-client/pkg/cassdcutil"
+	k8ssandraapi "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func testOptions(t *testing.T, in string, edits ...string) (*configOptions, client.Client, *bytes.Buffer) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, cassdcapi.AddToScheme(scheme))
+
+	dc := &cassdcapi.CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{Name: "dc1", Namespace: "ns1"},
+		Spec: cassdcapi.CassandraDatacenterSpec{
+			Config: json.RawMessage(`{"jvm-server-options": {"max_heap_size": "512m"}}`),
+		},
+	}
+	kubeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(dc).Build()
+
+	streams, stdin, out, _ := genericclioptions.NewTestIOStreams()
+	stdin.WriteString(in)
+
+	o := newConfigOptions(streams)
+	o.namespace = "ns1"
+	o.dcName = "dc1"
+	o.cassManager = cassdcutil.NewManager(kubeClient)
+	o.edit = func(content []byte) ([]byte, error) {
+		require.NotEmpty(t, edits, "editor opened too many times")
+		require.True(t, strings.HasPrefix(string(content), "# Please edit the config of cassandradatacenter/dc1"))
+		edited := edits[0]
+		edits = edits[1:]
+		return []byte(edited), nil
+	}
+
+	return o, kubeClient, out
+}
+
+func storedConfig(t *testing.T, kubeClient client.Client) string {
+	dc := &cassdcapi.CassandraDatacenter{}
+	require.NoError(t, kubeClient.Get(context.TODO(), client.ObjectKey{Name: "dc1", Namespace: "ns1"}, dc))
+	return string(dc.Spec.Config)
+}
+
+func TestEditConfig(t *testing.T) {
+	require := require.New(t)
+	o, kubeClient, out := testOptions(t, "y\n", "jvm-server-options:\n  max_heap_size: 1g\n")
+
+	require.NoError(o.Run())
+	require.Contains(out.String(), "-  max_heap_size: 512m")
+	require.Contains(out.String(), "+  max_heap_size: 1g")
+	require.Contains(out.String(), "cassandradatacenter/dc1 edited")
+	require.JSONEq(`{"jvm-server-options": {"max_heap_size": "1g"}}`, storedConfig(t, kubeClient))
+}
+
+func TestEditConfigDeclined(t *testing.T) {
+	require := require.New(t)
+	o, kubeClient, out := testOptions(t, "n\n", "jvm-server-options:\n  max_heap_size: 1g\n")
+
+	require.NoError(o.Run())
+	require.Contains(out.String(), "Edit cancelled")
+	require.JSONEq(`{"jvm-server-options": {"max_heap_size": "512m"}}`, storedConfig(t, kubeClient))
+}
+
+func TestEditConfigInvalid(t *testing.T) {
+	require := require.New(t)
+	invalid := "jvm-server-options:\n  max_heap: 1g\n"
+	o, kubeClient, _ := testOptions(t, "", invalid, invalid)
+	o.yes = true
+
+	err := o.Run()
+	require.ErrorIs(err, errEditUnchanged)
+	require.ErrorContains(err, "jvm-server-options.max_heap is not a known option")
+	require.JSONEq(`{"jvm-server-options": {"max_heap_size": "512m"}}`, storedConfig(t, kubeClient))
+
+	o, kubeClient, _ = testOptions(t, "", invalid, "jvm-server-options:\n  max_heap_size: 1g\n")
+	o.yes = true
+
+	require.NoError(o.Run())
+	require.JSONEq(`{"jvm-server-options": {"max_heap_size": "1g"}}`, storedConfig(t, kubeClient))
+}
+
+func TestClusterConfigNormalize(t *testing.T) {
+	require := require.New(t)
+
+	kc := &k8ssandraapi.K8ssandraCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "ns1"},
+		Spec: k8ssandraapi.K8ssandraClusterSpec{
+			Cassandra: &k8ssandraapi.CassandraClusterTemplate{
+				ServerType: k8ssandraapi.ServerDistributionCassandra,
+				DatacenterOptions: k8ssandraapi.DatacenterOptions{
+					ServerVersion: "4.1.5",
+					StorageConfig: &cassdcapi.StorageConfig{},
+				},
+				Datacenters: []k8ssandraapi.CassandraDatacenterTemplate{
+					{Meta: k8ssandraapi.EmbeddedObjectMeta{Name: "dc1"}, Size: 3},
+				},
+			},
+		},
+	}
+	doc := &clusterConfig{kc: kc, dcName: "dc1"}
+
+	normalized, err := doc.Normalize([]byte("jvmOptions:\n  gc: G1GC\n"))
+	require.NoError(err)
+	require.Contains(string(normalized), "gc: G1GC")
+
+	// The typed field accepts any string, the rendered jvm11-server-options section does not
+	_, err = doc.Normalize([]byte("jvmOptions:\n  gc: NotAGC\n"))
+	require.ErrorContains(err, "jvm11-server-options.garbage_collector must be one of")
+
+	_, err = doc.Normalize([]byte("jvmOptions:\n  max_heap: 1g\n"))
+	require.Error(err)
+
+	doc.dcName = "dc2"
+	_, err = doc.Normalize([]byte("jvmOptions:\n  gc: G1GC\n"))
+	require.ErrorContains(err, "datacenter dc2 is not part of k8ssandracluster/demo")
+}
@@ -0,0 +1,36 @@
+package edit
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+type ClientOptions struct {
+	configFlags *genericclioptions.ConfigFlags
+	genericclioptions.IOStreams
+}
+
+// NewClientOptions provides an instance of ClientOptions with default values
+func NewClientOptions(streams genericclioptions.IOStreams) *ClientOptions {
+	return &ClientOptions{
+		configFlags: genericclioptions.NewConfigFlags(true),
+		IOStreams:   streams,
+	}
+}
+
+// NewCmd provides a cobra command wrapping ClientOptions
+func NewCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	o := NewClientOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:   "edit [subcommand] [flags]",
+		Short: "edit the settings of a datacenter with an interactive editor",
+	}
+
+	// Add subcommands
+	cmd.AddCommand(NewConfigCmd(streams))
+
+	o.configFlags.AddFlags(cmd.Flags())
+
+	return cmd
+}
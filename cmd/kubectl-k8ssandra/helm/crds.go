@@ -41,7 +41,7 @@ func newOptions(streams genericclioptions.IOStreams) *options {
 	}
 }
 
-// NewCmd provides a cobra command wrapping cqlShOptions
+// NewUpgradeCmd provides a cobra command upgrading the CRDs of a chart
 func NewUpgradeCmd(streams genericclioptions.IOStreams) *cobra.Command {
 	o := newOptions(streams)
 
@@ -115,7 +115,7 @@ func (c *options) Validate() error {
 	return nil
 }
 
-// Run removes the finalizers for a release X in the given namespace
+// Run upgrades the CRDs of the chart to the target version
 func (c *options) Run() error {
 	restConfig, err := c.configFlags.ToRESTConfig()
 	if err != nil {
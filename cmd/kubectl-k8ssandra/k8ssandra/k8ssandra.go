@@ -1,19 +1,23 @@
 package k8ssandra
 
 import (
-	// "github.com/k8ssandra/k8ssandra-client/cmd/kubectl-k8ssandra/cleaner"
-	// "github.com/k8ssandra/k8ssandra-client/cmd/kubectl-k8ssandra/cqlsh"
 	// "github.com/k8ssandra/k8ssandra-client/cmd/kubectl-k8ssandra/crds"
-	// "github.com/k8ssandra/k8ssandra-client/cmd/kubectl-k8ssandra/edit"
-	// "github.com/k8ssandra/k8ssandra-client/cmd/kubectl-k8ssandra/list"
-	// "github.com/k8ssandra/k8ssandra-client/cmd/kubectl-k8ssandra/migrate"
+	"github.com/k8ssandra/k8ssandra-client/cmd/kubectl-k8ssandra/backup"
+	"github.com/k8ssandra/k8ssandra-client/cmd/kubectl-k8ssandra/cleaner"
 	"github.com/k8ssandra/k8ssandra-client/cmd/kubectl-k8ssandra/config"
+	"github.com/k8ssandra/k8ssandra-client/cmd/kubectl-k8ssandra/cqlsh"
+	"github.com/k8ssandra/k8ssandra-client/cmd/kubectl-k8ssandra/edit"
 	"github.com/k8ssandra/k8ssandra-client/cmd/kubectl-k8ssandra/helm"
+	"github.com/k8ssandra/k8ssandra-client/cmd/kubectl-k8ssandra/list"
+	"github.com/k8ssandra/k8ssandra-client/cmd/kubectl-k8ssandra/migrate"
 	"github.com/k8ssandra/k8ssandra-client/cmd/kubectl-k8ssandra/nodetool"
 	"github.com/k8ssandra/k8ssandra-client/cmd/kubectl-k8ssandra/operate"
 	"github.com/k8ssandra/k8ssandra-client/cmd/kubectl-k8ssandra/register"
+	"github.com/k8ssandra/k8ssandra-client/cmd/kubectl-k8ssandra/repair"
+	"github.com/k8ssandra/k8ssandra-client/cmd/kubectl-k8ssandra/tasks"
 	"github.com/k8ssandra/k8ssandra-client/cmd/kubectl-k8ssandra/tools"
 	"github.com/k8ssandra/k8ssandra-client/cmd/kubectl-k8ssandra/users"
+	"github.com/k8ssandra/k8ssandra-client/cmd/kubectl-k8ssandra/wait"
 
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
@@ -41,18 +45,27 @@ func NewCmd(streams genericclioptions.IOStreams) *cobra.Command {
 	}
 
 	// Add subcommands
-	// cmd.AddCommand(cqlsh.NewCmd(streams))
-	// cmd.AddCommand(cleaner.NewCmd(streams))
-	// cmd.AddCommand(edit.NewCmd(streams))
+	cmd.AddCommand(cqlsh.NewCmd(streams))
+	cmd.AddCommand(cleaner.NewCmd(streams))
+	cmd.AddCommand(edit.NewCmd(streams))
+	cmd.AddCommand(migrate.NewCmd(streams))
 	cmd.AddCommand(operate.NewStartCmd(streams))
 	cmd.AddCommand(operate.NewRestartCmd(streams))
 	cmd.AddCommand(operate.NewStopCmd(streams))
-	// cmd.AddCommand(list.NewCmd(streams))
+	cmd.AddCommand(operate.NewScaleCmd(streams))
+	cmd.AddCommand(operate.NewUpgradeVersionCmd(streams))
+	cmd.AddCommand(list.NewCmd(streams))
+	cmd.AddCommand(list.NewStatusCmd(streams))
+	cmd.AddCommand(wait.NewCmd(streams))
 	cmd.AddCommand(users.NewCmd(streams))
 	cmd.AddCommand(config.NewCmd(streams))
 	cmd.AddCommand(helm.NewHelmCmd(streams))
 	cmd.AddCommand(nodetool.NewCmd(streams))
 	cmd.AddCommand(tools.NewToolsCmd(streams))
+	cmd.AddCommand(tasks.NewCmd(streams))
+	cmd.AddCommand(backup.NewCmd(streams))
+	cmd.AddCommand(backup.NewRestoreCmd(streams))
+	cmd.AddCommand(repair.NewCmd(streams))
 	register.SetupRegisterClusterCmd(cmd, streams)
 
 	// cmd.Flags().BoolVar(&o.listNamespaces, "list", o.listNamespaces, "if true, print the list of all namespaces in the current KUBECONFIG")
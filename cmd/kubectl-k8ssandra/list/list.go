@@ -0,0 +1,221 @@
+package list
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/k8ssandra/k8ssandra-client/pkg/cassdcutil"
+	"github.com/k8ssandra/k8ssandra-client/pkg/kubernetes"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+var (
+	listExample = `
+	# list the CassandraDatacenters and K8ssandraClusters of the current namespace
+	%[1]s list
+
+	# list them in every namespace
+	%[1]s list --all-namespaces
+	`
+
+	statusExample = `
+	# show the status, nodes and last task of datacenter dc1
+	%[1]s status dc1
+
+	# show the status of the datacenters of the K8ssandraCluster demo
+	%[1]s status demo
+	`
+
+	errNoName = fmt.Errorf("no datacenter or K8ssandraCluster name given")
+)
+
+type options struct {
+	configFlags *genericclioptions.ConfigFlags
+	genericclioptions.IOStreams
+	namespace     string
+	allNamespaces bool
+	name          string
+	cassManager   *cassdcutil.CassManager
+}
+
+func newOptions(streams genericclioptions.IOStreams) *options {
+	return &options{
+		configFlags: genericclioptions.NewConfigFlags(true),
+		IOStreams:   streams,
+	}
+}
+
+// NewCmd provides a cobra command listing the datacenters and clusters
+func NewCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	o := newOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:          "list [flags]",
+		Short:        "list CassandraDatacenters and K8ssandraClusters with their state",
+		Example:      fmt.Sprintf(listExample, "kubectl k8ssandra"),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.Complete(c, args); err != nil {
+				return err
+			}
+			if err := o.List(); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	fl := cmd.Flags()
+	fl.BoolVarP(&o.allNamespaces, "all-namespaces", "A", false, "list across all namespaces")
+	o.configFlags.AddFlags(fl)
+	return cmd
+}
+
+// NewStatusCmd provides a cobra command showing the detailed status of a datacenter or a cluster
+func NewStatusCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	o := newOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:          "status [datacenter|cluster]",
+		Short:        "show the status, nodes and last task of a CassandraDatacenter or the datacenters of a K8ssandraCluster",
+		Example:      fmt.Sprintf(statusExample, "kubectl k8ssandra"),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if len(args) < 1 {
+				return errNoName
+			}
+			o.name = args[0]
+
+			if err := o.Complete(c, args); err != nil {
+				return err
+			}
+			if err := o.Status(); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	o.configFlags.AddFlags(cmd.Flags())
+	return cmd
+}
+
+// Complete parses the arguments and necessary flags to options
+func (c *options) Complete(cmd *cobra.Command, args []string) error {
+	var err error
+
+	c.namespace, _, err = c.configFlags.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	if c.allNamespaces {
+		c.namespace = ""
+	}
+
+	restConfig, err := c.configFlags.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+
+	kubeClient, err := kubernetes.GetClient(restConfig)
+	if err != nil {
+		return err
+	}
+
+	c.cassManager = cassdcutil.NewManager(kubeClient)
+	return nil
+}
+
+// List prints the datacenters and the K8ssandraClusters
+func (c *options) List() error {
+	ctx := context.Background()
+
+	dcs, err := c.cassManager.DatacenterSummaries(ctx, c.namespace)
+	if err != nil {
+		return err
+	}
+
+	clusters, err := c.cassManager.ClusterSummaries(ctx, c.namespace)
+	if err != nil {
+		return err
+	}
+
+	if len(dcs) == 0 && len(clusters) == 0 {
+		fmt.Fprintln(c.ErrOut, "No datacenters or clusters found")
+		return nil
+	}
+
+	if len(dcs) > 0 {
+		if err := printDatacenterTable(c.Out, dcs, c.allNamespaces); err != nil {
+			return err
+		}
+	}
+
+	if len(clusters) > 0 {
+		if len(dcs) > 0 {
+			fmt.Fprintln(c.Out)
+		}
+		return printClusterTable(c.Out, clusters, c.allNamespaces)
+	}
+
+	return nil
+}
+
+// Status prints the details of the datacenter or, if there is no such datacenter, of the K8ssandraCluster and its
+// datacenters running in this Kubernetes cluster
+func (c *options) Status() error {
+	ctx := context.Background()
+
+	dc, err := c.cassManager.DatacenterSummary(ctx, c.name, c.namespace)
+	if err == nil {
+		return printDatacenterDetails(c.Out, dc)
+	}
+
+	if !errors.IsNotFound(err) {
+		return err
+	}
+
+	kc, errCluster := c.cassManager.K8ssandraCluster(ctx, c.name, c.namespace)
+	if errCluster != nil {
+		// Report the datacenter as missing
+		return err
+	}
+
+	cluster := cassdcutil.ClusterSummaryFrom(kc)
+	if err := printClusterDetails(c.Out, cluster); err != nil {
+		return err
+	}
+
+	if kc.Spec.Cassandra == nil {
+		return nil
+	}
+
+	for _, clusterDc := range kc.Spec.Cassandra.Datacenters {
+		// k8ssandra-operator names the CassandraDatacenter after the metadata, datacenterName only renames it in Cassandra
+		namespace := clusterDc.Meta.Namespace
+		if namespace == "" {
+			namespace = kc.Namespace
+		}
+
+		dc, err := c.cassManager.DatacenterSummary(ctx, clusterDc.Meta.Name, namespace)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				// The datacenter runs in another Kubernetes cluster or has not been created yet
+				continue
+			}
+			return err
+		}
+
+		fmt.Fprintln(c.Out)
+		if err := printDatacenterDetails(c.Out, dc); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package list
+
+import (
+	"testing"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	controlapi "github.com/k8ssandra/cass-operator/apis/control/v1alpha1"
+	"github.com/k8ssandra/k8ssandra-client/pkg/cassdcutil"
+	k8ssandrataskapi "github.com/k8ssandra/k8ssandra-operator/apis/control/v1alpha1"
+	k8ssandraapi "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestStatusClusterDatacenters(t *testing.T) {
+	require := require.New(t)
+
+	scheme := runtime.NewScheme()
+	require.NoError(clientgoscheme.AddToScheme(scheme))
+	require.NoError(cassdcapi.AddToScheme(scheme))
+	require.NoError(controlapi.AddToScheme(scheme))
+	require.NoError(k8ssandraapi.AddToScheme(scheme))
+	require.NoError(k8ssandrataskapi.AddToScheme(scheme))
+
+	kc := &k8ssandraapi.K8ssandraCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "ns1"},
+		Spec: k8ssandraapi.K8ssandraClusterSpec{
+			Cassandra: &k8ssandraapi.CassandraClusterTemplate{
+				Datacenters: []k8ssandraapi.CassandraDatacenterTemplate{
+					{
+						Meta:              k8ssandraapi.EmbeddedObjectMeta{Name: "dc1"},
+						DatacenterOptions: k8ssandraapi.DatacenterOptions{DatacenterName: "East"},
+						Size:              1,
+					},
+					{Meta: k8ssandraapi.EmbeddedObjectMeta{Name: "dc2", Namespace: "ns2"}, Size: 1},
+				},
+			},
+		},
+	}
+
+	dcs := []*cassdcapi.CassandraDatacenter{
+		{ObjectMeta: metav1.ObjectMeta{Name: "dc1", Namespace: "ns1"}, Spec: cassdcapi.CassandraDatacenterSpec{Size: 1, DatacenterName: "East"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "dc2", Namespace: "ns2"}, Spec: cassdcapi.CassandraDatacenterSpec{Size: 1}},
+	}
+
+	kubeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(kc, dcs[0], dcs[1]).Build()
+
+	streams, _, out, _ := genericiooptions.NewTestIOStreams()
+	o := newOptions(streams)
+	o.namespace = "ns1"
+	o.name = "demo"
+	o.cassManager = cassdcutil.NewManager(kubeClient)
+
+	require.NoError(o.Status())
+
+	// Both datacenters are found, the one renamed with datacenterName and the one in another namespace
+	require.Contains(out.String(), "Datacenter:   ns1/dc1")
+	require.Contains(out.String(), "Datacenter:   ns2/dc2")
+}
@@ -0,0 +1,160 @@
+package list
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/k8ssandra/k8ssandra-client/pkg/cassdcutil"
+	"github.com/k8ssandra/k8ssandra-client/pkg/tasks"
+)
+
+// printDatacenterTable writes one line per datacenter
+func printDatacenterTable(out io.Writer, dcs []*cassdcutil.DatacenterSummary, withNamespace bool) error {
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+
+	if withNamespace {
+		fmt.Fprint(w, "NAMESPACE\t")
+	}
+	fmt.Fprintln(w, "DATACENTER\tCLUSTER\tK8SSANDRACLUSTER\tVERSION\tIMAGE\tREADY\tSTOPPED\tCONDITIONS\tLAST TASK")
+
+	for _, dc := range dcs {
+		if withNamespace {
+			fmt.Fprintf(w, "%s\t", dc.Namespace)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d/%d\t%t\t%s\t%s\n",
+			dc.Name,
+			dc.Cluster,
+			orNone(dc.K8ssandraCluster),
+			serverVersion(dc.ServerType, dc.ServerVersion),
+			orNone(dc.Image),
+			dc.ReadyPods,
+			dc.Size,
+			dc.Stopped,
+			orNone(strings.Join(dc.Conditions, ",")),
+			taskString(dc.LastTask),
+		)
+	}
+
+	return w.Flush()
+}
+
+// printClusterTable writes one line per K8ssandraCluster
+func printClusterTable(out io.Writer, clusters []*cassdcutil.ClusterSummary, withNamespace bool) error {
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+
+	if withNamespace {
+		fmt.Fprint(w, "NAMESPACE\t")
+	}
+	fmt.Fprintln(w, "K8SSANDRACLUSTER\tCLUSTER\tVERSION\tIMAGE\tDATACENTERS\tCONDITIONS")
+
+	for _, cluster := range clusters {
+		if withNamespace {
+			fmt.Fprintf(w, "%s\t", cluster.Namespace)
+		}
+
+		dcs := make([]string, 0, len(cluster.Datacenters))
+		for _, dc := range cluster.Datacenters {
+			dcs = append(dcs, fmt.Sprintf("%s(%s)", dc.Name, clusterDatacenterState(dc)))
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			cluster.Name,
+			cluster.Cluster,
+			orNone(cluster.ServerVersion),
+			orNone(cluster.Image),
+			orNone(strings.Join(dcs, ",")),
+			orNone(strings.Join(cluster.Conditions, ",")),
+		)
+	}
+
+	return w.Flush()
+}
+
+// printDatacenterDetails writes the status of a single datacenter and its nodes
+func printDatacenterDetails(out io.Writer, dc *cassdcutil.DatacenterSummary) error {
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+
+	fmt.Fprintf(w, "Datacenter:\t%s/%s\n", dc.Namespace, dc.Name)
+	fmt.Fprintf(w, "Cluster:\t%s\n", dc.Cluster)
+	if dc.K8ssandraCluster != "" {
+		fmt.Fprintf(w, "K8ssandraCluster:\t%s\n", dc.K8ssandraCluster)
+	}
+	fmt.Fprintf(w, "Version:\t%s\n", serverVersion(dc.ServerType, dc.ServerVersion))
+	fmt.Fprintf(w, "Image:\t%s\n", orNone(dc.Image))
+	fmt.Fprintf(w, "Ready:\t%d/%d\n", dc.ReadyPods, dc.Size)
+	fmt.Fprintf(w, "Stopped:\t%t\n", dc.Stopped)
+	fmt.Fprintf(w, "Progress:\t%s\n", orNone(dc.Progress))
+	fmt.Fprintf(w, "Conditions:\t%s\n", orNone(strings.Join(dc.Conditions, ",")))
+	fmt.Fprintf(w, "Last task:\t%s\n", taskString(dc.LastTask))
+
+	if len(dc.Nodes) > 0 {
+		fmt.Fprintln(w, "\nPOD\tRACK\tIP\tHOST ID\tREADY")
+		for _, node := range dc.Nodes {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%t\n", node.Pod, orNone(node.Rack), orNone(node.IP), orNone(node.HostID), node.Ready)
+		}
+	}
+
+	return w.Flush()
+}
+
+// printClusterDetails writes the status of the K8ssandraCluster and the state of all its datacenters
+func printClusterDetails(out io.Writer, cluster *cassdcutil.ClusterSummary) error {
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+
+	fmt.Fprintf(w, "K8ssandraCluster:\t%s/%s\n", cluster.Namespace, cluster.Name)
+	fmt.Fprintf(w, "Cluster:\t%s\n", cluster.Cluster)
+	fmt.Fprintf(w, "Version:\t%s\n", orNone(cluster.ServerVersion))
+	fmt.Fprintf(w, "Image:\t%s\n", orNone(cluster.Image))
+	fmt.Fprintf(w, "Conditions:\t%s\n", orNone(strings.Join(cluster.Conditions, ",")))
+
+	if len(cluster.Datacenters) > 0 {
+		fmt.Fprintln(w, "\nDATACENTER\tSIZE\tNODES\tSTOPPED\tPROGRESS\tCONDITIONS")
+		for _, dc := range cluster.Datacenters {
+			fmt.Fprintf(w, "%s\t%d\t%d\t%t\t%s\t%s\n", dc.Name, dc.Size, dc.Nodes, dc.Stopped, orNone(dc.Progress), orNone(strings.Join(dc.Conditions, ",")))
+		}
+	}
+
+	return w.Flush()
+}
+
+func clusterDatacenterState(dc cassdcutil.ClusterDatacenterSummary) string {
+	if dc.Stopped {
+		return "Stopped"
+	}
+	return fmt.Sprintf("%d/%d", dc.Nodes, dc.Size)
+}
+
+// taskString describes the task with its command and state
+func taskString(s *tasks.Summary) string {
+	if s == nil {
+		return "<none>"
+	}
+
+	state := "Pending"
+	switch {
+	case s.Failed():
+		state = "Failed"
+	case s.Completed():
+		state = "Succeeded"
+	case s.Status.StartTime != nil:
+		state = "Running"
+	}
+
+	return fmt.Sprintf("%s (%s, %s)", s.Name, strings.Join(s.Commands, ","), state)
+}
+
+func serverVersion(serverType, version string) string {
+	if serverType == "" {
+		return orNone(version)
+	}
+	return fmt.Sprintf("%s %s", serverType, version)
+}
+
+func orNone(value string) string {
+	if value == "" {
+		return "<none>"
+	}
+	return value
+}
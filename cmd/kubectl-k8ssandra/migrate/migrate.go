@@ -0,0 +1,183 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	"github.com/k8ssandra/k8ssandra-client/pkg/cassdcutil"
+	"github.com/k8ssandra/k8ssandra-client/pkg/kubernetes"
+	"github.com/k8ssandra/k8ssandra-client/pkg/migrate"
+	"github.com/k8ssandra/k8ssandra-client/pkg/util"
+	k8ssandraapi "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	migrateExample = `
+	# adopt the standalone datacenter dc1 into a new K8ssandraCluster named after its cluster
+	%[1]s migrate dc1
+
+	# choose the name of the K8ssandraCluster
+	%[1]s migrate dc1 --name demo
+
+	# only print the generated K8ssandraCluster, or the changes it would make to the datacenter
+	%[1]s migrate dc1 --dry-run=client -o yaml
+	`
+
+	errNoDatacenterDefined = fmt.Errorf("no target datacenter given")
+	errAlreadyInCluster    = fmt.Errorf("the datacenter is already part of a K8ssandraCluster")
+	errSpecMismatch        = fmt.Errorf("the K8ssandraCluster would change the datacenter and restart its pods, nothing was created")
+)
+
+type options struct {
+	configFlags *genericclioptions.ConfigFlags
+	genericclioptions.IOStreams
+	namespace   string
+	dcName      string
+	name        string
+	yes         bool
+	outputFlags *util.OutputFlags
+	kubeClient  client.Client
+	cassManager *cassdcutil.CassManager
+}
+
+func newOptions(streams genericclioptions.IOStreams) *options {
+	return &options{
+		configFlags: genericclioptions.NewConfigFlags(true),
+		outputFlags: util.NewOutputFlags(),
+		IOStreams:   streams,
+	}
+}
+
+// NewCmd provides a cobra command adopting a standalone CassandraDatacenter into a K8ssandraCluster
+func NewCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	o := newOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:          "migrate [datacenter] [flags]",
+		Short:        "create a K8ssandraCluster adopting a standalone CassandraDatacenter without restarting it",
+		Example:      fmt.Sprintf(migrateExample, "kubectl k8ssandra"),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.Complete(c, args); err != nil {
+				return err
+			}
+			if err := o.Validate(); err != nil {
+				return err
+			}
+			if err := o.Run(); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	fl := cmd.Flags()
+	fl.StringVar(&o.name, "name", "", "name of the K8ssandraCluster, defaults to the cluster name of the datacenter")
+	fl.BoolVarP(&o.yes, "yes", "y", false, "create the K8ssandraCluster without asking for confirmation")
+	o.outputFlags.AddFlags(cmd)
+	o.configFlags.AddFlags(fl)
+	return cmd
+}
+
+// Complete parses the arguments and necessary flags to options
+func (c *options) Complete(cmd *cobra.Command, args []string) error {
+	var err error
+
+	if len(args) < 1 {
+		return errNoDatacenterDefined
+	}
+
+	c.dcName = args[0]
+
+	if err := c.outputFlags.Complete(cmd); err != nil {
+		return err
+	}
+
+	c.namespace, _, err = c.configFlags.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	restConfig, err := c.configFlags.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+
+	c.kubeClient, err = kubernetes.GetClient(restConfig)
+	if err != nil {
+		return err
+	}
+
+	c.cassManager = cassdcutil.NewManager(c.kubeClient)
+
+	return nil
+}
+
+// Validate ensures that all required arguments and flag values are provided
+func (c *options) Validate() error {
+	return nil
+}
+
+// Run generates the K8ssandraCluster, verifies it renders the same datacenter and creates it after confirmation
+func (c *options) Run() error {
+	ctx := context.Background()
+
+	dc, err := c.cassManager.CassandraDatacenter(ctx, c.dcName, c.namespace)
+	if err != nil {
+		return err
+	}
+
+	if name, found := dc.Labels[k8ssandraapi.K8ssandraClusterNameLabel]; found {
+		return fmt.Errorf("%w: %s", errAlreadyInCluster, name)
+	}
+
+	name := c.name
+	if name == "" {
+		name = cassdcapi.CleanupForKubernetes(dc.Spec.ClusterName)
+	}
+
+	kc, err := migrate.NewCluster(dc, name)
+	if err != nil {
+		return err
+	}
+
+	rendered, err := migrate.RenderDatacenter(kc)
+	if err != nil {
+		return err
+	}
+
+	differences, err := migrate.Compare(rendered, dc)
+	if err != nil {
+		return err
+	}
+
+	if len(differences) > 0 {
+		fmt.Fprintf(c.Out, "The K8ssandraCluster would change these fields of cassandradatacenter/%s:\n", dc.Name)
+		for _, d := range differences {
+			fmt.Fprintf(c.Out, "  %s\n", d)
+		}
+		return errSpecMismatch
+	}
+
+	switch c.outputFlags.DryRun {
+	case cmdutil.DryRunNone:
+		if !c.yes && !util.Confirm(c.In, c.Out, fmt.Sprintf("Create k8ssandracluster/%s adopting cassandradatacenter/%s?", kc.Name, dc.Name)) {
+			fmt.Fprintln(c.Out, "Nothing created")
+			return nil
+		}
+		err = c.kubeClient.Create(ctx, kc)
+	case cmdutil.DryRunServer:
+		err = c.kubeClient.Create(ctx, kc, client.DryRunAll)
+	}
+	if err != nil {
+		return err
+	}
+
+	return c.outputFlags.PrintObj(c.Out, c.kubeClient.Scheme(), kc, fmt.Sprintf("created, adopting cassandradatacenter/%s", dc.Name))
+}
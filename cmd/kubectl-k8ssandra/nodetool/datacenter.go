@@ -0,0 +1,190 @@
+package nodetool
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"text/tabwriter"
+
+	utilexec "k8s.io/client-go/util/exec"
+)
+
+var errPodsFailed = errors.New("nodetool failed")
+
+// podResult is the outcome of the nodetool command on a single pod
+type podResult struct {
+	pod      string
+	exitCode int
+	// err is set if the command could not be run in the pod at all
+	err     error
+	skipped bool
+}
+
+func (r podResult) failed() bool {
+	return r.skipped || r.err != nil || r.exitCode != 0
+}
+
+// podExec runs the command in the pod, writing its stdout and stderr to out
+type podExec func(ctx context.Context, pod string, out io.Writer) error
+
+// runOnPods runs the command on every pod, at most parallel pods at a time. With rolling the pods are run one at a
+// time in the given order and the remaining pods are skipped after the first failure. The output of each pod is
+// written to out as a block once the pod has finished, or line by line prefixed with the pod name if prefix is set.
+func runOnPods(ctx context.Context, pods []string, parallel int, rolling, prefix bool, out io.Writer, exec podExec) []podResult {
+	if rolling {
+		parallel = 1
+	}
+
+	results := make([]podResult, len(pods))
+	outLock := &sync.Mutex{}
+
+	run := func(i int) {
+		pod := pods[i]
+		results[i] = podResult{pod: pod}
+
+		var podOut io.Writer
+		var buffer *bytes.Buffer
+		if prefix {
+			pw := &prefixWriter{prefix: pod + ": ", out: out, lock: outLock}
+			defer pw.flush()
+			podOut = pw
+		} else {
+			buffer = &bytes.Buffer{}
+			podOut = &syncWriter{out: buffer}
+		}
+
+		err := exec(ctx, pod, podOut)
+
+		var exitErr utilexec.ExitError
+		if errors.As(err, &exitErr) {
+			results[i].exitCode = exitErr.ExitStatus()
+		} else if err != nil {
+			results[i].err = err
+		}
+
+		if buffer != nil {
+			outLock.Lock()
+			defer outLock.Unlock()
+			fmt.Fprintf(out, "==> %s <==\n", pod)
+			_, _ = out.Write(buffer.Bytes())
+			if buffer.Len() > 0 && buffer.Bytes()[buffer.Len()-1] != '\n' {
+				fmt.Fprintln(out)
+			}
+			if results[i].err != nil {
+				fmt.Fprintf(out, "error: %v\n", results[i].err)
+			}
+		}
+	}
+
+	if parallel <= 1 {
+		for i := range pods {
+			if rolling && i > 0 && results[i-1].failed() {
+				for j := i; j < len(pods); j++ {
+					results[j] = podResult{pod: pods[j], skipped: true}
+				}
+				break
+			}
+			run(i)
+		}
+		return results
+	}
+
+	wg := sync.WaitGroup{}
+	slots := make(chan struct{}, parallel)
+	for i := range pods {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			run(i)
+		}(i)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// printSummary writes the exit code of every pod and returns an error if the command failed on any of them
+func printSummary(out io.Writer, results []podResult) error {
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "\nPOD\tEXIT CODE")
+
+	failed := 0
+	for _, r := range results {
+		status := fmt.Sprintf("%d", r.exitCode)
+		switch {
+		case r.skipped:
+			status = "<skipped>"
+		case r.err != nil:
+			status = fmt.Sprintf("<error: %v>", r.err)
+		}
+		if r.failed() {
+			failed++
+		}
+		fmt.Fprintf(w, "%s\t%s\n", r.pod, status)
+	}
+
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%w on %d of %d pods", errPodsFailed, failed, len(results))
+	}
+
+	return nil
+}
+
+// syncWriter serializes the writes of the stdout and stderr streams of an exec
+type syncWriter struct {
+	lock sync.Mutex
+	out  io.Writer
+}
+
+func (w *syncWriter) Write(p []byte) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.out.Write(p)
+}
+
+// prefixWriter writes complete lines to out with the prefix, holding the lock shared with the writers of other pods
+type prefixWriter struct {
+	prefix  string
+	out     io.Writer
+	lock    *sync.Mutex
+	partial []byte
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.partial = append(w.partial, p...)
+	for {
+		i := bytes.IndexByte(w.partial, '\n')
+		if i < 0 {
+			break
+		}
+		if _, err := fmt.Fprintf(w.out, "%s%s", w.prefix, w.partial[:i+1]); err != nil {
+			return 0, err
+		}
+		w.partial = w.partial[i+1:]
+	}
+
+	return len(p), nil
+}
+
+// flush writes the last line if it did not end with a newline
+func (w *prefixWriter) flush() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if len(w.partial) > 0 {
+		fmt.Fprintf(w.out, "%s%s\n", w.prefix, w.partial)
+		w.partial = nil
+	}
+}
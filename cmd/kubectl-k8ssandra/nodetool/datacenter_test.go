@@ -0,0 +1,119 @@
+package nodetool
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	utilexec "k8s.io/client-go/util/exec"
+)
+
+func TestRunOnPodsGrouped(t *testing.T) {
+	require := require.New(t)
+	out := &bytes.Buffer{}
+
+	results := runOnPods(context.TODO(), []string{"pod-0", "pod-1"}, 1, false, false, out, func(ctx context.Context, pod string, out io.Writer) error {
+		fmt.Fprintf(out, "status of %s\nUN", pod)
+		return nil
+	})
+
+	require.Len(results, 2)
+	require.False(results[0].failed())
+	require.False(results[1].failed())
+	require.Equal("==> pod-0 <==\nstatus of pod-0\nUN\n==> pod-1 <==\nstatus of pod-1\nUN\n", out.String())
+	require.NoError(printSummary(out, results))
+}
+
+func TestRunOnPodsPrefixed(t *testing.T) {
+	require := require.New(t)
+	out := &bytes.Buffer{}
+
+	results := runOnPods(context.TODO(), []string{"pod-0", "pod-1"}, 1, false, true, out, func(ctx context.Context, pod string, out io.Writer) error {
+		fmt.Fprint(out, "first\nsec")
+		fmt.Fprint(out, "ond")
+		return nil
+	})
+
+	require.Len(results, 2)
+	require.Equal("pod-0: first\npod-0: second\npod-1: first\npod-1: second\n", out.String())
+}
+
+func TestRunOnPodsExitCodes(t *testing.T) {
+	require := require.New(t)
+	out := &bytes.Buffer{}
+
+	results := runOnPods(context.TODO(), []string{"pod-0", "pod-1", "pod-2"}, 1, false, false, out, func(ctx context.Context, pod string, out io.Writer) error {
+		switch pod {
+		case "pod-1":
+			return utilexec.CodeExitError{Err: errors.New("command terminated with exit code 2"), Code: 2}
+		case "pod-2":
+			return errors.New("pod not running")
+		}
+		return nil
+	})
+
+	require.Equal(0, results[0].exitCode)
+	require.Equal(2, results[1].exitCode)
+	require.NoError(results[1].err)
+	require.Error(results[2].err)
+	require.Contains(out.String(), "error: pod not running")
+
+	summary := &bytes.Buffer{}
+	err := printSummary(summary, results)
+	require.ErrorIs(err, errPodsFailed)
+	require.EqualError(err, "nodetool failed on 2 of 3 pods")
+	require.Contains(summary.String(), "pod-1   2")
+	require.Contains(summary.String(), "pod-2   <error: pod not running>")
+}
+
+func TestRunOnPodsRollingStopsAtFailure(t *testing.T) {
+	require := require.New(t)
+	executed := []string{}
+
+	results := runOnPods(context.TODO(), []string{"pod-0", "pod-1", "pod-2"}, 3, true, false, io.Discard, func(ctx context.Context, pod string, out io.Writer) error {
+		executed = append(executed, pod)
+		if pod == "pod-1" {
+			return utilexec.CodeExitError{Err: errors.New("failed"), Code: 1}
+		}
+		return nil
+	})
+
+	require.Equal([]string{"pod-0", "pod-1"}, executed)
+	require.True(results[2].skipped)
+
+	summary := &bytes.Buffer{}
+	require.EqualError(printSummary(summary, results), "nodetool failed on 2 of 3 pods")
+	require.Contains(summary.String(), "pod-2   <skipped>")
+}
+
+func TestRunOnPodsParallel(t *testing.T) {
+	require := require.New(t)
+	var running, maxRunning atomic.Int32
+
+	pods := []string{"pod-0", "pod-1", "pod-2", "pod-3", "pod-4"}
+	results := runOnPods(context.TODO(), pods, 2, false, false, io.Discard, func(ctx context.Context, pod string, out io.Writer) error {
+		current := running.Add(1)
+		defer running.Add(-1)
+		for {
+			highest := maxRunning.Load()
+			if current <= highest || maxRunning.CompareAndSwap(highest, current) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	})
+
+	require.Len(results, len(pods))
+	for i, r := range results {
+		require.Equal(pods[i], r.pod)
+		require.False(r.failed())
+	}
+	require.LessOrEqual(maxRunning.Load(), int32(2))
+}
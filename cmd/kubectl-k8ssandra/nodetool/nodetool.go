@@ -3,7 +3,10 @@ package nodetool
 import (
 	"context"
 	"fmt"
+	"io"
+	"sort"
 
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
 	"github.com/k8ssandra/k8ssandra-client/pkg/cassdcutil"
 	"github.com/k8ssandra/k8ssandra-client/pkg/kubernetes"
 	"github.com/k8ssandra/k8ssandra-client/pkg/util"
@@ -13,11 +16,30 @@ import (
 )
 
 var (
-	cqlshExample = `
-	# launch a interactive cqlsh shell on node
+	nodetoolExample = `
+	# run a nodetool command on a node
 	%[1]s nodetool <pod> <command> [<args>]
+
+	# run a nodetool command on every node of a datacenter
+	%[1]s nodetool --dc <dc> <command> [<args>]
+
+	# show the status of the cluster as seen by a node
+	%[1]s nodetool cluster1-dc1-default-sts-0 status
+
+	# show the table statistics of every node of datacenter dc1, nodetool flags go after --
+	%[1]s nodetool --dc dc1 -- tablestats -H ks1
+
+	# clear the snapshots of rack r1, four nodes at a time
+	%[1]s nodetool --dc dc1 --rack r1 --parallel 4 -- clearsnapshot --all
+
+	# flush the nodes one at a time, stopping at the first failure
+	%[1]s nodetool --dc dc1 --rolling flush
 `
 	errNotEnoughParameters = fmt.Errorf("not enough parameters to run nodetool")
+	errDatacenterRequired  = fmt.Errorf("--rack, --parallel, --rolling and --prefix require --dc")
+	errParallelRolling     = fmt.Errorf("--parallel and --rolling can not be used together")
+	errInvalidParallel     = fmt.Errorf("--parallel must be at least 1")
+	errNoPods              = fmt.Errorf("no pods found")
 )
 
 type options struct {
@@ -26,6 +48,11 @@ type options struct {
 	execOptions *exec.ExecOptions
 	cassManager *cassdcutil.CassManager
 	params      []string
+	dcName      string
+	rackName    string
+	parallel    int
+	rolling     bool
+	prefix      bool
 }
 
 func newOptions(streams genericclioptions.IOStreams) *options {
@@ -35,14 +62,14 @@ func newOptions(streams genericclioptions.IOStreams) *options {
 	}
 }
 
-// NewCmd provides a cobra command wrapping cqlShOptions
+// NewCmd provides a cobra command wrapping options
 func NewCmd(streams genericclioptions.IOStreams) *cobra.Command {
 	o := newOptions(streams)
 
 	cmd := &cobra.Command{
-		Use:          "nodetool [pod] [flags]",
-		Short:        "nodetool launched on pod",
-		Example:      fmt.Sprintf(cqlshExample, "kubectl k8ssandra"),
+		Use:          "nodetool [pod] [command] [flags]",
+		Short:        "nodetool launched on a pod or on every pod of a datacenter",
+		Example:      fmt.Sprintf(nodetoolExample, "kubectl k8ssandra"),
 		SilenceUsage: true,
 		RunE: func(c *cobra.Command, args []string) error {
 			if err := o.Complete(c, args); err != nil {
@@ -59,7 +86,13 @@ func NewCmd(streams genericclioptions.IOStreams) *cobra.Command {
 		},
 	}
 
-	o.configFlags.AddFlags(cmd.Flags())
+	fl := cmd.Flags()
+	fl.StringVar(&o.dcName, "dc", "", "run the command on every pod of the CassandraDatacenter instead of a single pod")
+	fl.StringVar(&o.rackName, "rack", "", "run the command only on the pods of the given rack, requires --dc")
+	fl.IntVar(&o.parallel, "parallel", 1, "how many pods to run the command on at the same time, requires --dc")
+	fl.BoolVar(&o.rolling, "rolling", false, "run the command one pod at a time and stop at the first failure, requires --dc")
+	fl.BoolVar(&o.prefix, "prefix", false, "prefix every output line with the pod name instead of grouping the output per pod, requires --dc")
+	o.configFlags.AddFlags(fl)
 	return cmd
 }
 
@@ -67,7 +100,7 @@ func NewCmd(streams genericclioptions.IOStreams) *cobra.Command {
 func (c *options) Complete(cmd *cobra.Command, args []string) error {
 	var err error
 
-	if len(args) < 2 {
+	if (c.dcName == "" && len(args) < 2) || len(args) < 1 {
 		return errNotEnoughParameters
 	}
 
@@ -76,7 +109,11 @@ func (c *options) Complete(cmd *cobra.Command, args []string) error {
 		return err
 	}
 	c.execOptions = execOptions
-	execOptions.PodName = args[0]
+
+	if c.dcName == "" {
+		execOptions.PodName = args[0]
+		args = args[1:]
+	}
 
 	restConfig, err := c.configFlags.ToRESTConfig()
 	if err != nil {
@@ -90,7 +127,15 @@ func (c *options) Complete(cmd *cobra.Command, args []string) error {
 
 	c.cassManager = cassdcutil.NewManager(kubeClient)
 
-	c.params = args[1:]
+	c.params = args
+
+	if c.dcName == "" && (c.rackName != "" || cmd.Flags().Changed("parallel") || c.rolling || c.prefix) {
+		return errDatacenterRequired
+	}
+
+	if cmd.Flags().Changed("parallel") && c.rolling {
+		return errParallelRolling
+	}
 
 	return nil
 }
@@ -99,6 +144,10 @@ func (c *options) Complete(cmd *cobra.Command, args []string) error {
 func (c *options) Validate() error {
 	// We could validate here if a nodetool command requires flags, but lets let nodetool throw that error
 
+	if c.parallel < 1 {
+		return errInvalidParallel
+	}
+
 	return nil
 }
 
@@ -106,6 +155,10 @@ func (c *options) Validate() error {
 func (c *options) Run() error {
 	ctx := context.Background()
 
+	if c.dcName != "" {
+		return c.runDatacenter(ctx)
+	}
+
 	dc, err := c.cassManager.PodDatacenter(ctx, c.execOptions.PodName, c.execOptions.Namespace)
 	if err != nil {
 		return err
@@ -124,6 +177,50 @@ func (c *options) Run() error {
 	return c.execOptions.Run()
 }
 
+// runDatacenter runs the nodetool command on the pods of the datacenter and prints a summary of the exit codes
+func (c *options) runDatacenter(ctx context.Context) error {
+	dc, err := c.cassManager.CassandraDatacenter(ctx, c.dcName, c.execOptions.Namespace)
+	if err != nil {
+		return err
+	}
+
+	cassSecret, err := c.cassManager.CassandraAuthDetails(ctx, dc)
+	if err != nil {
+		return err
+	}
+
+	podList, err := c.cassManager.CassandraDatacenterPods(ctx, dc)
+	if err != nil {
+		return err
+	}
+
+	pods := make([]string, 0, len(podList.Items))
+	for _, pod := range podList.Items {
+		if c.rackName == "" || pod.Labels[cassdcapi.RackLabel] == c.rackName {
+			pods = append(pods, pod.Name)
+		}
+	}
+	sort.Strings(pods)
+
+	if len(pods) == 0 {
+		return fmt.Errorf("%w in cassandradatacenter/%s", errNoPods, dc.Name)
+	}
+
+	command := append([]string{"nodetool"}, nodetoolAuthParameters(cassSecret)...)
+	command = append(command, c.params...)
+
+	results := runOnPods(ctx, pods, c.parallel, c.rolling, c.prefix, c.Out, func(ctx context.Context, pod string, out io.Writer) error {
+		execOptions := *c.execOptions
+		execOptions.Namespace = dc.Namespace
+		execOptions.PodName = pod
+		execOptions.Command = command
+		execOptions.IOStreams = genericclioptions.IOStreams{Out: out, ErrOut: out}
+		return execOptions.Run()
+	})
+
+	return printSummary(c.Out, results)
+}
+
 func nodetoolAuthParameters(authDetails *cassdcutil.CassandraAuth) []string {
 	auth := []string{"--username", authDetails.Username, "--password", authDetails.Password}
 
@@ -3,37 +3,85 @@ package operate
 import (
 	"context"
 	"fmt"
+	"io"
+	"strings"
+	"time"
 
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
 	"github.com/k8ssandra/k8ssandra-client/pkg/cassdcutil"
 	"github.com/k8ssandra/k8ssandra-client/pkg/kubernetes"
+	"github.com/k8ssandra/k8ssandra-client/pkg/restart"
+	"github.com/k8ssandra/k8ssandra-client/pkg/tasks"
+	"github.com/k8ssandra/k8ssandra-client/pkg/ui"
+	"github.com/k8ssandra/k8ssandra-client/pkg/util"
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 var (
 	startExample = `
 	# start an existing datacenter that was stopped
 	%[1]s start <datacenter>
+
+	# start every datacenter of the K8ssandraCluster demo, one at a time in the order of the spec
+	%[1]s start --k8ssandra-cluster demo
 	`
 
 	stopExample = `
 	# shutdown an existing datacenter
 	%[1]s stop <datacenter>
 
-	# shutdown an existing datacenter and wait for all the pods to shutdown
-	%[1]s stop <datacenter> --wait
+	# shutdown an existing datacenter and wait up to 30 minutes for all the pods to shutdown
+	%[1]s stop <datacenter> --wait --timeout 30m
+
+	# shutdown an existing datacenter and follow the state of its pods while waiting
+	%[1]s stop <datacenter> --wait --progress
+
+	# show the modified datacenter without changing it
+	%[1]s stop <datacenter> --dry-run=client -o yaml
+
+	# stop every datacenter of the K8ssandraCluster demo, one at a time in the reverse order of the spec
+	%[1]s stop --k8ssandra-cluster demo
 	`
 
 	restartExample = `
 	# request a rolling restart for datacenter
 	%[1]s restart <datacenter>
 
+	# request a rolling restart and follow the state of the pods and their restart jobs until it completes
+	%[1]s restart <datacenter> --wait --progress
+
 	# request a rolling restart of a single rack called r1
 	%[1]s restart <datacenter> --rack r1
+
+	# validate the restart task on the server without creating it
+	%[1]s restart <datacenter> --dry-run=server -o yaml
+
+	# request a rolling restart of every datacenter of the K8ssandraCluster demo, one datacenter at a time
+	%[1]s restart --k8ssandra-cluster demo --wait
+
+	# restart only datacenters dc2 and dc1 of the K8ssandraCluster demo, dc2 first
+	%[1]s restart --k8ssandra-cluster demo --dc dc2,dc1
+
+	# restart a single canary pod first and continue rack by rack only while the nodes come back healthy
+	%[1]s restart <datacenter> --canary
+
+	# use pod dc1-r1-sts-0 as the canary
+	%[1]s restart <datacenter> --canary --canary-pod dc1-r1-sts-0
 	`
 
-	errNoDatacenterDefined = fmt.Errorf("no target datacenter given")
-	errRestartingStopped   = fmt.Errorf("unable to do rolling restart to a stopped datacenter")
+	errNoDatacenterDefined  = fmt.Errorf("no target datacenter given")
+	errRestartingStopped    = fmt.Errorf("unable to do rolling restart to a stopped datacenter")
+	errClusterAndDatacenter = fmt.Errorf("datacenter argument can not be used with --k8ssandra-cluster, use --dc to select the datacenters")
+	errDatacentersNoCluster = fmt.Errorf("--dc requires --k8ssandra-cluster")
+	errUnknownDatacenter    = fmt.Errorf("datacenter is not part of the K8ssandraCluster")
+	errNoClusterDatacenters = fmt.Errorf("K8ssandraCluster has no datacenters")
+	errCanaryOptions        = fmt.Errorf("--canary restarts a single datacenter and can not be used with --k8ssandra-cluster, --rack or --dry-run")
+	errCanaryPodNoCanary    = fmt.Errorf("--canary-pod requires --canary")
+	errProgressOptions      = fmt.Errorf("--progress requires --wait and can not be used with --k8ssandra-cluster, --canary or --dry-run")
 )
 
 type options struct {
@@ -41,14 +89,23 @@ type options struct {
 	genericclioptions.IOStreams
 	namespace   string
 	dcName      string
+	clusterName string
+	datacenters []string
 	rackName    string
+	canary      bool
+	canaryPod   string
 	wait        bool
+	progress    bool
+	timeout     time.Duration
+	outputFlags *util.OutputFlags
+	kubeClient  client.Client
 	cassManager *cassdcutil.CassManager
 }
 
 func newOptions(streams genericclioptions.IOStreams) *options {
 	return &options{
 		configFlags: genericclioptions.NewConfigFlags(true),
+		outputFlags: util.NewOutputFlags(),
 		IOStreams:   streams,
 	}
 }
@@ -79,6 +136,11 @@ func NewStartCmd(streams genericclioptions.IOStreams) *cobra.Command {
 	fl := cmd.Flags()
 	fl.BoolVarP(&o.wait, "wait", "w", false, "wait until all pods have started")
 	fl.StringVar(&o.rackName, "rack", "", "restart only target rack")
+	_ = fl.MarkDeprecated("rack", "start always starts the whole datacenter, the flag has no effect")
+	fl.BoolVar(&o.progress, "progress", false, "show the state of every pod while waiting, live on a terminal and as log lines otherwise")
+	fl.DurationVar(&o.timeout, "timeout", 10*time.Minute, "how long to wait for the datacenter, or each datacenter of the cluster, to start")
+	fl.StringVar(&o.clusterName, "k8ssandra-cluster", "", "start every datacenter of the K8ssandraCluster in order, waiting for each one")
+	o.outputFlags.AddFlags(cmd)
 	o.configFlags.AddFlags(fl)
 	return cmd
 }
@@ -87,7 +149,7 @@ func NewRestartCmd(streams genericclioptions.IOStreams) *cobra.Command {
 	o := newOptions(streams)
 
 	cmd := &cobra.Command{
-		Use:          "restart [cluster]",
+		Use:          "restart [datacenter]",
 		Short:        "request rolling restart for an existing running Cassandra cluster",
 		Example:      fmt.Sprintf(restartExample, "kubectl k8ssandra"),
 		SilenceUsage: true,
@@ -108,6 +170,14 @@ func NewRestartCmd(streams genericclioptions.IOStreams) *cobra.Command {
 
 	fl := cmd.Flags()
 	fl.BoolVarP(&o.wait, "wait", "w", false, "wait until all pods have restarted")
+	fl.BoolVar(&o.progress, "progress", false, "show the state of every pod while waiting, live on a terminal and as log lines otherwise")
+	fl.DurationVar(&o.timeout, "timeout", 10*time.Minute, "how long to wait for the restart to complete, with --canary for each pod or rack")
+	fl.StringVar(&o.rackName, "rack", "", "restart only target rack")
+	fl.StringVar(&o.clusterName, "k8ssandra-cluster", "", "restart the datacenters of the K8ssandraCluster, one datacenter at a time")
+	fl.StringSliceVar(&o.datacenters, "dc", []string{}, "datacenters of the K8ssandraCluster to restart in the given order, defaults to all")
+	fl.BoolVar(&o.canary, "canary", false, "restart one pod first and continue rack by rack only after the management API reports all nodes UP and in schema agreement")
+	fl.StringVar(&o.canaryPod, "canary-pod", "", "pod to restart as the canary, defaults to the first pod of the first rack")
+	o.outputFlags.AddFlags(cmd)
 	o.configFlags.AddFlags(fl)
 	return cmd
 }
@@ -137,6 +207,10 @@ func NewStopCmd(streams genericclioptions.IOStreams) *cobra.Command {
 
 	fl := cmd.Flags()
 	fl.BoolVarP(&o.wait, "wait", "w", false, "wait until all pods have terminated")
+	fl.BoolVar(&o.progress, "progress", false, "show the state of every pod while waiting, live on a terminal and as log lines otherwise")
+	fl.DurationVar(&o.timeout, "timeout", 10*time.Minute, "how long to wait for the datacenter, or each datacenter of the cluster, to stop")
+	fl.StringVar(&o.clusterName, "k8ssandra-cluster", "", "stop every datacenter of the K8ssandraCluster in order, waiting for each one")
+	o.outputFlags.AddFlags(cmd)
 	o.configFlags.AddFlags(fl)
 	return cmd
 }
@@ -145,11 +219,25 @@ func NewStopCmd(streams genericclioptions.IOStreams) *cobra.Command {
 func (c *options) Complete(cmd *cobra.Command, args []string) error {
 	var err error
 
-	if len(args) < 1 {
-		return errNoDatacenterDefined
+	if c.clusterName != "" {
+		if len(args) > 0 {
+			return errClusterAndDatacenter
+		}
+	} else {
+		if len(c.datacenters) > 0 {
+			return errDatacentersNoCluster
+		}
+
+		if len(args) < 1 {
+			return errNoDatacenterDefined
+		}
+
+		c.dcName = args[0]
 	}
 
-	c.dcName = args[0]
+	if err := c.outputFlags.Complete(cmd); err != nil {
+		return err
+	}
 
 	c.namespace, _, err = c.configFlags.ToRawKubeConfigLoader().Namespace()
 	if err != nil {
@@ -166,13 +254,33 @@ func (c *options) Complete(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	c.cassManager = cassdcutil.NewManager(kubeClient)
+	// The manager waits using watches, which the namespaced client does not support
+	watchClient, err := kubernetes.GetWatchClient(restConfig)
+	if err != nil {
+		return err
+	}
+
+	c.kubeClient = kubeClient
+	c.cassManager = cassdcutil.NewManager(watchClient)
 
 	return nil
 }
 
 // Validate ensures that all required arguments and flag values are provided
 func (c *options) Validate() error {
+	if err := c.validateProgress(); err != nil {
+		return err
+	}
+
+	if c.clusterName != "" {
+		kc, err := c.cassManager.K8ssandraCluster(context.Background(), c.clusterName, c.namespace)
+		if err != nil {
+			return err
+		}
+		_, err = cassdcutil.ClusterStoppedStateOrder(kc, false)
+		return err
+	}
+
 	// Verify target cluster exists
 	_, err := c.cassManager.CassandraDatacenter(context.Background(), c.dcName, c.namespace)
 	if err != nil {
@@ -184,6 +292,22 @@ func (c *options) Validate() error {
 
 // ValidateRestart ensures that all required arguments and flag values are provided
 func (c *options) ValidateRestart() error {
+	if err := c.validateProgress(); err != nil {
+		return err
+	}
+
+	if c.canaryPod != "" && !c.canary {
+		return errCanaryPodNoCanary
+	}
+
+	if c.canary && (c.clusterName != "" || c.rackName != "" || c.outputFlags.DryRun != cmdutil.DryRunNone) {
+		return errCanaryOptions
+	}
+
+	if c.clusterName != "" {
+		return c.validateClusterRestart()
+	}
+
 	// Verify target cluster exists
 	dc, err := c.cassManager.CassandraDatacenter(context.Background(), c.dcName, c.namespace)
 	if err != nil {
@@ -196,12 +320,220 @@ func (c *options) ValidateRestart() error {
 	return nil
 }
 
-// Run either stops or starts the existing datacenter
+// validateProgress verifies the progress view is only requested when waiting for a single datacenter
+func (c *options) validateProgress() error {
+	if c.progress && (!c.wait || c.clusterName != "" || c.canary || c.outputFlags.DryRun != cmdutil.DryRunNone) {
+		return errProgressOptions
+	}
+	return nil
+}
+
+// validateClusterRestart verifies the K8ssandraCluster exists and none of the target datacenters is stopped
+func (c *options) validateClusterRestart() error {
+	kc, err := c.cassManager.K8ssandraCluster(context.Background(), c.clusterName, c.namespace)
+	if err != nil {
+		return err
+	}
+
+	if kc.Spec.Cassandra == nil || len(kc.Spec.Cassandra.Datacenters) == 0 {
+		return errNoClusterDatacenters
+	}
+
+	stopped := make(map[string]bool, len(kc.Spec.Cassandra.Datacenters))
+	for _, dc := range kc.Spec.Cassandra.Datacenters {
+		stopped[dc.Meta.Name] = dc.Stopped
+	}
+
+	targets := c.datacenters
+	if len(targets) == 0 {
+		targets = make([]string, 0, len(stopped))
+		for dc := range stopped {
+			targets = append(targets, dc)
+		}
+	}
+
+	for _, dc := range targets {
+		isStopped, found := stopped[dc]
+		if !found {
+			return fmt.Errorf("%w: %s", errUnknownDatacenter, dc)
+		}
+		if isStopped {
+			return fmt.Errorf("%w: %s", errRestartingStopped, dc)
+		}
+	}
+
+	return nil
+}
+
+// Run either stops or starts the existing datacenter or the datacenters of the K8ssandraCluster
 func (c *options) Run(stop bool) error {
-	return c.cassManager.ModifyStoppedState(context.Background(), c.dcName, c.namespace, stop, c.wait)
+	ctx := context.Background()
+
+	if c.clusterName != "" {
+		return c.runCluster(ctx, stop)
+	}
+
+	cassdc, err := c.cassManager.SetStoppedState(ctx, c.dcName, c.namespace, stop, c.outputFlags.DryRun)
+	if err != nil {
+		return err
+	}
+
+	operation := "started"
+	if stop {
+		operation = "stopped"
+	}
+
+	if err := c.outputFlags.PrintObj(c.Out, c.kubeClient.Scheme(), cassdc, operation); err != nil {
+		return err
+	}
+
+	if c.wait && c.outputFlags.DryRun == cmdutil.DryRunNone {
+		wait := func(ctx context.Context) error {
+			return c.cassManager.WaitForStoppedState(ctx, cassdc, stop, c.timeout)
+		}
+
+		if c.progress {
+			return c.progressView(cassdc, nil, fmt.Sprintf("Waiting for datacenter %s to be %s", cassdc.Name, operation)).Run(ctx, wait)
+		}
+
+		return wait(ctx)
+	}
+
+	return nil
 }
 
-// Restart creates a restart task for the cluster
+// runCluster stops or starts the datacenters of the K8ssandraCluster one at a time, waiting for each of them
+func (c *options) runCluster(ctx context.Context, stop bool) error {
+	operation, progress := "started", "starting"
+	if stop {
+		operation, progress = "stopped", "stopping"
+	}
+
+	if c.outputFlags.DryRun != cmdutil.DryRunNone {
+		kc, err := c.cassManager.SetClusterStoppedState(ctx, c.clusterName, c.namespace, stop, c.outputFlags.DryRun)
+		if err != nil {
+			return err
+		}
+		return c.outputFlags.PrintObj(c.Out, c.kubeClient.Scheme(), kc, operation)
+	}
+
+	if err := c.cassManager.ModifyClusterStoppedState(ctx, c.clusterName, c.namespace, stop, c.timeout, func(dc string) {
+		fmt.Fprintf(c.Out, "%s datacenter %s\n", progress, dc)
+	}); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(c.Out, "k8ssandracluster/%s %s\n", c.clusterName, operation)
+	return nil
+}
+
+// Restart creates a restart task for the datacenter, or a single K8ssandraTask restarting the datacenters of the
+// K8ssandraCluster one at a time
 func (c *options) Restart() error {
-	return c.cassManager.RestartDc(context.Background(), c.dcName, c.namespace, c.rackName, c.wait)
+	ctx := context.Background()
+	wait := c.wait && c.outputFlags.DryRun == cmdutil.DryRunNone
+
+	if c.canary {
+		return c.canaryRestart(ctx)
+	}
+
+	if c.clusterName != "" {
+		task, err := tasks.CreateClusterDatacentersRestartTask(ctx, c.kubeClient, c.namespace, c.clusterName, c.datacenters, c.rackName, tasks.WithDryRun(c.outputFlags.DryRun))
+		if err != nil {
+			return err
+		}
+
+		if err := c.outputFlags.PrintObj(c.Out, c.kubeClient.Scheme(), task, "created"); err != nil {
+			return err
+		}
+
+		if wait {
+			return tasks.WaitForClusterCompletion(ctx, c.kubeClient, task, tasks.WithTimeout(c.timeout))
+		}
+
+		return nil
+	}
+
+	task, err := c.cassManager.RestartDc(ctx, c.dcName, c.namespace, c.rackName, false, tasks.WithDryRun(c.outputFlags.DryRun))
+	if err != nil {
+		return err
+	}
+
+	if err := c.outputFlags.PrintObj(c.Out, c.kubeClient.Scheme(), task, "created"); err != nil {
+		return err
+	}
+
+	if wait {
+		waitTask := func(ctx context.Context) error {
+			return tasks.WaitForCompletion(ctx, c.kubeClient, task, tasks.WithTimeout(c.timeout))
+		}
+
+		if c.progress {
+			dc, err := c.cassManager.CassandraDatacenter(ctx, c.dcName, c.namespace)
+			if err != nil {
+				return err
+			}
+			taskKey := client.ObjectKeyFromObject(task)
+			return c.progressView(dc, &taskKey, fmt.Sprintf("Waiting for task %s to restart datacenter %s", task.Name, dc.Name)).Run(ctx, waitTask)
+		}
+
+		return waitTask(ctx)
+	}
+
+	return nil
+}
+
+// progressView shows the pods of the datacenter, and the job state of the task if set, while waiting
+func (c *options) progressView(dc *cassdcapi.CassandraDatacenter, task *types.NamespacedName, title string) *ui.ProgressView {
+	return ui.NewProgressView(c.Out, title, func(ctx context.Context) ([]cassdcutil.PodProgress, error) {
+		return c.cassManager.DatacenterProgress(ctx, dc, task)
+	})
+}
+
+// canaryRestart restarts the canary pod and then the datacenter rack by rack, printing the progress of each step
+func (c *options) canaryRestart(ctx context.Context) error {
+	dc, err := c.cassManager.CassandraDatacenter(ctx, c.dcName, c.namespace)
+	if err != nil {
+		return err
+	}
+
+	report, err := restart.CanaryRestart(ctx, c.kubeClient, dc, restart.CanaryOptions{
+		CanaryPod: c.canaryPod,
+		Timeout:   c.timeout,
+		OnStep: func(step *restart.Step) {
+			switch {
+			case !step.Finished:
+				fmt.Fprintf(c.Out, "%s (%s)\n", step.Name, strings.Join(step.Pods, ", "))
+			case step.Err == nil:
+				fmt.Fprintf(c.Out, "%s: done\n", step.Name)
+			}
+		},
+	})
+	if err != nil {
+		if report != nil {
+			printCanaryReport(c.ErrOut, report)
+		}
+		return err
+	}
+
+	fmt.Fprintf(c.Out, "cassandradatacenter/%s restarted\n", dc.Name)
+	return nil
+}
+
+// printCanaryReport describes which steps completed and why the failed step stopped the restart
+func printCanaryReport(out io.Writer, report *restart.Report) {
+	failed := report.Failed()
+	if failed == nil {
+		return
+	}
+
+	fmt.Fprintln(out, "Restart stopped:")
+	for _, step := range report.Steps {
+		if step.Err != nil {
+			fmt.Fprintf(out, "  FAILED     %s (%s): %v\n", step.Name, strings.Join(step.Pods, ", "), step.Err)
+			continue
+		}
+		fmt.Fprintf(out, "  COMPLETED  %s (%s)\n", step.Name, strings.Join(step.Pods, ", "))
+	}
+	fmt.Fprintln(out, "The remaining racks were not restarted.")
 }
@@ -0,0 +1,44 @@
+package operate
+
+import (
+	"testing"
+
+	"github.com/k8ssandra/k8ssandra-client/pkg/cassdcutil"
+	k8ssandraapi "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestValidateClusterRestartWithoutCassandra(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, k8ssandraapi.AddToScheme(scheme))
+
+	kc := &k8ssandraapi.K8ssandraCluster{ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "ns1"}}
+	kubeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(kc).Build()
+
+	o := newOptions(genericiooptions.NewTestIOStreamsDiscard())
+	o.namespace = "ns1"
+	o.clusterName = "demo"
+	o.cassManager = cassdcutil.NewManager(kubeClient)
+
+	require.ErrorIs(t, o.validateClusterRestart(), errNoClusterDatacenters)
+}
+
+func TestStartDeprecatedRackFlag(t *testing.T) {
+	cmd := NewStartCmd(genericiooptions.NewTestIOStreamsDiscard())
+	require.NotNil(t, cmd.Flags().Lookup("rack"))
+	require.NotEmpty(t, cmd.Flags().Lookup("rack").Deprecated)
+}
+
+func TestClusterFlags(t *testing.T) {
+	// The K8ssandraCluster flag must not replace the kubeconfig --cluster flag
+	streams := genericiooptions.NewTestIOStreamsDiscard()
+	for _, cmd := range []*cobra.Command{NewStartCmd(streams), NewStopCmd(streams), NewRestartCmd(streams)} {
+		require.NotNil(t, cmd.Flags().Lookup("cluster"), cmd.Name())
+		require.NotNil(t, cmd.Flags().Lookup("k8ssandra-cluster"), cmd.Name())
+	}
+}
@@ -0,0 +1,305 @@
+package operate
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	"github.com/k8ssandra/k8ssandra-client/pkg/cassdcutil"
+	"github.com/k8ssandra/k8ssandra-client/pkg/kubernetes"
+	"github.com/k8ssandra/k8ssandra-client/pkg/mgmtapi"
+	"github.com/k8ssandra/k8ssandra-client/pkg/scheduler"
+	"github.com/k8ssandra/k8ssandra-client/pkg/tasks"
+	"github.com/k8ssandra/k8ssandra-client/pkg/util"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	scaleExample = `
+	# grow datacenter dc1 to 6 nodes if the new pods fit the Kubernetes cluster
+	%[1]s scale dc1 --size 6
+
+	# grow the datacenter, wait for the new nodes to be ready and run cleanup on the datacenter afterwards
+	%[1]s scale dc1 --size 6 --wait --cleanup
+
+	# grow the datacenter even if the scheduling check fails, for example when the cluster autoscaler adds nodes
+	%[1]s scale dc1 --size 6 --force
+
+	# show the modified datacenter without changing it
+	%[1]s scale dc1 --size 6 --dry-run=client -o yaml
+
+	# shrink datacenter dc1 to 3 nodes if no keyspace is replicated to more than 3 nodes in it, wait for the
+	# decommissions to finish and list the volume claims left behind
+	%[1]s scale dc1 --size 3 --wait
+	`
+
+	errInvalidSize         = fmt.Errorf("--size must be higher than 0")
+	errSizeSmallerThanRack = fmt.Errorf("size can not be smaller than the number of racks")
+	errScalingStopped      = fmt.Errorf("unable to scale a stopped datacenter")
+	errUnschedulable       = fmt.Errorf("the new pods will not fit the Kubernetes cluster, use --force to scale anyway")
+	errCleanupScaleDown    = fmt.Errorf("--cleanup can only be used when adding nodes")
+)
+
+type scaleOptions struct {
+	configFlags *genericclioptions.ConfigFlags
+	genericclioptions.IOStreams
+	namespace   string
+	dcName      string
+	size        int32
+	force       bool
+	wait        bool
+	cleanup     bool
+	timeout     time.Duration
+	outputFlags *util.OutputFlags
+	kubeClient  client.Client
+	cassManager *cassdcutil.CassManager
+	dc          *cassdcapi.CassandraDatacenter
+}
+
+func newScaleOptions(streams genericclioptions.IOStreams) *scaleOptions {
+	return &scaleOptions{
+		configFlags: genericclioptions.NewConfigFlags(true),
+		outputFlags: util.NewOutputFlags(),
+		IOStreams:   streams,
+	}
+}
+
+// NewScaleCmd provides a cobra command changing the size of a datacenter
+func NewScaleCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	o := newScaleOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:          "scale [datacenter] --size [nodes]",
+		Short:        "change the number of nodes of a datacenter after checking the new pods can be scheduled or, when shrinking, that no keyspace is replicated to more nodes than remain",
+		Example:      fmt.Sprintf(scaleExample, "kubectl k8ssandra"),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.Complete(c, args); err != nil {
+				return err
+			}
+			if err := o.Validate(); err != nil {
+				return err
+			}
+			if err := o.Run(); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	fl := cmd.Flags()
+	fl.Int32Var(&o.size, "size", 0, "new number of nodes in the datacenter")
+	fl.BoolVar(&o.force, "force", false, "scale even if the new pods do not fit the Kubernetes cluster")
+	fl.BoolVarP(&o.wait, "wait", "w", false, "wait until all the pods of the datacenter are ready")
+	fl.BoolVar(&o.cleanup, "cleanup", false, "run cleanup on the datacenter once the new nodes are ready, implies --wait")
+	fl.DurationVar(&o.timeout, "timeout", 30*time.Minute, "how long to wait for the datacenter and the cleanup")
+	if err := cmd.MarkFlagRequired("size"); err != nil {
+		panic(err)
+	}
+	o.outputFlags.AddFlags(cmd)
+	o.configFlags.AddFlags(fl)
+	return cmd
+}
+
+// Complete parses the arguments and necessary flags to options
+func (c *scaleOptions) Complete(cmd *cobra.Command, args []string) error {
+	var err error
+
+	if len(args) < 1 {
+		return errNoDatacenterDefined
+	}
+
+	c.dcName = args[0]
+
+	if err := c.outputFlags.Complete(cmd); err != nil {
+		return err
+	}
+
+	c.namespace, _, err = c.configFlags.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	restConfig, err := c.configFlags.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+
+	kubeClient, err := kubernetes.GetClientInNamespace(restConfig, c.namespace)
+	if err != nil {
+		return err
+	}
+
+	c.kubeClient = kubeClient
+	c.cassManager = cassdcutil.NewManager(kubeClient)
+
+	return nil
+}
+
+// Validate ensures that all required arguments and flag values are provided
+func (c *scaleOptions) Validate() error {
+	if c.size <= 0 {
+		return errInvalidSize
+	}
+
+	dc, err := c.cassManager.CassandraDatacenter(context.Background(), c.dcName, c.namespace)
+	if err != nil {
+		return err
+	}
+
+	if dc.Spec.Stopped {
+		return errScalingStopped
+	}
+
+	if int(c.size) < len(dc.GetRacks()) {
+		return fmt.Errorf("%w: %d racks", errSizeSmallerThanRack, len(dc.GetRacks()))
+	}
+
+	if c.cleanup && c.size <= dc.Spec.Size {
+		return errCleanupScaleDown
+	}
+
+	c.dc = dc
+	return nil
+}
+
+// Run checks the added pods fit the cluster, updates the datacenter size and optionally waits and runs cleanup
+func (c *scaleOptions) Run() error {
+	ctx := context.Background()
+
+	shrink := c.size < c.dc.Spec.Size
+	if c.size > c.dc.Spec.Size {
+		if err := c.preflight(ctx); err != nil {
+			return err
+		}
+	}
+
+	if shrink {
+		if err := c.checkReplication(ctx); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(c.Out, "cass-operator will decommission, in order: %s\n", strings.Join(cassdcutil.DecommissionedPods(c.dc, int(c.size)), ", "))
+	}
+
+	cassdc, err := c.setSize(ctx)
+	if err != nil {
+		return err
+	}
+
+	if c.outputFlags.DryRun != cmdutil.DryRunNone || !(c.wait || c.cleanup) {
+		return nil
+	}
+
+	if err := c.cassManager.WaitForSize(ctx, cassdc, c.timeout); err != nil {
+		return fmt.Errorf("datacenter %s did not reach %d ready nodes: %w", c.dcName, c.size, err)
+	}
+
+	if shrink {
+		return c.printOrphanedPVCs(ctx, cassdc)
+	}
+
+	if !c.cleanup {
+		return nil
+	}
+
+	task, err := tasks.CreateCleanupTask(ctx, c.kubeClient, cassdc, "", "")
+	if err != nil {
+		return err
+	}
+
+	if err := c.outputFlags.PrintObj(c.Out, c.kubeClient.Scheme(), task, "created"); err != nil {
+		return err
+	}
+
+	return tasks.WaitForCompletion(ctx, c.kubeClient, task, tasks.WithTimeout(c.timeout))
+}
+
+// setSize changes the size in the K8ssandraCluster owning the datacenter, or in the CassandraDatacenter if it is
+// standalone, and returns the datacenter with the new size
+func (c *scaleOptions) setSize(ctx context.Context) (*cassdcapi.CassandraDatacenter, error) {
+	kc, err := c.cassManager.DatacenterCluster(ctx, c.dc)
+	if err != nil {
+		return nil, err
+	}
+
+	if kc == nil {
+		cassdc, err := c.cassManager.SetSize(ctx, c.dcName, c.namespace, c.size, c.outputFlags.DryRun)
+		if err != nil {
+			return nil, err
+		}
+		return cassdc, c.outputFlags.PrintObj(c.Out, c.kubeClient.Scheme(), cassdc, "scaled")
+	}
+
+	kc, err = c.cassManager.SetClusterDatacenterSize(ctx, kc, c.dc.Name, c.size, c.outputFlags.DryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.outputFlags.PrintObj(c.Out, c.kubeClient.Scheme(), kc, fmt.Sprintf("datacenter %s scaled", c.dc.Name)); err != nil {
+		return nil, err
+	}
+
+	cassdc := c.dc.DeepCopy()
+	cassdc.Spec.Size = c.size
+	return cassdc, nil
+}
+
+// checkReplication refuses to shrink the datacenter below the replication factor of any keyspace replicated to it
+func (c *scaleOptions) checkReplication(ctx context.Context) error {
+	pod, err := c.cassManager.ReadyPod(ctx, c.dc)
+	if err != nil {
+		return fmt.Errorf("unable to read the keyspace replication: %w", err)
+	}
+
+	mgmtClient, err := mgmtapi.NewManagementClient(ctx, c.kubeClient, c.namespace, c.dcName)
+	if err != nil {
+		return err
+	}
+
+	replication, err := mgmtapi.DatacenterReplication(mgmtClient, pod, c.dc.DatacenterName())
+	if err != nil {
+		return err
+	}
+
+	return cassdcutil.CheckReplication(replication, int(c.size))
+}
+
+// printOrphanedPVCs lists the volume claims of the datacenter no pod uses anymore after the decommissions
+func (c *scaleOptions) printOrphanedPVCs(ctx context.Context, dc *cassdcapi.CassandraDatacenter) error {
+	pvcs, err := c.cassManager.OrphanedPVCs(ctx, dc)
+	if err != nil {
+		return err
+	}
+
+	if len(pvcs) == 0 {
+		fmt.Fprintln(c.Out, "No PersistentVolumeClaims were left behind")
+		return nil
+	}
+
+	fmt.Fprintln(c.Out, "PersistentVolumeClaims left behind by the decommissioned nodes:")
+	for _, pvc := range pvcs {
+		fmt.Fprintf(c.Out, "  %s\n", pvc.Name)
+	}
+
+	return nil
+}
+
+// preflight tries to schedule the pods the datacenter would add to the current nodes of the Kubernetes cluster
+func (c *scaleOptions) preflight(ctx context.Context) error {
+	pods := scheduler.DatacenterPods(c.dc, int(c.size))
+	if err := scheduler.TryScheduling(ctx, c.kubeClient, pods); err != nil {
+		if !c.force {
+			return fmt.Errorf("%w: %w", errUnschedulable, err)
+		}
+		fmt.Fprintf(c.ErrOut, "Warning: the new pods will not fit the Kubernetes cluster, scaling anyway: %v\n", err)
+	}
+
+	return nil
+}
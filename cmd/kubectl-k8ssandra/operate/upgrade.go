@@ -0,0 +1,153 @@
+package operate
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	"github.com/k8ssandra/k8ssandra-client/pkg/cassdcutil"
+	"github.com/k8ssandra/k8ssandra-client/pkg/kubernetes"
+	"github.com/k8ssandra/k8ssandra-client/pkg/upgrade"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	upgradeVersionExample = `
+	# upgrade datacenter dc1 to Cassandra 5.0.4
+	%[1]s upgrade-version dc1 --to 5.0.4
+
+	# upgrade to a custom image of the version
+	%[1]s upgrade-version dc1 --to 4.1.8 --image example.com/cassandra:4.1.8
+
+	# resume an interrupted upgrade by running the same command again
+	%[1]s upgrade-version dc1 --to 5.0.4
+	`
+
+	errNoTargetVersion = fmt.Errorf("--to must be set to the target version")
+)
+
+type upgradeVersionOptions struct {
+	configFlags *genericclioptions.ConfigFlags
+	genericclioptions.IOStreams
+	namespace   string
+	dcName      string
+	to          string
+	image       string
+	timeout     time.Duration
+	kubeClient  client.Client
+	cassManager *cassdcutil.CassManager
+	dc          *cassdcapi.CassandraDatacenter
+}
+
+func newUpgradeVersionOptions(streams genericclioptions.IOStreams) *upgradeVersionOptions {
+	return &upgradeVersionOptions{
+		configFlags: genericclioptions.NewConfigFlags(true),
+		IOStreams:   streams,
+	}
+}
+
+// NewUpgradeVersionCmd provides a cobra command upgrading the server version of a datacenter
+func NewUpgradeVersionCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	o := newUpgradeVersionOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:          "upgrade-version [datacenter] --to [version]",
+		Short:        "upgrade the server version of a datacenter, flushing and snapshotting the nodes before and upgrading the SSTables after",
+		Example:      fmt.Sprintf(upgradeVersionExample, "kubectl k8ssandra"),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.Complete(c, args); err != nil {
+				return err
+			}
+			if err := o.Validate(); err != nil {
+				return err
+			}
+			if err := o.Run(); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	fl := cmd.Flags()
+	fl.StringVar(&o.to, "to", "", "target server version")
+	fl.StringVar(&o.image, "image", "", "server image to use instead of the default image of the version")
+	fl.DurationVar(&o.timeout, "timeout", 30*time.Minute, "how long to wait for each step of the upgrade")
+	if err := cmd.MarkFlagRequired("to"); err != nil {
+		panic(err)
+	}
+	o.configFlags.AddFlags(fl)
+	return cmd
+}
+
+// Complete parses the arguments and necessary flags to options
+func (c *upgradeVersionOptions) Complete(cmd *cobra.Command, args []string) error {
+	var err error
+
+	if len(args) < 1 {
+		return errNoDatacenterDefined
+	}
+
+	c.dcName = args[0]
+
+	c.namespace, _, err = c.configFlags.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	restConfig, err := c.configFlags.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+
+	kubeClient, err := kubernetes.GetClientInNamespace(restConfig, c.namespace)
+	if err != nil {
+		return err
+	}
+
+	c.kubeClient = kubeClient
+	c.cassManager = cassdcutil.NewManager(kubeClient)
+
+	return nil
+}
+
+// Validate ensures that all required arguments and flag values are provided
+func (c *upgradeVersionOptions) Validate() error {
+	if c.to == "" {
+		return errNoTargetVersion
+	}
+
+	dc, err := c.cassManager.CassandraDatacenter(context.Background(), c.dcName, c.namespace)
+	if err != nil {
+		return err
+	}
+
+	c.dc = dc
+	return nil
+}
+
+// Run upgrades the datacenter, printing each phase as it starts
+func (c *upgradeVersionOptions) Run() error {
+	opts := upgrade.Options{
+		To:      c.to,
+		Image:   c.image,
+		Timeout: c.timeout,
+		OnPhase: func(state *upgrade.State) {
+			if state.Phase == upgrade.PhaseCompleted {
+				fmt.Fprintf(c.Out, "Datacenter %s upgraded from %s to %s\n", c.dcName, state.From, state.To)
+				return
+			}
+			fmt.Fprintf(c.Out, "Upgrading datacenter %s from %s to %s: %s\n", c.dcName, state.From, state.To, state.Phase)
+		},
+	}
+
+	if _, err := upgrade.Run(context.Background(), c.kubeClient, c.dc, opts); err != nil {
+		return fmt.Errorf("%w, run the command again to resume the upgrade", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package repair
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+	reaperclient "github.com/k8ssandra/reaper-client-go/reaper"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+var (
+	listExample = `
+	# list the repair runs of the K8ssandraCluster demo
+	%[1]s list demo
+
+	# list only the running and paused repair runs
+	%[1]s list demo --state RUNNING,PAUSED
+	`
+)
+
+type listOptions struct {
+	reaperOptions
+	states []string
+}
+
+// NewListCmd provides a cobra command wrapping listOptions
+func NewListCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	o := &listOptions{reaperOptions: newReaperOptions(streams)}
+
+	cmd := &cobra.Command{
+		Use:          "list [cluster] [flags]",
+		Short:        "list the repair runs of a K8ssandraCluster in Reaper",
+		Example:      fmt.Sprintf(listExample, "kubectl k8ssandra repair"),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.Complete(c, args); err != nil {
+				return err
+			}
+			if err := o.List(); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	fl := cmd.Flags()
+	fl.StringSliceVar(&o.states, "state", []string{}, "list only the repair runs in the given states, for example RUNNING,PAUSED")
+	o.addFlags(fl)
+	return cmd
+}
+
+// List prints the repair runs of the cluster
+func (c *listOptions) List() error {
+	ctx := context.Background()
+
+	reaperClient, cluster, forward, err := c.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer forward.Close()
+
+	search := &reaperclient.RepairRunSearchOptions{Cluster: cluster}
+	for _, state := range c.states {
+		search.States = append(search.States, reaperclient.RepairRunState(state))
+	}
+
+	runs, err := reaperClient.RepairRuns(ctx, search)
+	if err != nil {
+		return err
+	}
+
+	if len(runs) == 0 {
+		fmt.Fprintln(c.ErrOut, "No repair runs found")
+		return nil
+	}
+
+	return printRepairTable(c.Out, sortedRuns(runs))
+}
+
+// sortedRuns returns the repair runs oldest first, Reaper uses time based UUIDs as ids
+func sortedRuns(runs map[uuid.UUID]*reaperclient.RepairRun) []*reaperclient.RepairRun {
+	sorted := make([]*reaperclient.RepairRun, 0, len(runs))
+	for _, run := range runs {
+		sorted = append(sorted, run)
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Id.Time() < sorted[j].Id.Time()
+	})
+
+	return sorted
+}
@@ -0,0 +1,42 @@
+package repair
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	reaperclient "github.com/k8ssandra/reaper-client-go/reaper"
+)
+
+// printRepairTable writes one line per repair run
+func printRepairTable(out io.Writer, runs []*reaperclient.RepairRun) error {
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+
+	fmt.Fprintln(w, "ID\tKEYSPACE\tTABLES\tSTATE\tSEGMENTS\tINCREMENTAL\tINTENSITY\tPARALLELISM\tOWNER\tDURATION")
+
+	for _, run := range runs {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d/%d\t%t\t%g\t%s\t%s\t%s\n",
+			run.Id,
+			run.Keyspace,
+			orNone(strings.Join(run.Tables, ",")),
+			run.State,
+			run.SegmentsRepaired,
+			run.TotalSegments,
+			run.IncrementalRepair,
+			run.Intensity,
+			orNone(string(run.RepairParallelism)),
+			orNone(run.Owner),
+			orNone(run.Duration),
+		)
+	}
+
+	return w.Flush()
+}
+
+func orNone(value string) string {
+	if value == "" {
+		return "<none>"
+	}
+	return value
+}
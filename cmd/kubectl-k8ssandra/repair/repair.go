@@ -0,0 +1,138 @@
+package repair
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/k8ssandra/k8ssandra-client/pkg/cassdcutil"
+	"github.com/k8ssandra/k8ssandra-client/pkg/kubernetes"
+	"github.com/k8ssandra/k8ssandra-client/pkg/reaper"
+	reaperclient "github.com/k8ssandra/reaper-client-go/reaper"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	errNoClusterDefined = fmt.Errorf("no target K8ssandraCluster given")
+	errNoRunDefined     = fmt.Errorf("no target repair run given")
+	errInvalidRunId     = fmt.Errorf("the repair run id must be a UUID")
+)
+
+type ClientOptions struct {
+	configFlags *genericclioptions.ConfigFlags
+	genericclioptions.IOStreams
+}
+
+// NewClientOptions provides an instance of ClientOptions with default values
+func NewClientOptions(streams genericclioptions.IOStreams) *ClientOptions {
+	return &ClientOptions{
+		configFlags: genericclioptions.NewConfigFlags(true),
+		IOStreams:   streams,
+	}
+}
+
+// NewCmd provides a cobra command wrapping ClientOptions
+func NewCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	o := NewClientOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:   "repair [subcommand] [flags]",
+		Short: "start and manage Reaper repairs of a K8ssandraCluster",
+	}
+
+	// Add subcommands
+	cmd.AddCommand(NewStartCmd(streams))
+	cmd.AddCommand(NewListCmd(streams))
+	cmd.AddCommand(NewPauseCmd(streams))
+	cmd.AddCommand(NewResumeCmd(streams))
+	cmd.AddCommand(NewAbortCmd(streams))
+
+	o.configFlags.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// reaperOptions locate the Reaper of a K8ssandraCluster, they are shared by every repair command
+type reaperOptions struct {
+	configFlags *genericclioptions.ConfigFlags
+	genericclioptions.IOStreams
+	namespace   string
+	clusterName string
+	dcName      string
+	restConfig  *rest.Config
+	kubeClient  client.Client
+}
+
+func newReaperOptions(streams genericclioptions.IOStreams) reaperOptions {
+	return reaperOptions{
+		configFlags: genericclioptions.NewConfigFlags(true),
+		IOStreams:   streams,
+	}
+}
+
+func (c *reaperOptions) addFlags(fl *pflag.FlagSet) {
+	fl.StringVar(&c.dcName, "dc", "", "use the Reaper of this datacenter in the PER_DC deployment mode, defaults to the first running datacenter")
+	c.configFlags.AddFlags(fl)
+}
+
+// Complete parses the K8ssandraCluster argument and creates the Kubernetes client
+func (c *reaperOptions) Complete(cmd *cobra.Command, args []string) error {
+	var err error
+
+	if len(args) < 1 {
+		return errNoClusterDefined
+	}
+
+	c.clusterName = args[0]
+
+	c.namespace, _, err = c.configFlags.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	c.restConfig, err = c.configFlags.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+
+	c.kubeClient, err = kubernetes.GetClient(c.restConfig)
+	return err
+}
+
+// connect forwards a local port to the Reaper service of the K8ssandraCluster and logs in with the Reaper UI user.
+// The returned forward must be closed once the client is no longer used.
+func (c *reaperOptions) connect(ctx context.Context) (reaperclient.Client, string, *kubernetes.PortForward, error) {
+	kc, err := cassdcutil.NewManager(c.kubeClient).K8ssandraCluster(ctx, c.clusterName, c.namespace)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	serviceKey, err := reaper.ServiceKey(kc, c.dcName)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	username, password, err := reaper.Credentials(ctx, c.kubeClient, kc)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	forward, err := kubernetes.ForwardServicePort(ctx, c.restConfig, c.kubeClient, serviceKey.Namespace, serviceKey.Name, reaper.ServicePort)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	reaperClient := reaperclient.NewClient(&url.URL{Scheme: "http", Host: fmt.Sprintf("localhost:%d", forward.LocalPort)})
+	if username != "" {
+		if err := reaperClient.Login(ctx, username, password); err != nil {
+			forward.Close()
+			return nil, "", nil, err
+		}
+	}
+
+	return reaperClient, reaper.ClusterName(kc), forward, nil
+}
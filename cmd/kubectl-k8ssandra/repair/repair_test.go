@@ -0,0 +1,246 @@
+package repair
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	k8ssandraapi "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	reaperapi "github.com/k8ssandra/k8ssandra-operator/apis/reaper/v1alpha1"
+	reaperclient "github.com/k8ssandra/reaper-client-go/reaper"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: test
+  cluster:
+    server: https://127.0.0.1:6443
+contexts:
+- name: test
+  context:
+    cluster: test
+    namespace: ns1
+current-context: test
+`
+
+// writeKubeconfig returns a kubeconfig the commands can complete with, no server is contacted
+func writeKubeconfig(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "kubeconfig")
+	require.NoError(t, os.WriteFile(path, []byte(testKubeconfig), 0o600))
+	return path
+}
+
+func testCluster(reaper *reaperapi.ReaperClusterTemplate) *k8ssandraapi.K8ssandraCluster {
+	return &k8ssandraapi.K8ssandraCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "ns1"},
+		Spec: k8ssandraapi.K8ssandraClusterSpec{
+			Reaper: reaper,
+			Cassandra: &k8ssandraapi.CassandraClusterTemplate{
+				Datacenters: []k8ssandraapi.CassandraDatacenterTemplate{
+					{Meta: k8ssandraapi.EmbeddedObjectMeta{Name: "dc1"}},
+				},
+			},
+		},
+	}
+}
+
+func testReaperOptions(t *testing.T, dcName string, objects ...client.Object) *reaperOptions {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, k8ssandraapi.AddToScheme(scheme))
+
+	o := newReaperOptions(genericiooptions.NewTestIOStreamsDiscard())
+	o.namespace = "ns1"
+	o.clusterName = "demo"
+	o.dcName = dcName
+	o.kubeClient = fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+	return &o
+}
+
+func TestCommandArgs(t *testing.T) {
+	kubeconfig := writeKubeconfig(t)
+	streams := genericiooptions.NewTestIOStreamsDiscard()
+
+	tests := []struct {
+		name string
+		cmd  func(genericiooptions.IOStreams) *cobra.Command
+		args []string
+		err  error
+	}{
+		{"start without cluster", NewStartCmd, []string{}, errNoClusterDefined},
+		{"start without keyspace", NewStartCmd, []string{"demo"}, errNoKeyspace},
+		{"list without cluster", NewListCmd, []string{}, errNoClusterDefined},
+		{"pause without cluster", NewPauseCmd, []string{}, errNoClusterDefined},
+		{"pause without run", NewPauseCmd, []string{"demo"}, errNoRunDefined},
+		{"pause invalid run", NewPauseCmd, []string{"demo", "run-1"}, errInvalidRunId},
+		{"resume without run", NewResumeCmd, []string{"demo"}, errNoRunDefined},
+		{"abort without run", NewAbortCmd, []string{"demo"}, errNoRunDefined},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := tt.cmd(streams)
+			cmd.SetArgs(append(tt.args, "--kubeconfig", kubeconfig))
+			cmd.SetOut(streams.Out)
+			cmd.SetErr(streams.ErrOut)
+			require.ErrorIs(t, cmd.Execute(), tt.err)
+		})
+	}
+}
+
+func TestStartFlags(t *testing.T) {
+	require := require.New(t)
+	o := &startOptions{reaperOptions: newReaperOptions(genericiooptions.NewTestIOStreamsDiscard())}
+	require.ErrorIs(o.Validate(), errNoKeyspace)
+
+	cmd := NewStartCmd(genericiooptions.NewTestIOStreamsDiscard())
+	require.NoError(cmd.ParseFlags([]string{
+		"--keyspace", "ks1", "--tables", "t1,t2", "--datacenters", "dc1", "--parallelism", "DATACENTER_AWARE",
+		"--intensity", "0.5", "--incremental", "--dc", "dc1", "--cluster", "kube-cluster",
+	}))
+
+	for flag, want := range map[string]string{
+		"keyspace":    "ks1",
+		"tables":      "[t1,t2]",
+		"datacenters": "[dc1]",
+		"parallelism": "DATACENTER_AWARE",
+		"intensity":   "0.5",
+		"incremental": "true",
+		"owner":       "kubectl-k8ssandra",
+		"dc":          "dc1",
+		// The kubeconfig cluster flag is not shadowed by the repair flags
+		"cluster": "kube-cluster",
+	} {
+		require.Equal(want, cmd.Flags().Lookup(flag).Value.String(), flag)
+	}
+}
+
+func TestConnectErrors(t *testing.T) {
+	authSecret := &reaperapi.ReaperClusterTemplate{ReaperTemplate: reaperapi.ReaperTemplate{UiUserSecretRef: &corev1.LocalObjectReference{Name: "reaper-ui"}}}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "reaper-ui", Namespace: "ns1"},
+		Data:       map[string][]byte{"username": []byte("admin"), "password": []byte("secret")},
+	}
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo-dc1-reaper-service", Namespace: "ns1"},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{"app": "reaper"},
+			Ports:    []corev1.ServicePort{{Name: "app", Port: 8080}},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		dc       string
+		objects  []client.Object
+		contains string
+		notFound bool
+	}{
+		{"missing cluster", "", nil, "", true},
+		{"reaper not enabled", "", []client.Object{testCluster(nil)}, "reaper is not enabled", false},
+		{"unknown datacenter", "dc2", []client.Object{testCluster(&reaperapi.ReaperClusterTemplate{DeploymentMode: reaperapi.DeploymentModePerDc})}, "the datacenter is not part of the K8ssandraCluster", false},
+		{"missing ui secret", "", []client.Object{testCluster(authSecret)}, "", true},
+		{"missing service", "", []client.Object{testCluster(authSecret), secret}, "", true},
+		{"no ready reaper", "", []client.Object{testCluster(authSecret), secret, service}, "the service has no ready pods", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := testReaperOptions(t, tt.dc, tt.objects...)
+			reaperClient, _, forward, err := o.connect(context.TODO())
+			require.Error(t, err)
+			require.Nil(t, reaperClient)
+			require.Nil(t, forward)
+			if tt.notFound {
+				require.True(t, apierrors.IsNotFound(err), err)
+			} else {
+				require.ErrorContains(t, err, tt.contains)
+			}
+		})
+	}
+}
+
+// fakeReaperClient serves a single repair run, the other calls of the interface are not implemented
+type fakeReaperClient struct {
+	reaperclient.Client
+	run     *reaperclient.RepairRun
+	changes int
+}
+
+func (f *fakeReaperClient) RepairRun(ctx context.Context, runId uuid.UUID) (*reaperclient.RepairRun, error) {
+	if runId != f.run.Id {
+		return nil, errors.New("repair run not found (HTTP status 404)")
+	}
+	return f.run, nil
+}
+
+func (f *fakeReaperClient) PauseRepairRun(ctx context.Context, runId uuid.UUID) error {
+	f.changes++
+	f.run.State = reaperclient.RepairRunStatePaused
+	return nil
+}
+
+func TestSetState(t *testing.T) {
+	require := require.New(t)
+	streams, _, out, _ := genericiooptions.NewTestIOStreams()
+
+	f := &fakeReaperClient{run: &reaperclient.RepairRun{Id: uuid.New(), Keyspace: "ks1", State: reaperclient.RepairRunStateRunning}}
+	o := &stateOptions{
+		reaperOptions: newReaperOptions(streams),
+		runId:         f.run.Id,
+		state:         reaperclient.RepairRunStatePaused,
+		change:        reaperclient.Client.PauseRepairRun,
+		done:          "paused",
+	}
+
+	require.NoError(o.setState(context.TODO(), f))
+	require.Equal(1, f.changes)
+	require.Equal(reaperclient.RepairRunStatePaused, f.run.State)
+	require.Contains(out.String(), "Repair run "+f.run.Id.String()+" of keyspace ks1 paused")
+
+	// Reaper refuses to pause a paused run, the command does not ask it to
+	require.NoError(o.setState(context.TODO(), f))
+	require.Equal(1, f.changes)
+
+	o.runId = uuid.New()
+	require.ErrorContains(o.setState(context.TODO(), f), "repair run not found")
+}
+
+func TestPrintRepairRuns(t *testing.T) {
+	require := require.New(t)
+
+	first, err := uuid.NewUUID()
+	require.NoError(err)
+	second, err := uuid.NewUUID()
+	require.NoError(err)
+
+	runs := sortedRuns(map[uuid.UUID]*reaperclient.RepairRun{
+		second: {Id: second, Keyspace: "ks2", State: reaperclient.RepairRunStateRunning, TotalSegments: 4, SegmentsRepaired: 1},
+		first:  {Id: first, Keyspace: "ks1", Tables: []string{"t1", "t2"}, State: reaperclient.RepairRunStateDone, Intensity: 0.5, RepairParallelism: reaperclient.RepairParallelismParallel},
+	})
+	require.Equal([]uuid.UUID{first, second}, []uuid.UUID{runs[0].Id, runs[1].Id})
+
+	out := &bytes.Buffer{}
+	require.NoError(printRepairTable(out, runs))
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(lines, 3)
+	require.Equal([]string{"ID", "KEYSPACE", "TABLES", "STATE", "SEGMENTS", "INCREMENTAL", "INTENSITY", "PARALLELISM", "OWNER", "DURATION"}, strings.Fields(lines[0]))
+	require.Equal([]string{first.String(), "ks1", "t1,t2", "DONE", "0/0", "false", "0.5", "PARALLEL", "<none>", "<none>"}, strings.Fields(lines[1]))
+	require.Equal([]string{second.String(), "ks2", "<none>", "RUNNING", "1/4", "false", "0", "<none>", "<none>", "<none>"}, strings.Fields(lines[2]))
+}
@@ -0,0 +1,103 @@
+package repair
+
+import (
+	"context"
+	"fmt"
+
+	reaperclient "github.com/k8ssandra/reaper-client-go/reaper"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+var (
+	startExample = `
+	# repair keyspace ks1 of the K8ssandraCluster demo
+	%[1]s start demo --keyspace ks1
+
+	# repair only tables t1 and t2 in datacenter dc1, one datacenter at a time
+	%[1]s start demo --keyspace ks1 --tables t1,t2 --datacenters dc1 --parallelism DATACENTER_AWARE
+
+	# run an incremental repair at half the intensity
+	%[1]s start demo --keyspace ks1 --incremental --intensity 0.5
+	`
+
+	errNoKeyspace = fmt.Errorf("--keyspace is required")
+)
+
+type startOptions struct {
+	reaperOptions
+	keyspace    string
+	owner       string
+	parallelism string
+	repair      reaperclient.RepairRunCreateOptions
+}
+
+// NewStartCmd provides a cobra command wrapping startOptions
+func NewStartCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	o := &startOptions{reaperOptions: newReaperOptions(streams)}
+
+	cmd := &cobra.Command{
+		Use:          "start [cluster] [flags]",
+		Short:        "create and start a repair run in Reaper",
+		Example:      fmt.Sprintf(startExample, "kubectl k8ssandra repair"),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.Complete(c, args); err != nil {
+				return err
+			}
+			if err := o.Validate(); err != nil {
+				return err
+			}
+			if err := o.Run(); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	fl := cmd.Flags()
+	fl.StringVar(&o.keyspace, "keyspace", "", "keyspace to repair")
+	fl.StringSliceVar(&o.repair.Tables, "tables", []string{}, "repair only the given tables of the keyspace")
+	fl.StringSliceVar(&o.repair.Datacenters, "datacenters", []string{}, "repair only the given datacenters")
+	fl.StringVar(&o.owner, "owner", "kubectl-k8ssandra", "owner of the repair run shown in Reaper")
+	fl.StringVar(&o.repair.Cause, "cause", "", "reason of the repair shown in Reaper")
+	fl.StringVar(&o.parallelism, "parallelism", "", "SEQUENTIAL, PARALLEL or DATACENTER_AWARE, defaults to the Reaper setting")
+	fl.Float64Var(&o.repair.Intensity, "intensity", 0, "intensity of the repair between 0 and 1, defaults to the Reaper setting")
+	fl.BoolVar(&o.repair.IncrementalRepair, "incremental", false, "run an incremental repair")
+	o.addFlags(fl)
+	return cmd
+}
+
+// Validate ensures that all required arguments and flag values are provided
+func (c *startOptions) Validate() error {
+	if c.keyspace == "" {
+		return errNoKeyspace
+	}
+
+	return nil
+}
+
+// Run creates the repair run and starts it
+func (c *startOptions) Run() error {
+	ctx := context.Background()
+
+	reaperClient, cluster, forward, err := c.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer forward.Close()
+
+	c.repair.RepairParallelism = reaperclient.RepairParallelism(c.parallelism)
+	runId, err := reaperClient.CreateRepairRun(ctx, cluster, c.keyspace, c.owner, &c.repair)
+	if err != nil {
+		return err
+	}
+
+	if err := reaperClient.StartRepairRun(ctx, runId); err != nil {
+		return fmt.Errorf("repair run %s was created but could not be started: %w", runId, err)
+	}
+
+	fmt.Fprintf(c.Out, "Repair run %s of keyspace %s started\n", runId, c.keyspace)
+	return nil
+}
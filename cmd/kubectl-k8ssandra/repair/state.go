@@ -0,0 +1,118 @@
+package repair
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	reaperclient "github.com/k8ssandra/reaper-client-go/reaper"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+var (
+	pauseExample = `
+	# pause a running repair run, the segments being repaired are finished first
+	%[1]s pause demo <run-id>
+	`
+
+	resumeExample = `
+	# resume a paused repair run
+	%[1]s resume demo <run-id>
+	`
+
+	abortExample = `
+	# abort a repair run, it can not be resumed afterwards
+	%[1]s abort demo <run-id>
+	`
+)
+
+// stateChange moves a repair run to another state through the Reaper client
+type stateChange func(reaperClient reaperclient.Client, ctx context.Context, runId uuid.UUID) error
+
+type stateOptions struct {
+	reaperOptions
+	runId  uuid.UUID
+	state  reaperclient.RepairRunState
+	change stateChange
+	done   string
+}
+
+func newStateCmd(streams genericclioptions.IOStreams, use, short, example string, state reaperclient.RepairRunState, change stateChange, done string) *cobra.Command {
+	o := &stateOptions{reaperOptions: newReaperOptions(streams), state: state, change: change, done: done}
+
+	cmd := &cobra.Command{
+		Use:          use,
+		Short:        short,
+		Example:      fmt.Sprintf(example, "kubectl k8ssandra repair"),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.Complete(c, args); err != nil {
+				return err
+			}
+			if len(args) < 2 {
+				return errNoRunDefined
+			}
+			runId, err := uuid.Parse(args[1])
+			if err != nil {
+				return fmt.Errorf("%w: %s", errInvalidRunId, args[1])
+			}
+			o.runId = runId
+
+			if err := o.Run(); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	o.addFlags(cmd.Flags())
+	return cmd
+}
+
+// NewPauseCmd provides a cobra command pausing a repair run
+func NewPauseCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	return newStateCmd(streams, "pause [cluster] [run-id] [flags]", "pause a running repair run", pauseExample, reaperclient.RepairRunStatePaused, reaperclient.Client.PauseRepairRun, "paused")
+}
+
+// NewResumeCmd provides a cobra command resuming a repair run
+func NewResumeCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	return newStateCmd(streams, "resume [cluster] [run-id] [flags]", "resume a paused repair run", resumeExample, reaperclient.RepairRunStateRunning, reaperclient.Client.ResumeRepairRun, "resumed")
+}
+
+// NewAbortCmd provides a cobra command aborting a repair run
+func NewAbortCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	return newStateCmd(streams, "abort [cluster] [run-id] [flags]", "abort a repair run", abortExample, reaperclient.RepairRunStateAborted, reaperclient.Client.AbortRepairRun, "aborted")
+}
+
+// Run moves the repair run to the wanted state
+func (c *stateOptions) Run() error {
+	ctx := context.Background()
+
+	reaperClient, _, forward, err := c.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer forward.Close()
+
+	return c.setState(ctx, reaperClient)
+}
+
+// setState changes the state of the repair run unless it is already in the wanted state
+func (c *stateOptions) setState(ctx context.Context, reaperClient reaperclient.Client) error {
+	run, err := reaperClient.RepairRun(ctx, c.runId)
+	if err != nil {
+		return err
+	}
+
+	// Reaper answers a change to the current state with 304 Not Modified, which the client reports as an error
+	if run.State != c.state {
+		if err := c.change(reaperClient, ctx, c.runId); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(c.Out, "Repair run %s of keyspace %s %s\n", run.Id, run.Keyspace, c.done)
+	return nil
+}
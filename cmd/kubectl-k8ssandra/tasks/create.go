@@ -0,0 +1,429 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	controlapi "github.com/k8ssandra/cass-operator/apis/control/v1alpha1"
+	"github.com/k8ssandra/k8ssandra-client/pkg/cassdcutil"
+	"github.com/k8ssandra/k8ssandra-client/pkg/kubernetes"
+	"github.com/k8ssandra/k8ssandra-client/pkg/tasks"
+	"github.com/k8ssandra/k8ssandra-client/pkg/util"
+	k8ssandrataskapi "github.com/k8ssandra/k8ssandra-operator/apis/control/v1alpha1"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	flushExample = `
+	# flush all the nodes of datacenter dc1
+	%[1]s flush --dc dc1
+
+	# flush a single pod and wait for the task to finish
+	%[1]s flush --dc dc1 --pod cluster1-dc1-r1-sts-0 --wait
+
+	# flush every datacenter of the K8ssandraCluster demo
+	%[1]s flush --k8ssandra-cluster demo
+
+	# flush only tables t1 and t2 of keyspace ks1
+	%[1]s flush --dc dc1 --keyspace ks1 --tables t1,t2
+
+	# print the CassandraTask without creating it
+	%[1]s flush --dc dc1 --dry-run=client -o yaml
+	`
+
+	cleanupExample = `
+	# run cleanup on all the nodes of datacenter dc1
+	%[1]s cleanup --dc dc1
+
+	# run cleanup on rack r1 of datacenter dc1 in the K8ssandraCluster demo
+	%[1]s cleanup --k8ssandra-cluster demo --dc dc1 --rack r1
+
+	# run cleanup of keyspace ks1 only
+	%[1]s cleanup --dc dc1 --keyspace ks1
+
+	# run cleanup at 02:00 and do not allow other tasks to run at the same time
+	%[1]s cleanup --dc dc1 --start-at 02:00 --concurrency-policy Forbid
+
+	# run cleanup in every datacenter of the K8ssandraCluster demo, one datacenter at a time
+	%[1]s cleanup --k8ssandra-cluster demo --dc-concurrency-policy Forbid
+	`
+
+	compactExample = `
+	# run a major compaction on all the nodes of datacenter dc1
+	%[1]s compact --dc dc1
+
+	# compact only tables t1 and t2 of keyspace ks1
+	%[1]s compact --dc dc1 --keyspace ks1 --tables t1,t2
+	`
+
+	scrubExample = `
+	# scrub all the nodes of datacenter dc1
+	%[1]s scrub --dc dc1
+
+	# scrub only table t1 of keyspace ks1
+	%[1]s scrub --dc dc1 --keyspace ks1 --tables t1
+	`
+
+	upgradeSSTablesExample = `
+	# rewrite the SSTables of all the nodes of datacenter dc1 to the current version
+	%[1]s upgradesstables --dc dc1 --wait
+
+	# rewrite only the SSTables of keyspace ks1
+	%[1]s upgradesstables --dc dc1 --keyspace ks1
+	`
+
+	gcExample = `
+	# remove deleted data from the SSTables of datacenter dc1
+	%[1]s gc --dc dc1
+
+	# remove deleted data from tables t1 and t2 of keyspace ks1
+	%[1]s gc --dc dc1 --keyspace ks1 --tables t1,t2
+	`
+
+	rebuildExample = `
+	# rebuild datacenter dc2 by streaming data from dc1
+	%[1]s rebuild --dc dc2 --source-dc dc1
+	`
+
+	replaceExample = `
+	# replace the node running in pod cluster1-dc1-r1-sts-0
+	%[1]s replace --dc dc1 --pod cluster1-dc1-r1-sts-0
+	`
+
+	moveExample = `
+	# move the node of pod cluster1-dc1-r1-sts-0 to token 3074457345618258602
+	%[1]s move --dc dc1 --pod cluster1-dc1-r1-sts-0 --token 3074457345618258602
+
+	# move a node of datacenter dc1 in the K8ssandraCluster demo
+	%[1]s move --k8ssandra-cluster demo --dc dc1 --pod demo-dc1-r1-sts-0 --token -3074457345618258603
+	`
+
+	errNoTarget           = fmt.Errorf("either --dc or --k8ssandra-cluster is required")
+	errUnsupportedCommand = fmt.Errorf("unsupported task command")
+	errNoMoveDatacenter   = fmt.Errorf("--dc is required to move a node of a K8ssandraCluster")
+	errTablesNoKeyspace   = fmt.Errorf("--tables requires --keyspace")
+)
+
+type createOptions struct {
+	configFlags *genericclioptions.ConfigFlags
+	genericclioptions.IOStreams
+	namespace   string
+	command     controlapi.CassandraCommand
+	dcName      string
+	clusterName string
+	rackName    string
+	podName     string
+	keyspace    string
+	tables      []string
+	sourceDc    string
+	token       string
+	wait        bool
+	timeout     time.Duration
+	taskFlags   taskFlags
+	taskOpts    []tasks.TaskOption
+	outputFlags *util.OutputFlags
+	kubeClient  client.Client
+	cassManager *cassdcutil.CassManager
+}
+
+func newCreateOptions(streams genericclioptions.IOStreams, command controlapi.CassandraCommand) *createOptions {
+	return &createOptions{
+		configFlags: genericclioptions.NewConfigFlags(true),
+		outputFlags: util.NewOutputFlags(),
+		IOStreams:   streams,
+		command:     command,
+	}
+}
+
+func newTaskCmd(o *createOptions, use, short, example string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          use,
+		Short:        short,
+		Example:      fmt.Sprintf(example, "kubectl k8ssandra tasks"),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.Complete(c, args); err != nil {
+				return err
+			}
+			if err := o.Validate(); err != nil {
+				return err
+			}
+			if err := o.Run(); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	fl := cmd.Flags()
+	fl.StringVar(&o.dcName, "dc", "", "target CassandraDatacenter, or the datacenter of the K8ssandraCluster if --k8ssandra-cluster is set")
+	fl.StringVar(&o.clusterName, "k8ssandra-cluster", "", "target K8ssandraCluster, creates a K8ssandraTask instead of a CassandraTask")
+	fl.StringVar(&o.rackName, "rack", "", "target only the given rack")
+	fl.StringVar(&o.podName, "pod", "", "target only the given pod")
+	fl.BoolVarP(&o.wait, "wait", "w", false, "wait until the task has completed, fails if the task failed")
+	fl.DurationVar(&o.timeout, "timeout", 10*time.Minute, "how long to wait for the task to complete, extended by the delay of --start-at")
+	o.taskFlags.addFlags(fl)
+	o.outputFlags.AddFlags(cmd)
+	o.configFlags.AddFlags(fl)
+	return cmd
+}
+
+func addKeyspaceFlags(cmd *cobra.Command, o *createOptions) {
+	fl := cmd.Flags()
+	fl.StringVar(&o.keyspace, "keyspace", "", "target keyspace")
+	fl.StringSliceVar(&o.tables, "tables", []string{}, "target tables of the keyspace, requires --keyspace")
+}
+
+func NewFlushCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	o := newCreateOptions(streams, controlapi.CommandFlush)
+	cmd := newTaskCmd(o, "flush [flags]", "flush memtables to disk", flushExample)
+	addKeyspaceFlags(cmd, o)
+	return cmd
+}
+
+func NewCleanupCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	o := newCreateOptions(streams, controlapi.CommandCleanup)
+	cmd := newTaskCmd(o, "cleanup [flags]", "remove data the nodes no longer own", cleanupExample)
+	addKeyspaceFlags(cmd, o)
+	return cmd
+}
+
+func NewCompactCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	o := newCreateOptions(streams, controlapi.CommandCompaction)
+	cmd := newTaskCmd(o, "compact [flags]", "run a major compaction", compactExample)
+	addKeyspaceFlags(cmd, o)
+	return cmd
+}
+
+func NewScrubCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	o := newCreateOptions(streams, controlapi.CommandScrub)
+	cmd := newTaskCmd(o, "scrub [flags]", "scrub the SSTables", scrubExample)
+	addKeyspaceFlags(cmd, o)
+	return cmd
+}
+
+func NewUpgradeSSTablesCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	o := newCreateOptions(streams, controlapi.CommandUpgradeSSTables)
+	cmd := newTaskCmd(o, "upgradesstables [flags]", "rewrite SSTables to the current version", upgradeSSTablesExample)
+	addKeyspaceFlags(cmd, o)
+	return cmd
+}
+
+func NewGarbageCollectCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	o := newCreateOptions(streams, controlapi.CommandGarbageCollect)
+	cmd := newTaskCmd(o, "gc [flags]", "remove deleted data from SSTables", gcExample)
+	addKeyspaceFlags(cmd, o)
+	return cmd
+}
+
+func NewRebuildCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	o := newCreateOptions(streams, controlapi.CommandRebuild)
+	cmd := newTaskCmd(o, "rebuild [flags]", "rebuild data by streaming from another datacenter", rebuildExample)
+	cmd.Flags().StringVar(&o.sourceDc, "source-dc", "", "datacenter to stream the data from")
+
+	if err := cmd.MarkFlagRequired("source-dc"); err != nil {
+		panic(err)
+	}
+
+	return cmd
+}
+
+func NewReplaceCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	o := newCreateOptions(streams, controlapi.CommandReplaceNode)
+	cmd := newTaskCmd(o, "replace [flags]", "replace the node of a pod", replaceExample)
+
+	if err := cmd.MarkFlagRequired("pod"); err != nil {
+		panic(err)
+	}
+
+	return cmd
+}
+
+func NewMoveCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	o := newCreateOptions(streams, controlapi.CommandMove)
+	// --token is the new token of the node instead of the bearer token of the kubeconfig
+	o.configFlags.BearerToken = nil
+	cmd := newTaskCmd(o, "move [flags]", "move the single token node of a pod to a new token", moveExample)
+	cmd.Flags().StringVar(&o.token, "token", "", "new token of the node, validated for the partitioner of the datacenter")
+
+	if err := cmd.MarkFlagRequired("pod"); err != nil {
+		panic(err)
+	}
+
+	if err := cmd.MarkFlagRequired("token"); err != nil {
+		panic(err)
+	}
+
+	return cmd
+}
+
+// Complete parses the arguments and necessary flags to options
+func (c *createOptions) Complete(cmd *cobra.Command, args []string) error {
+	var err error
+
+	now := time.Now()
+	c.taskOpts, err = c.taskFlags.taskOptions(cmd.Flags(), now)
+	if err != nil {
+		return err
+	}
+	c.timeout = c.taskFlags.waitTimeout(c.timeout, now)
+
+	if err := c.outputFlags.Complete(cmd); err != nil {
+		return err
+	}
+	c.taskOpts = append(c.taskOpts, tasks.WithDryRun(c.outputFlags.DryRun))
+
+	c.namespace, _, err = c.configFlags.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	restConfig, err := c.configFlags.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+
+	kubeClient, err := kubernetes.GetClientInNamespace(restConfig, c.namespace)
+	if err != nil {
+		return err
+	}
+
+	c.kubeClient = kubeClient
+	c.cassManager = cassdcutil.NewManager(kubeClient)
+
+	return nil
+}
+
+// Validate ensures that all required arguments and flag values are provided
+func (c *createOptions) Validate() error {
+	if c.dcName == "" && c.clusterName == "" {
+		return errNoTarget
+	}
+
+	if len(c.tables) > 0 && c.keyspace == "" {
+		return errTablesNoKeyspace
+	}
+
+	return nil
+}
+
+// Run creates the task and waits for its completion if requested
+func (c *createOptions) Run() error {
+	ctx := context.Background()
+
+	if c.clusterName != "" {
+		task, err := c.createClusterTask(ctx)
+		if err != nil {
+			return err
+		}
+
+		if err := c.outputFlags.PrintObj(c.Out, c.kubeClient.Scheme(), task, "created"); err != nil {
+			return err
+		}
+
+		if c.wait && c.outputFlags.DryRun == cmdutil.DryRunNone {
+			return tasks.WaitForClusterCompletion(ctx, c.kubeClient, task, tasks.WithTimeout(c.timeout))
+		}
+
+		return nil
+	}
+
+	task, err := c.createTask(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := c.outputFlags.PrintObj(c.Out, c.kubeClient.Scheme(), task, "created"); err != nil {
+		return err
+	}
+
+	if c.wait && c.outputFlags.DryRun == cmdutil.DryRunNone {
+		return tasks.WaitForCompletion(ctx, c.kubeClient, task, tasks.WithTimeout(c.timeout))
+	}
+
+	return nil
+}
+
+func (c *createOptions) createTask(ctx context.Context) (*controlapi.CassandraTask, error) {
+	dc, err := c.cassManager.CassandraDatacenter(ctx, c.dcName, c.namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	switch c.command {
+	case controlapi.CommandFlush, controlapi.CommandCleanup, controlapi.CommandCompaction, controlapi.CommandScrub, controlapi.CommandUpgradeSSTables, controlapi.CommandGarbageCollect:
+		return tasks.CreateTask(ctx, c.kubeClient, c.command, dc, c.keyspaceArguments(), c.taskOpts...)
+	case controlapi.CommandRebuild:
+		return tasks.CreateRebuildTask(ctx, c.kubeClient, dc, c.rackName, c.podName, c.sourceDc, c.taskOpts...)
+	case controlapi.CommandReplaceNode:
+		return tasks.CreateReplaceTask(ctx, c.kubeClient, dc, c.podName, c.taskOpts...)
+	case controlapi.CommandMove:
+		return tasks.CreateMoveTask(ctx, c.kubeClient, dc, map[string]string{c.podName: c.token}, c.taskOpts...)
+	}
+
+	return nil, errUnsupportedCommand
+}
+
+func (c *createOptions) createClusterTask(ctx context.Context) (*k8ssandrataskapi.K8ssandraTask, error) {
+	switch c.command {
+	case controlapi.CommandFlush, controlapi.CommandCleanup, controlapi.CommandCompaction, controlapi.CommandScrub, controlapi.CommandUpgradeSSTables, controlapi.CommandGarbageCollect:
+		return tasks.CreateClusterTask(ctx, c.kubeClient, c.command, c.namespace, c.clusterName, []string{c.dcName}, c.keyspaceArguments(), c.taskOpts...)
+	case controlapi.CommandRebuild:
+		return tasks.CreateClusterRebuildTask(ctx, c.kubeClient, c.namespace, c.clusterName, c.dcName, c.rackName, c.podName, c.sourceDc, c.taskOpts...)
+	case controlapi.CommandReplaceNode:
+		return tasks.CreateClusterReplaceTask(ctx, c.kubeClient, c.namespace, c.clusterName, c.dcName, c.podName, c.taskOpts...)
+	case controlapi.CommandMove:
+		partitioner, err := c.clusterPartitioner(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return tasks.CreateClusterMoveTask(ctx, c.kubeClient, c.namespace, c.clusterName, c.dcName, partitioner, map[string]string{c.podName: c.token}, c.taskOpts...)
+	}
+
+	return nil, errUnsupportedCommand
+}
+
+// keyspaceArguments returns the job arguments of the commands accepting --keyspace and --tables, cass-operator runs
+// them against the whole node when no keyspace is given
+func (c *createOptions) keyspaceArguments() *controlapi.JobArguments {
+	args := &controlapi.JobArguments{
+		RackName:     c.rackName,
+		PodName:      c.podName,
+		KeyspaceName: c.keyspace,
+	}
+
+	if len(c.tables) > 0 {
+		args.Tables = c.tables
+	}
+
+	return args
+}
+
+// clusterPartitioner returns the partitioner of the target datacenter if its CassandraDatacenter is in this Kubernetes
+// cluster, after checking it uses single tokens. Datacenters deployed to other Kubernetes clusters use the default
+// partitioner for the validation.
+func (c *createOptions) clusterPartitioner(ctx context.Context) (string, error) {
+	if c.dcName == "" {
+		return "", errNoMoveDatacenter
+	}
+
+	dc, err := c.cassManager.CassandraDatacenter(ctx, c.dcName, c.namespace)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	if err := tasks.ValidateSingleToken(dc); err != nil {
+		return "", err
+	}
+
+	return tasks.Partitioner(dc), nil
+}
@@ -0,0 +1,175 @@
+package tasks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/k8ssandra/k8ssandra-client/pkg/kubernetes"
+	"github.com/k8ssandra/k8ssandra-client/pkg/tasks"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+var (
+	listExample = `
+	# list the CassandraTasks and K8ssandraTasks of the current namespace
+	%[1]s list
+
+	# list the tasks of every namespace
+	%[1]s list --all-namespaces
+	`
+
+	watchExample = `
+	# follow the progress of a task until it has completed
+	%[1]s watch <task>
+	`
+
+	errNoTaskName = fmt.Errorf("no target task given")
+)
+
+type listOptions struct {
+	configFlags *genericclioptions.ConfigFlags
+	genericclioptions.IOStreams
+	namespace     string
+	allNamespaces bool
+	taskName      string
+}
+
+func newListOptions(streams genericclioptions.IOStreams) *listOptions {
+	return &listOptions{
+		configFlags: genericclioptions.NewConfigFlags(true),
+		IOStreams:   streams,
+	}
+}
+
+// NewListCmd provides a cobra command wrapping listOptions
+func NewListCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	o := newListOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:          "list [flags]",
+		Short:        "list CassandraTasks and K8ssandraTasks",
+		Example:      fmt.Sprintf(listExample, "kubectl k8ssandra tasks"),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.Complete(c, args); err != nil {
+				return err
+			}
+			if err := o.List(); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	fl := cmd.Flags()
+	fl.BoolVarP(&o.allNamespaces, "all-namespaces", "A", false, "list the tasks across all namespaces")
+	o.configFlags.AddFlags(fl)
+	return cmd
+}
+
+// NewWatchCmd provides a cobra command wrapping listOptions
+func NewWatchCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	o := newListOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:          "watch [task]",
+		Short:        "follow the progress of a CassandraTask or K8ssandraTask",
+		Example:      fmt.Sprintf(watchExample, "kubectl k8ssandra tasks"),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if len(args) < 1 {
+				return errNoTaskName
+			}
+			o.taskName = args[0]
+
+			if err := o.Complete(c, args); err != nil {
+				return err
+			}
+			if err := o.Watch(); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	o.configFlags.AddFlags(cmd.Flags())
+	return cmd
+}
+
+// Complete parses the arguments and necessary flags to options
+func (c *listOptions) Complete(cmd *cobra.Command, args []string) error {
+	var err error
+
+	c.namespace, _, err = c.configFlags.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	if c.allNamespaces {
+		c.namespace = ""
+	}
+
+	return nil
+}
+
+// List prints the tasks of the namespace
+func (c *listOptions) List() error {
+	restConfig, err := c.configFlags.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+
+	kubeClient, err := kubernetes.GetClient(restConfig)
+	if err != nil {
+		return err
+	}
+
+	summaries, err := tasks.ListTasks(context.Background(), kubeClient, c.namespace)
+	if err != nil {
+		return err
+	}
+
+	if len(summaries) == 0 {
+		fmt.Fprintln(c.ErrOut, "No tasks found")
+		return nil
+	}
+
+	return printTaskTable(c.Out, summaries, c.allNamespaces)
+}
+
+// Watch prints the status of the task every time it changes until the task has completed
+func (c *listOptions) Watch() error {
+	restConfig, err := c.configFlags.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+
+	kubeClient, err := kubernetes.GetWatchClient(restConfig)
+	if err != nil {
+		return err
+	}
+
+	previous := ""
+	return tasks.WatchTask(context.Background(), kubeClient, types.NamespacedName{Name: c.taskName, Namespace: c.namespace}, func(s *tasks.Summary, pods []tasks.PodJobStatus) {
+		var b bytes.Buffer
+		if err := printTaskDetails(&b, s, pods); err != nil {
+			return
+		}
+
+		// Unrelated pod changes trigger updates also, only print when something visible has changed
+		if b.String() == previous {
+			return
+		}
+
+		if previous != "" {
+			fmt.Fprintln(c.Out)
+		}
+		previous = b.String()
+		fmt.Fprint(c.Out, previous)
+	})
+}
@@ -0,0 +1,89 @@
+package tasks
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/k8ssandra/k8ssandra-client/pkg/tasks"
+	"github.com/spf13/pflag"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// taskFlags are the scheduling and execution settings shared by every command creating a task
+type taskFlags struct {
+	startAt             string
+	startTime           time.Time
+	ttl                 time.Duration
+	concurrencyPolicy   string
+	restartPolicy       string
+	dcConcurrencyPolicy string
+}
+
+func (f *taskFlags) addFlags(fl *pflag.FlagSet) {
+	fl.StringVar(&f.startAt, "start-at", "", "earliest time to run the task, either RFC3339 timestamp or HH:MM for the next occurrence in local time")
+	fl.DurationVar(&f.ttl, "ttl", 0, "how long to keep the task after it has finished, 0 keeps it forever. Defaults to the operator's setting")
+	fl.StringVar(&f.concurrencyPolicy, "concurrency-policy", "", "Allow or Forbid running the task at the same time with other tasks in the datacenter")
+	fl.StringVar(&f.restartPolicy, "restart-policy", "", "Never or OnFailure, what to do if the task fails")
+	fl.StringVar(&f.dcConcurrencyPolicy, "dc-concurrency-policy", "", "Allow running the K8ssandraTask in all the datacenters at once or Forbid to run one datacenter at a time")
+}
+
+// taskOptions converts the set flags to TaskOptions
+func (f *taskFlags) taskOptions(fl *pflag.FlagSet, now time.Time) ([]tasks.TaskOption, error) {
+	opts := make([]tasks.TaskOption, 0)
+
+	if f.startAt != "" {
+		startTime, err := parseScheduledTime(f.startAt, now)
+		if err != nil {
+			return nil, err
+		}
+		f.startTime = startTime
+		opts = append(opts, tasks.WithScheduledTime(startTime))
+	}
+
+	if fl.Changed("ttl") {
+		opts = append(opts, tasks.WithTTLSecondsAfterFinished(int32(f.ttl.Seconds())))
+	}
+
+	if f.concurrencyPolicy != "" {
+		opts = append(opts, tasks.WithConcurrencyPolicy(batchv1.ConcurrencyPolicy(f.concurrencyPolicy)))
+	}
+
+	if f.restartPolicy != "" {
+		opts = append(opts, tasks.WithRestartPolicy(corev1.RestartPolicy(f.restartPolicy)))
+	}
+
+	if f.dcConcurrencyPolicy != "" {
+		opts = append(opts, tasks.WithDcConcurrencyPolicy(batchv1.ConcurrencyPolicy(f.dcConcurrencyPolicy)))
+	}
+
+	return opts, nil
+}
+
+// waitTimeout extends the timeout by the time left until the task is allowed to start
+func (f *taskFlags) waitTimeout(timeout time.Duration, now time.Time) time.Duration {
+	if f.startTime.After(now) {
+		return timeout + f.startTime.Sub(now)
+	}
+	return timeout
+}
+
+// parseScheduledTime accepts either a RFC3339 timestamp or a HH:MM time of day, which is the next occurrence of that
+// time after now
+func parseScheduledTime(value string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	t, err := time.ParseInLocation("15:04", value, now.Location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --start-at %s, expected RFC3339 timestamp or HH:MM", value)
+	}
+
+	scheduled := time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, now.Location())
+	if !scheduled.After(now) {
+		scheduled = scheduled.AddDate(0, 0, 1)
+	}
+
+	return scheduled, nil
+}
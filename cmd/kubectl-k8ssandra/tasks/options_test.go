@@ -0,0 +1,82 @@
+package tasks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseScheduledTime(t *testing.T) {
+	require := require.New(t)
+	now := time.Date(2024, 3, 10, 13, 30, 0, 0, time.UTC)
+
+	scheduled, err := parseScheduledTime("02:00", now)
+	require.NoError(err)
+	require.Equal(time.Date(2024, 3, 11, 2, 0, 0, 0, time.UTC), scheduled)
+
+	scheduled, err = parseScheduledTime("20:15", now)
+	require.NoError(err)
+	require.Equal(time.Date(2024, 3, 10, 20, 15, 0, 0, time.UTC), scheduled)
+
+	scheduled, err = parseScheduledTime("2024-04-01T02:00:00Z", now)
+	require.NoError(err)
+	require.Equal(time.Date(2024, 4, 1, 2, 0, 0, 0, time.UTC), scheduled)
+
+	_, err = parseScheduledTime("tomorrow", now)
+	require.Error(err)
+}
+
+func TestTaskOptions(t *testing.T) {
+	require := require.New(t)
+
+	f := &taskFlags{}
+	fl := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	f.addFlags(fl)
+
+	opts, err := f.taskOptions(fl, time.Now())
+	require.NoError(err)
+	require.Empty(opts)
+
+	require.NoError(fl.Parse([]string{"--ttl", "0s", "--start-at", "02:00", "--concurrency-policy", "Forbid", "--restart-policy", "OnFailure", "--dc-concurrency-policy", "Allow"}))
+	opts, err = f.taskOptions(fl, time.Now())
+	require.NoError(err)
+	require.Len(opts, 5)
+
+	f.startAt = "soon"
+	_, err = f.taskOptions(fl, time.Now())
+	require.Error(err)
+}
+
+func TestWaitTimeout(t *testing.T) {
+	require := require.New(t)
+	now := time.Date(2024, 3, 10, 13, 30, 0, 0, time.UTC)
+
+	f := &taskFlags{}
+	require.Equal(10*time.Minute, f.waitTimeout(10*time.Minute, now))
+
+	f.startTime = now.Add(-time.Hour)
+	require.Equal(10*time.Minute, f.waitTimeout(10*time.Minute, now))
+
+	f.startTime = now.Add(2 * time.Hour)
+	require.Equal(2*time.Hour+10*time.Minute, f.waitTimeout(10*time.Minute, now))
+}
+
+func TestParseAge(t *testing.T) {
+	require := require.New(t)
+
+	d, err := parseAge("7d")
+	require.NoError(err)
+	require.Equal(7*24*time.Hour, d)
+
+	d, err = parseAge("12h")
+	require.NoError(err)
+	require.Equal(12*time.Hour, d)
+
+	_, err = parseAge("xd")
+	require.Error(err)
+
+	_, err = parseAge("-1h")
+	require.Error(err)
+}
@@ -0,0 +1,142 @@
+package tasks
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/k8ssandra/k8ssandra-client/pkg/tasks"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+// printTaskTable writes one line per task
+func printTaskTable(out io.Writer, summaries []*tasks.Summary, withNamespace bool) error {
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+
+	if withNamespace {
+		fmt.Fprint(w, "NAMESPACE\t")
+	}
+	fmt.Fprintln(w, "NAME\tKIND\tCOMMAND\tTARGET\tSTARTED\tCOMPLETED\tSUCCEEDED\tFAILED\tCONDITIONS")
+
+	for _, s := range summaries {
+		if withNamespace {
+			fmt.Fprintf(w, "%s\t", s.Namespace)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%d\t%d\t%s\n",
+			s.Name,
+			s.Kind,
+			strings.Join(s.Commands, ","),
+			s.TargetString(),
+			age(s.Status.StartTime),
+			age(s.Status.CompletionTime),
+			s.Status.Succeeded,
+			s.Status.Failed,
+			orNone(strings.Join(s.ActiveConditions(), ",")),
+		)
+	}
+
+	return w.Flush()
+}
+
+// printTaskDetails writes the status of a single task, its datacenters and the job progress of its pods
+func printTaskDetails(out io.Writer, s *tasks.Summary, pods []tasks.PodJobStatus) error {
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+
+	fmt.Fprintf(w, "Name:\t%s/%s\n", s.Kind, s.Name)
+	fmt.Fprintf(w, "Command:\t%s\n", strings.Join(s.Commands, ","))
+	fmt.Fprintf(w, "Target:\t%s\n", s.TargetString())
+	fmt.Fprintf(w, "Started:\t%s\n", timestamp(s.Status.StartTime))
+	fmt.Fprintf(w, "Completed:\t%s\n", timestamp(s.Status.CompletionTime))
+	fmt.Fprintf(w, "Jobs:\t%d active, %d succeeded, %d failed\n", s.Status.Active, s.Status.Succeeded, s.Status.Failed)
+	fmt.Fprintf(w, "Conditions:\t%s\n", conditionString(s.Status.Conditions))
+
+	if len(s.DatacenterStatus) > 0 {
+		fmt.Fprintln(w, "\nDATACENTER\tSTARTED\tCOMPLETED\tACTIVE\tSUCCEEDED\tFAILED\tCONDITIONS")
+
+		dcs := make([]string, 0, len(s.DatacenterStatus))
+		for dc := range s.DatacenterStatus {
+			dcs = append(dcs, dc)
+		}
+		sort.Strings(dcs)
+
+		for _, dc := range dcs {
+			status := s.DatacenterStatus[dc]
+			fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\t%d\t%s\n", dc, timestamp(status.StartTime), timestamp(status.CompletionTime), status.Active, status.Succeeded, status.Failed, conditionString(status.Conditions))
+		}
+	}
+
+	if len(pods) > 0 {
+		fmt.Fprintln(w, "\nPOD\tSTATUS\tRETRIES")
+		for _, pod := range pods {
+			fmt.Fprintf(w, "%s\t%s\t%d\n", pod.Pod, orNone(pod.Status), pod.Retries)
+		}
+	}
+
+	return w.Flush()
+}
+
+// printScheduleTable writes one line per task schedule
+func printScheduleTable(out io.Writer, cronJobs []batchv1.CronJob, withNamespace bool) error {
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+
+	if withNamespace {
+		fmt.Fprint(w, "NAMESPACE\t")
+	}
+	fmt.Fprintln(w, "NAME\tSCHEDULE\tSUSPEND\tACTIVE\tLAST SCHEDULE\tCOMMAND")
+
+	for i := range cronJobs {
+		cronJob := &cronJobs[i]
+		if withNamespace {
+			fmt.Fprintf(w, "%s\t", cronJob.Namespace)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%t\t%d\t%s\t%s\n",
+			cronJob.Name,
+			cronJob.Spec.Schedule,
+			cronJob.Spec.Suspend != nil && *cronJob.Spec.Suspend,
+			len(cronJob.Status.Active),
+			age(cronJob.Status.LastScheduleTime),
+			strings.Join(tasks.ScheduledArgs(cronJob), " "),
+		)
+	}
+
+	return w.Flush()
+}
+
+func conditionString(conditions []metav1.Condition) string {
+	parts := make([]string, 0, len(conditions))
+	for _, cond := range conditions {
+		part := fmt.Sprintf("%s=%s", cond.Type, cond.Status)
+		if cond.Message != "" {
+			part = fmt.Sprintf("%s (%s)", part, cond.Message)
+		}
+		parts = append(parts, part)
+	}
+
+	return orNone(strings.Join(parts, ", "))
+}
+
+func age(t *metav1.Time) string {
+	if t == nil || t.IsZero() {
+		return "<none>"
+	}
+	return duration.HumanDuration(time.Since(t.Time))
+}
+
+func timestamp(t *metav1.Time) string {
+	if t == nil || t.IsZero() {
+		return "<none>"
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func orNone(value string) string {
+	if value == "" {
+		return "<none>"
+	}
+	return value
+}
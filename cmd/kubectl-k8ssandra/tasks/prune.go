@@ -0,0 +1,186 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/k8ssandra/k8ssandra-client/pkg/kubernetes"
+	"github.com/k8ssandra/k8ssandra-client/pkg/tasks"
+	"github.com/k8ssandra/k8ssandra-client/pkg/util"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+var (
+	pruneExample = `
+	# remove the tasks which completed over a week ago, except for the last 5
+	%[1]s prune --older-than 7d --keep-last 5
+
+	# remove the tasks without asking for confirmation, for example in a schedule
+	%[1]s prune --older-than 7d --yes
+
+	# remove the succeeded tasks older than a day, keeping the failed ones for investigation
+	%[1]s prune --older-than 24h --only-succeeded
+
+	# list the tasks that would be removed in every namespace
+	%[1]s prune --older-than 7d --all-namespaces --dry-run=client
+	`
+)
+
+type pruneOptions struct {
+	configFlags *genericclioptions.ConfigFlags
+	genericclioptions.IOStreams
+	namespace     string
+	allNamespaces bool
+	olderThan     string
+	yes           bool
+	pruneOpts     tasks.PruneOptions
+	outputFlags   *util.OutputFlags
+}
+
+func newPruneOptions(streams genericclioptions.IOStreams) *pruneOptions {
+	return &pruneOptions{
+		configFlags: genericclioptions.NewConfigFlags(true),
+		outputFlags: util.NewOutputFlags(),
+		IOStreams:   streams,
+	}
+}
+
+// NewPruneCmd provides a cobra command wrapping pruneOptions
+func NewPruneCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	o := newPruneOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:          "prune [flags]",
+		Short:        "delete completed CassandraTasks and K8ssandraTasks",
+		Example:      fmt.Sprintf(pruneExample, "kubectl k8ssandra tasks"),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.Complete(c, args); err != nil {
+				return err
+			}
+			if err := o.Run(); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	fl := cmd.Flags()
+	fl.StringVar(&o.olderThan, "older-than", "", "only delete tasks which completed longer ago than this, for example 7d or 12h")
+	fl.IntVar(&o.pruneOpts.KeepLast, "keep-last", 0, "number of most recently completed tasks to keep in each namespace")
+	fl.BoolVar(&o.pruneOpts.OnlySucceeded, "only-succeeded", false, "keep the failed tasks")
+	fl.BoolVarP(&o.allNamespaces, "all-namespaces", "A", false, "prune the tasks across all namespaces")
+	fl.BoolVarP(&o.yes, "yes", "y", false, "delete the tasks without asking for confirmation")
+	o.outputFlags.AddFlags(cmd)
+	o.configFlags.AddFlags(fl)
+	return cmd
+}
+
+// Complete parses the arguments and necessary flags to options
+func (c *pruneOptions) Complete(cmd *cobra.Command, args []string) error {
+	var err error
+
+	if c.olderThan != "" {
+		c.pruneOpts.OlderThan, err = parseAge(c.olderThan)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := c.outputFlags.Complete(cmd); err != nil {
+		return err
+	}
+	c.pruneOpts.DryRun = c.outputFlags.DryRun
+
+	c.namespace, _, err = c.configFlags.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	if c.allNamespaces {
+		c.namespace = ""
+	}
+
+	return nil
+}
+
+// Run lists the matching tasks and deletes them after confirmation. With --dry-run nothing is deleted and there is
+// no confirmation.
+func (c *pruneOptions) Run() error {
+	ctx := context.Background()
+
+	restConfig, err := c.configFlags.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+
+	kubeClient, err := kubernetes.GetClient(restConfig)
+	if err != nil {
+		return err
+	}
+
+	listOpts := c.pruneOpts
+	listOpts.DryRun = cmdutil.DryRunClient
+	candidates, err := tasks.PruneTasks(ctx, kubeClient, c.namespace, listOpts)
+	if err != nil {
+		return err
+	}
+
+	if len(candidates) == 0 {
+		fmt.Fprintln(c.ErrOut, "No tasks to prune")
+		return nil
+	}
+
+	if !c.yes && c.pruneOpts.DryRun == cmdutil.DryRunNone {
+		fmt.Fprintln(c.Out, "Completed tasks to delete:")
+		for _, s := range candidates {
+			name := fmt.Sprintf("%s/%s", strings.ToLower(s.Kind), s.Name)
+			if c.allNamespaces {
+				name = fmt.Sprintf("%s %s", s.Namespace, name)
+			}
+			fmt.Fprintf(c.Out, "  %s\n", name)
+		}
+
+		if !util.Confirm(c.In, c.Out, fmt.Sprintf("Delete %d tasks?", len(candidates))) {
+			fmt.Fprintln(c.Out, "Nothing deleted")
+			return nil
+		}
+	}
+
+	pruned, err := tasks.DeleteTasks(ctx, kubeClient, candidates, c.pruneOpts.DryRun)
+	for _, s := range pruned {
+		if err := c.outputFlags.PrintObj(c.Out, kubeClient.Scheme(), s.Task, "deleted"); err != nil {
+			return err
+		}
+	}
+
+	return err
+}
+
+// parseAge parses a Go duration, extended with a d suffix for days
+func parseAge(value string) (time.Duration, error) {
+	if days, found := strings.CutSuffix(value, "d"); found {
+		d, err := strconv.Atoi(days)
+		if err != nil || d < 0 {
+			return 0, fmt.Errorf("invalid duration %s", value)
+		}
+		return time.Duration(d) * 24 * time.Hour, nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+
+	if d < 0 {
+		return 0, fmt.Errorf("invalid duration %s", value)
+	}
+
+	return d, nil
+}
@@ -0,0 +1,244 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	controlapi "github.com/k8ssandra/cass-operator/apis/control/v1alpha1"
+	"github.com/k8ssandra/k8ssandra-client/pkg/cassdcutil"
+	"github.com/k8ssandra/k8ssandra-client/pkg/kubernetes"
+	"github.com/k8ssandra/k8ssandra-client/pkg/tasks"
+	"github.com/k8ssandra/k8ssandra-client/pkg/util"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	runExample = `
+	# flush, cleanup and compact keyspace ks1 in datacenter dc1, one job after another
+	%[1]s run --dc dc1 --jobs flush,cleanup,compact --keyspace ks1
+
+	# run the jobs described in a pipeline file
+	%[1]s run -f maintenance.yaml
+
+	# run the jobs of a pipeline file in every datacenter of the K8ssandraCluster demo
+	%[1]s run -f maintenance.yaml --k8ssandra-cluster demo
+
+	# the pipeline file lists the jobs in the order they are run
+	datacenter: dc1
+	jobs:
+	  - command: flush
+	    args:
+	      keyspace_name: ks1
+	  - command: cleanup
+	    args:
+	      keyspace_name: ks1
+	  - command: compact
+	    args:
+	      keyspace_name: ks1
+	`
+
+	errNoJobs           = fmt.Errorf("either --jobs or --filename is required")
+	errDoubleDefinition = fmt.Errorf("either --jobs or --filename is allowed, not both")
+	errJobFlagsWithFile = fmt.Errorf("--rack, --pod, --keyspace and --tables can not be used with --filename, set them in the pipeline file")
+)
+
+type runOptions struct {
+	configFlags *genericclioptions.ConfigFlags
+	genericclioptions.IOStreams
+	namespace   string
+	filename    string
+	dcName      string
+	clusterName string
+	rackName    string
+	podName     string
+	keyspace    string
+	tables      []string
+	jobs        []string
+	wait        bool
+	timeout     time.Duration
+	taskFlags   taskFlags
+	taskOpts    []tasks.TaskOption
+	outputFlags *util.OutputFlags
+	pipeline    *tasks.Pipeline
+	kubeClient  client.Client
+	cassManager *cassdcutil.CassManager
+}
+
+func newRunOptions(streams genericclioptions.IOStreams) *runOptions {
+	return &runOptions{
+		configFlags: genericclioptions.NewConfigFlags(true),
+		outputFlags: util.NewOutputFlags(),
+		IOStreams:   streams,
+	}
+}
+
+// NewRunCmd provides a cobra command wrapping runOptions
+func NewRunCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	o := newRunOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:          "run [flags]",
+		Short:        "run multiple jobs in order as a single task",
+		Example:      fmt.Sprintf(runExample, "kubectl k8ssandra tasks"),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.Complete(c, args); err != nil {
+				return err
+			}
+			if err := o.Validate(); err != nil {
+				return err
+			}
+			if err := o.Run(); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	fl := cmd.Flags()
+	fl.StringVarP(&o.filename, "filename", "f", "", "pipeline file describing the jobs")
+	fl.StringSliceVar(&o.jobs, "jobs", []string{}, "commands to run in order, for example flush,cleanup,compact")
+	fl.StringVar(&o.dcName, "dc", "", "target CassandraDatacenter, or the datacenter of the K8ssandraCluster if --k8ssandra-cluster is set")
+	fl.StringVar(&o.clusterName, "k8ssandra-cluster", "", "target K8ssandraCluster, creates a K8ssandraTask instead of a CassandraTask")
+	fl.StringVar(&o.rackName, "rack", "", "target only the given rack, used with --jobs")
+	fl.StringVar(&o.podName, "pod", "", "target only the given pod, used with --jobs")
+	fl.StringVar(&o.keyspace, "keyspace", "", "target keyspace, used with --jobs")
+	fl.StringSliceVar(&o.tables, "tables", []string{}, "target tables of the keyspace, used with --jobs")
+	fl.BoolVarP(&o.wait, "wait", "w", false, "wait until the task has completed, fails if the task failed")
+	fl.DurationVar(&o.timeout, "timeout", 10*time.Minute, "how long to wait for the task to complete, extended by the delay of --start-at")
+	o.taskFlags.addFlags(fl)
+	o.outputFlags.AddFlags(cmd)
+	o.configFlags.AddFlags(fl)
+	return cmd
+}
+
+// Complete parses the arguments and necessary flags to options
+func (c *runOptions) Complete(cmd *cobra.Command, args []string) error {
+	var err error
+
+	if c.filename != "" && len(c.jobs) > 0 {
+		return errDoubleDefinition
+	}
+
+	if c.filename != "" && (c.rackName != "" || c.podName != "" || c.keyspace != "" || len(c.tables) > 0) {
+		return errJobFlagsWithFile
+	}
+
+	if c.filename != "" {
+		c.pipeline, err = tasks.ReadPipeline(c.filename)
+		if err != nil {
+			return err
+		}
+	} else if len(c.jobs) > 0 {
+		commands := make([]controlapi.CassandraCommand, 0, len(c.jobs))
+		for _, job := range c.jobs {
+			commands = append(commands, controlapi.CassandraCommand(job))
+		}
+
+		c.pipeline = tasks.NewPipeline(commands, controlapi.JobArguments{
+			RackName:     c.rackName,
+			PodName:      c.podName,
+			KeyspaceName: c.keyspace,
+			Tables:       c.tables,
+		})
+	} else {
+		return errNoJobs
+	}
+
+	// Flags override the targets of the pipeline file
+	if c.dcName != "" {
+		c.pipeline.Datacenter = c.dcName
+	}
+
+	if c.clusterName != "" {
+		c.pipeline.Cluster = c.clusterName
+	}
+
+	now := time.Now()
+	c.taskOpts, err = c.taskFlags.taskOptions(cmd.Flags(), now)
+	if err != nil {
+		return err
+	}
+	c.timeout = c.taskFlags.waitTimeout(c.timeout, now)
+
+	if err := c.outputFlags.Complete(cmd); err != nil {
+		return err
+	}
+	c.taskOpts = append(c.taskOpts, tasks.WithDryRun(c.outputFlags.DryRun))
+
+	c.namespace, _, err = c.configFlags.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	restConfig, err := c.configFlags.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+
+	kubeClient, err := kubernetes.GetClientInNamespace(restConfig, c.namespace)
+	if err != nil {
+		return err
+	}
+
+	c.kubeClient = kubeClient
+	c.cassManager = cassdcutil.NewManager(kubeClient)
+
+	return nil
+}
+
+// Validate ensures that all required arguments and flag values are provided
+func (c *runOptions) Validate() error {
+	if c.pipeline.Datacenter == "" && c.pipeline.Cluster == "" {
+		return errNoTarget
+	}
+
+	return nil
+}
+
+// Run creates a single task with all the jobs of the pipeline
+func (c *runOptions) Run() error {
+	ctx := context.Background()
+
+	if c.pipeline.Cluster != "" {
+		task, err := tasks.CreateClusterJobsTask(ctx, c.kubeClient, c.namespace, c.pipeline.Cluster, []string{c.pipeline.Datacenter}, c.pipeline.Jobs, c.taskOpts...)
+		if err != nil {
+			return err
+		}
+
+		if err := c.outputFlags.PrintObj(c.Out, c.kubeClient.Scheme(), task, "created"); err != nil {
+			return err
+		}
+
+		if c.wait && c.outputFlags.DryRun == cmdutil.DryRunNone {
+			return tasks.WaitForClusterCompletion(ctx, c.kubeClient, task, tasks.WithTimeout(c.timeout))
+		}
+
+		return nil
+	}
+
+	dc, err := c.cassManager.CassandraDatacenter(ctx, c.pipeline.Datacenter, c.namespace)
+	if err != nil {
+		return err
+	}
+
+	task, err := tasks.CreateJobsTask(ctx, c.kubeClient, dc, c.pipeline.Jobs, c.taskOpts...)
+	if err != nil {
+		return err
+	}
+
+	if err := c.outputFlags.PrintObj(c.Out, c.kubeClient.Scheme(), task, "created"); err != nil {
+		return err
+	}
+
+	if c.wait && c.outputFlags.DryRun == cmdutil.DryRunNone {
+		return tasks.WaitForCompletion(ctx, c.kubeClient, task, tasks.WithTimeout(c.timeout))
+	}
+
+	return nil
+}
@@ -0,0 +1,319 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/k8ssandra/k8ssandra-client/pkg/kubernetes"
+	"github.com/k8ssandra/k8ssandra-client/pkg/tasks"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	scheduleExample = `
+	# run garbage collection in rack r1 of datacenter dc1 every Sunday at 03:00
+	%[1]s create weekly-gc-r1 --cron "0 3 * * 0" -- gc --dc dc1 --rack r1
+
+	# run a pipeline of jobs in every datacenter of the K8ssandraCluster demo every night, using a specific client image
+	%[1]s create nightly --cron "0 1 * * *" --image k8ssandra/k8ssandra-client:v0.8.1 -- run --k8ssandra-cluster demo --jobs flush,cleanup
+
+	# list the schedules
+	%[1]s list
+
+	# pause and continue a schedule
+	%[1]s suspend weekly-gc-r1
+	%[1]s resume weekly-gc-r1
+
+	# remove a schedule
+	%[1]s delete weekly-gc-r1
+	`
+
+	errNoScheduleName   = fmt.Errorf("no schedule name given")
+	errNoScheduleTask   = fmt.Errorf("no tasks command given, add it after --")
+	errNoCron           = fmt.Errorf("--cron is required")
+	errUnschedulableCmd = fmt.Errorf("only commands creating a task can be scheduled")
+	errScheduledFile    = fmt.Errorf("--filename can not be scheduled, the file is not available in the CronJob pod. Use --jobs instead")
+	errScheduledConfirm = fmt.Errorf("scheduled commands can not ask for confirmation, add --yes")
+)
+
+type scheduleOptions struct {
+	configFlags *genericclioptions.ConfigFlags
+	genericclioptions.IOStreams
+	namespace     string
+	allNamespaces bool
+	name          string
+	cron          string
+	image         string
+	suspend       bool
+	taskArgs      []string
+	kubeClient    client.Client
+}
+
+func newScheduleOptions(streams genericclioptions.IOStreams) *scheduleOptions {
+	return &scheduleOptions{
+		configFlags: genericclioptions.NewConfigFlags(true),
+		IOStreams:   streams,
+	}
+}
+
+// NewScheduleCmd provides a cobra command grouping the task schedule commands
+func NewScheduleCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "schedule [subcommand] [flags]",
+		Short:   "run tasks periodically using CronJobs",
+		Example: fmt.Sprintf(scheduleExample, "kubectl k8ssandra tasks schedule"),
+	}
+
+	cmd.AddCommand(newScheduleCreateCmd(streams))
+	cmd.AddCommand(newScheduleListCmd(streams))
+	cmd.AddCommand(newScheduleSuspendCmd(streams, "suspend", "stop creating new tasks from the schedule", true))
+	cmd.AddCommand(newScheduleSuspendCmd(streams, "resume", "continue creating tasks from a suspended schedule", false))
+	cmd.AddCommand(newScheduleDeleteCmd(streams))
+
+	return cmd
+}
+
+func newScheduleCreateCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	o := newScheduleOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:          "create [name] --cron [expression] -- [tasks command]",
+		Short:        "create a CronJob which runs the tasks command on a schedule",
+		Example:      fmt.Sprintf(scheduleExample, "kubectl k8ssandra tasks schedule"),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			dash := c.ArgsLenAtDash()
+			if dash < 0 || dash == len(args) {
+				return errNoScheduleTask
+			}
+			if dash < 1 {
+				return errNoScheduleName
+			}
+			o.name = args[0]
+			o.taskArgs = args[dash:]
+
+			if err := o.Validate(c); err != nil {
+				return err
+			}
+			if err := o.Complete(c, args); err != nil {
+				return err
+			}
+			if err := o.Create(); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	fl := cmd.Flags()
+	fl.StringVar(&o.cron, "cron", "", "schedule in cron format, for example \"0 3 * * 0\" for every Sunday at 03:00")
+	fl.StringVar(&o.image, "image", tasks.DefaultScheduleImage, "k8ssandra-client image to run the tasks command with")
+	fl.BoolVar(&o.suspend, "suspend", false, "create the schedule in suspended state")
+	o.configFlags.AddFlags(fl)
+	return cmd
+}
+
+func newScheduleListCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	o := newScheduleOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:          "list [flags]",
+		Short:        "list the task schedules",
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.Complete(c, args); err != nil {
+				return err
+			}
+			if err := o.List(); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	fl := cmd.Flags()
+	fl.BoolVarP(&o.allNamespaces, "all-namespaces", "A", false, "list the schedules across all namespaces")
+	o.configFlags.AddFlags(fl)
+	return cmd
+}
+
+func newScheduleSuspendCmd(streams genericclioptions.IOStreams, use, short string, suspend bool) *cobra.Command {
+	o := newScheduleOptions(streams)
+	o.suspend = suspend
+
+	cmd := &cobra.Command{
+		Use:          fmt.Sprintf("%s [name]", use),
+		Short:        short,
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if len(args) < 1 {
+				return errNoScheduleName
+			}
+			o.name = args[0]
+
+			if err := o.Complete(c, args); err != nil {
+				return err
+			}
+			if err := o.Suspend(); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	o.configFlags.AddFlags(cmd.Flags())
+	return cmd
+}
+
+func newScheduleDeleteCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	o := newScheduleOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:          "delete [name]",
+		Short:        "delete the task schedule and its Jobs",
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if len(args) < 1 {
+				return errNoScheduleName
+			}
+			o.name = args[0]
+
+			if err := o.Complete(c, args); err != nil {
+				return err
+			}
+			if err := o.Delete(); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	o.configFlags.AddFlags(cmd.Flags())
+	return cmd
+}
+
+// Complete parses the arguments and necessary flags to options
+func (c *scheduleOptions) Complete(cmd *cobra.Command, args []string) error {
+	var err error
+
+	c.namespace, _, err = c.configFlags.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	if c.allNamespaces {
+		c.namespace = ""
+	}
+
+	restConfig, err := c.configFlags.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+
+	c.kubeClient, err = kubernetes.GetClient(restConfig)
+	return err
+}
+
+// Validate ensures that the scheduled command is a tasks command creating a task
+func (c *scheduleOptions) Validate(cmd *cobra.Command) error {
+	if c.cron == "" {
+		return errNoCron
+	}
+
+	// cmd is tasks schedule create
+	tasksCmd := cmd.Parent().Parent()
+	target, targetArgs, err := tasksCmd.Find(c.taskArgs)
+	if err != nil {
+		return err
+	}
+
+	if target == tasksCmd || target.Parent() != tasksCmd {
+		return fmt.Errorf("%w: %s", errUnschedulableCmd, strings.Join(c.taskArgs, " "))
+	}
+
+	switch target.Name() {
+	case "schedule", "list", "watch":
+		return fmt.Errorf("%w: %s", errUnschedulableCmd, target.Name())
+	}
+
+	// The command runs in the CronJob without local files or a terminal
+	if err := target.ParseFlags(targetArgs); err != nil {
+		return err
+	}
+
+	if f := target.Flags().Lookup("filename"); f != nil && f.Changed {
+		return errScheduledFile
+	}
+
+	if f := target.Flags().Lookup("yes"); f != nil && f.Value.String() != "true" {
+		return errScheduledConfirm
+	}
+
+	return nil
+}
+
+// Create creates the CronJob and the resources it needs
+func (c *scheduleOptions) Create() error {
+	cronJob, err := tasks.CreateSchedule(context.Background(), c.kubeClient, &tasks.Schedule{
+		Name:      c.name,
+		Namespace: c.namespace,
+		Cron:      c.cron,
+		Image:     c.image,
+		Args:      c.taskArgs,
+		Suspend:   c.suspend,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(c.Out, "cronjob/%s created\n", cronJob.Name)
+	return nil
+}
+
+// List prints the task schedules
+func (c *scheduleOptions) List() error {
+	cronJobs, err := tasks.ListSchedules(context.Background(), c.kubeClient, c.namespace)
+	if err != nil {
+		return err
+	}
+
+	if len(cronJobs) == 0 {
+		fmt.Fprintln(c.ErrOut, "No schedules found")
+		return nil
+	}
+
+	return printScheduleTable(c.Out, cronJobs, c.allNamespaces)
+}
+
+// Suspend suspends or resumes the schedule
+func (c *scheduleOptions) Suspend() error {
+	if err := tasks.SuspendSchedule(context.Background(), c.kubeClient, types.NamespacedName{Name: c.name, Namespace: c.namespace}, c.suspend); err != nil {
+		return err
+	}
+
+	state := "resumed"
+	if c.suspend {
+		state = "suspended"
+	}
+
+	fmt.Fprintf(c.Out, "cronjob/%s %s\n", c.name, state)
+	return nil
+}
+
+// Delete removes the schedule
+func (c *scheduleOptions) Delete() error {
+	if err := tasks.DeleteSchedule(context.Background(), c.kubeClient, types.NamespacedName{Name: c.name, Namespace: c.namespace}); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(c.Out, "cronjob/%s deleted\n", c.name)
+	return nil
+}
@@ -0,0 +1,49 @@
+package tasks
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+type ClientOptions struct {
+	configFlags *genericclioptions.ConfigFlags
+	genericclioptions.IOStreams
+}
+
+// NewClientOptions provides an instance of ClientOptions with default values
+func NewClientOptions(streams genericclioptions.IOStreams) *ClientOptions {
+	return &ClientOptions{
+		configFlags: genericclioptions.NewConfigFlags(true),
+		IOStreams:   streams,
+	}
+}
+
+// NewCmd provides a cobra command wrapping ClientOptions
+func NewCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	o := NewClientOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:   "tasks [subcommand] [flags]",
+		Short: "create and follow CassandraTasks and K8ssandraTasks",
+	}
+
+	// Add subcommands
+	cmd.AddCommand(NewFlushCmd(streams))
+	cmd.AddCommand(NewCleanupCmd(streams))
+	cmd.AddCommand(NewCompactCmd(streams))
+	cmd.AddCommand(NewScrubCmd(streams))
+	cmd.AddCommand(NewUpgradeSSTablesCmd(streams))
+	cmd.AddCommand(NewGarbageCollectCmd(streams))
+	cmd.AddCommand(NewRebuildCmd(streams))
+	cmd.AddCommand(NewReplaceCmd(streams))
+	cmd.AddCommand(NewMoveCmd(streams))
+	cmd.AddCommand(NewRunCmd(streams))
+	cmd.AddCommand(NewListCmd(streams))
+	cmd.AddCommand(NewWatchCmd(streams))
+	cmd.AddCommand(NewScheduleCmd(streams))
+	cmd.AddCommand(NewPruneCmd(streams))
+
+	o.configFlags.AddFlags(cmd.Flags())
+
+	return cmd
+}
@@ -0,0 +1,116 @@
+package tasks
+
+import (
+	"context"
+	"testing"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	controlapi "github.com/k8ssandra/cass-operator/apis/control/v1alpha1"
+	"github.com/k8ssandra/k8ssandra-client/pkg/cassdcutil"
+	k8ssandrataskapi "github.com/k8ssandra/k8ssandra-operator/apis/control/v1alpha1"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestNewCmdFlags(t *testing.T) {
+	// Registering a flag twice panics, for example if a command flag shadows a kubeconfig flag
+	require.NotPanics(t, func() {
+		cmd := NewCmd(genericiooptions.NewTestIOStreamsDiscard())
+		for _, sub := range cmd.Commands() {
+			require.NotNil(t, sub.Flags())
+		}
+	})
+}
+
+func TestRunJobFlagsWithFile(t *testing.T) {
+	cmd := NewRunCmd(genericiooptions.NewTestIOStreamsDiscard())
+	cmd.SetArgs([]string{"-f", "pipeline.yaml", "--keyspace", "ks1"})
+	require.ErrorIs(t, cmd.Execute(), errJobFlagsWithFile)
+}
+
+func TestScheduleCreateValidate(t *testing.T) {
+	tests := []struct {
+		args []string
+		err  error
+	}{
+		{[]string{"--", "run", "-f", "pipeline.yaml"}, errScheduledFile},
+		{[]string{"--", "prune", "--older-than", "7d"}, errScheduledConfirm},
+		{[]string{"--", "list"}, errUnschedulableCmd},
+	}
+
+	for _, tt := range tests {
+		cmd := NewCmd(genericiooptions.NewTestIOStreamsDiscard())
+		cmd.SetArgs(append([]string{"schedule", "create", "nightly", "--cron", "0 1 * * *"}, tt.args...))
+		require.ErrorIs(t, cmd.Execute(), tt.err, tt.args)
+	}
+}
+
+func TestClusterFlags(t *testing.T) {
+	// The K8ssandraCluster flag must not replace the kubeconfig --cluster flag
+	for _, cmd := range []*cobra.Command{NewRunCmd(genericiooptions.NewTestIOStreamsDiscard()), NewFlushCmd(genericiooptions.NewTestIOStreamsDiscard())} {
+		require.NotNil(t, cmd.Flags().Lookup("cluster"), cmd.Name())
+		require.NotNil(t, cmd.Flags().Lookup("k8ssandra-cluster"), cmd.Name())
+	}
+}
+
+func TestKeyspaceFlags(t *testing.T) {
+	streams := genericiooptions.NewTestIOStreamsDiscard()
+	for _, cmd := range []*cobra.Command{
+		NewFlushCmd(streams), NewCleanupCmd(streams), NewCompactCmd(streams),
+		NewScrubCmd(streams), NewUpgradeSSTablesCmd(streams), NewGarbageCollectCmd(streams),
+	} {
+		require.NotNil(t, cmd.Flags().Lookup("keyspace"), cmd.Name())
+		require.NotNil(t, cmd.Flags().Lookup("tables"), cmd.Name())
+	}
+}
+
+func TestCreateValidateTables(t *testing.T) {
+	o := newCreateOptions(genericiooptions.NewTestIOStreamsDiscard(), controlapi.CommandFlush)
+	o.dcName = "dc1"
+	o.tables = []string{"t1"}
+	require.ErrorIs(t, o.Validate(), errTablesNoKeyspace)
+
+	o.keyspace = "ks1"
+	require.NoError(t, o.Validate())
+}
+
+func TestCreateKeyspaceArguments(t *testing.T) {
+	require := require.New(t)
+
+	scheme := runtime.NewScheme()
+	require.NoError(cassdcapi.AddToScheme(scheme))
+	require.NoError(controlapi.AddToScheme(scheme))
+	require.NoError(k8ssandrataskapi.AddToScheme(scheme))
+	dc := &cassdcapi.CassandraDatacenter{ObjectMeta: metav1.ObjectMeta{Name: "dc1", Namespace: "ns1"}}
+	kubeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(dc).Build()
+
+	want := controlapi.JobArguments{RackName: "r1", KeyspaceName: "ks1", Tables: []string{"t1", "t2"}}
+
+	for _, command := range []controlapi.CassandraCommand{
+		controlapi.CommandFlush, controlapi.CommandCleanup, controlapi.CommandCompaction,
+		controlapi.CommandScrub, controlapi.CommandUpgradeSSTables, controlapi.CommandGarbageCollect,
+	} {
+		o := newCreateOptions(genericiooptions.NewTestIOStreamsDiscard(), command)
+		o.namespace = "ns1"
+		o.dcName = "dc1"
+		o.rackName = "r1"
+		o.keyspace = "ks1"
+		o.tables = []string{"t1", "t2"}
+		o.kubeClient = kubeClient
+		o.cassManager = cassdcutil.NewManager(kubeClient)
+
+		task, err := o.createTask(context.TODO())
+		require.NoError(err, command)
+		require.Equal(command, task.Spec.Jobs[0].Command)
+		require.Equal(want, task.Spec.Jobs[0].Arguments, command)
+
+		o.clusterName = "demo"
+		clusterTask, err := o.createClusterTask(context.TODO())
+		require.NoError(err, command)
+		require.Equal(want, clusterTask.Spec.Template.Jobs[0].Arguments, command)
+	}
+}
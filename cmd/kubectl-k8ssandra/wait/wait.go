@@ -0,0 +1,220 @@
+package wait
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	controlapi "github.com/k8ssandra/cass-operator/apis/control/v1alpha1"
+	"github.com/k8ssandra/k8ssandra-client/pkg/kubernetes"
+	k8ssandrataskapi "github.com/k8ssandra/k8ssandra-operator/apis/control/v1alpha1"
+	k8ssandraapi "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	waitExample = `
+	# wait until datacenter dc1 is ready
+	%[1]s wait dc/dc1 --for=condition=Ready --timeout=30m
+
+	# wait until datacenter dc1 has stopped
+	%[1]s wait cassandradatacenter/dc1 --for=condition=Stopped
+
+	# wait for a task to complete, failing if the task failed
+	%[1]s wait cassandratask/dc1-restart --for=condition=Complete
+
+	# wait for a K8ssandraCluster to be deleted
+	%[1]s wait k8ssandracluster/demo --for=delete
+	`
+
+	errNoResource      = fmt.Errorf("no resource given, use the form type/name such as dc/dc1")
+	errInvalidResource = fmt.Errorf("resource must be of the form type/name")
+	errUnknownType     = fmt.Errorf("unknown resource type, supported types are cassandradatacenter (dc, cassdc), cassandratask, k8ssandratask and k8ssandracluster (k8c)")
+	errInvalidFor      = fmt.Errorf("--for must be delete, condition=<type> or condition=<type>=<status>")
+	errTaskFailed      = fmt.Errorf("task failed")
+)
+
+// resourceTypes maps the accepted resource names to the objects they stand for
+var resourceTypes = map[string]func() client.Object{
+	"cassandradatacenter":  func() client.Object { return &cassdcapi.CassandraDatacenter{} },
+	"cassandradatacenters": func() client.Object { return &cassdcapi.CassandraDatacenter{} },
+	"cassdc":               func() client.Object { return &cassdcapi.CassandraDatacenter{} },
+	"cassdcs":              func() client.Object { return &cassdcapi.CassandraDatacenter{} },
+	"dc":                   func() client.Object { return &cassdcapi.CassandraDatacenter{} },
+	"cassandratask":        func() client.Object { return &controlapi.CassandraTask{} },
+	"cassandratasks":       func() client.Object { return &controlapi.CassandraTask{} },
+	"k8ssandratask":        func() client.Object { return &k8ssandrataskapi.K8ssandraTask{} },
+	"k8ssandratasks":       func() client.Object { return &k8ssandrataskapi.K8ssandraTask{} },
+	"k8ssandracluster":     func() client.Object { return &k8ssandraapi.K8ssandraCluster{} },
+	"k8ssandraclusters":    func() client.Object { return &k8ssandraapi.K8ssandraCluster{} },
+	"k8c":                  func() client.Object { return &k8ssandraapi.K8ssandraCluster{} },
+	"k8cs":                 func() client.Object { return &k8ssandraapi.K8ssandraCluster{} },
+}
+
+type options struct {
+	configFlags *genericclioptions.ConfigFlags
+	genericclioptions.IOStreams
+	namespace   string
+	resource    string
+	forCond     string
+	timeout     time.Duration
+	obj         client.Object
+	condition   kubernetes.ObjectCondition
+	taskFailure bool
+	kubeClient  client.WithWatch
+}
+
+func newOptions(streams genericclioptions.IOStreams) *options {
+	return &options{
+		configFlags: genericclioptions.NewConfigFlags(true),
+		IOStreams:   streams,
+	}
+}
+
+// NewCmd provides a cobra command waiting for a datacenter, task or cluster to reach a condition
+func NewCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	o := newOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:          "wait [type/name] --for [condition]",
+		Short:        "wait until a CassandraDatacenter, task or K8ssandraCluster reaches a condition",
+		Example:      fmt.Sprintf(waitExample, "kubectl k8ssandra"),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.Complete(c, args); err != nil {
+				return err
+			}
+			if err := o.Validate(); err != nil {
+				return err
+			}
+			if err := o.Run(); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	fl := cmd.Flags()
+	fl.StringVar(&o.forCond, "for", "", "condition to wait for: delete, condition=<type> or condition=<type>=<status>")
+	fl.DurationVar(&o.timeout, "timeout", 10*time.Minute, "how long to wait before giving up")
+	if err := cmd.MarkFlagRequired("for"); err != nil {
+		panic(err)
+	}
+	o.configFlags.AddFlags(fl)
+	return cmd
+}
+
+// Complete parses the arguments and necessary flags to options
+func (c *options) Complete(cmd *cobra.Command, args []string) error {
+	var err error
+
+	if len(args) < 1 {
+		return errNoResource
+	}
+
+	c.resource = args[0]
+
+	c.namespace, _, err = c.configFlags.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	restConfig, err := c.configFlags.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+
+	c.kubeClient, err = kubernetes.GetWatchClient(restConfig)
+	return err
+}
+
+// Validate ensures that all required arguments and flag values are provided
+func (c *options) Validate() error {
+	obj, err := parseResource(c.resource)
+	if err != nil {
+		return err
+	}
+	obj.SetNamespace(c.namespace)
+
+	condition, conditionType, err := parseFor(c.forCond)
+	if err != nil {
+		return err
+	}
+
+	c.obj = obj
+	c.condition = condition
+	c.taskFailure = isTask(obj) && strings.EqualFold(conditionType, string(controlapi.JobComplete))
+	return nil
+}
+
+// Run waits for the condition and fails if it is not met within the timeout
+func (c *options) Run() error {
+	last, err := kubernetes.WaitFor(context.Background(), c.kubeClient, c.obj, c.timeout, c.condition)
+	if err != nil {
+		return fmt.Errorf("%s: %w", c.resource, err)
+	}
+
+	// A completed task is only a success if it did not fail
+	if c.taskFailure && last != nil {
+		if failed, _ := kubernetes.HasCondition(string(controlapi.JobFailed), string(corev1.ConditionTrue))(last); failed {
+			return fmt.Errorf("%s: %w", c.resource, errTaskFailed)
+		}
+	}
+
+	fmt.Fprintf(c.Out, "%s condition met\n", c.resource)
+	return nil
+}
+
+// parseResource returns an empty object of the type named in resource, with the name set
+func parseResource(resource string) (client.Object, error) {
+	kind, name, found := strings.Cut(resource, "/")
+	if !found || kind == "" || name == "" {
+		return nil, fmt.Errorf("%w: %s", errInvalidResource, resource)
+	}
+
+	newObj, found := resourceTypes[strings.ToLower(kind)]
+	if !found {
+		return nil, fmt.Errorf("%w: %s", errUnknownType, kind)
+	}
+
+	obj := newObj()
+	obj.SetName(name)
+	return obj, nil
+}
+
+// parseFor parses the --for value to a condition, also returning the condition type waited for
+func parseFor(forCond string) (kubernetes.ObjectCondition, string, error) {
+	if strings.EqualFold(forCond, "delete") {
+		return kubernetes.IsDeleted(), "", nil
+	}
+
+	spec, found := strings.CutPrefix(forCond, "condition=")
+	if !found || spec == "" {
+		return nil, "", fmt.Errorf("%w: %s", errInvalidFor, forCond)
+	}
+
+	conditionType, status, found := strings.Cut(spec, "=")
+	if !found {
+		status = string(corev1.ConditionTrue)
+	}
+
+	if conditionType == "" || status == "" {
+		return nil, "", fmt.Errorf("%w: %s", errInvalidFor, forCond)
+	}
+
+	return kubernetes.HasCondition(conditionType, status), conditionType, nil
+}
+
+func isTask(obj client.Object) bool {
+	switch obj.(type) {
+	case *controlapi.CassandraTask, *k8ssandrataskapi.K8ssandraTask:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+package wait
+
+import (
+	"testing"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	controlapi "github.com/k8ssandra/cass-operator/apis/control/v1alpha1"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseResource(t *testing.T) {
+	require := require.New(t)
+
+	obj, err := parseResource("dc/dc1")
+	require.NoError(err)
+	require.IsType(&cassdcapi.CassandraDatacenter{}, obj)
+	require.Equal("dc1", obj.GetName())
+
+	obj, err = parseResource("CassandraTask/dc1-restart")
+	require.NoError(err)
+	require.IsType(&controlapi.CassandraTask{}, obj)
+	require.True(isTask(obj))
+
+	_, err = parseResource("dc1")
+	require.ErrorIs(err, errInvalidResource)
+
+	_, err = parseResource("pod/dc1-r1-sts-0")
+	require.ErrorIs(err, errUnknownType)
+}
+
+func TestParseFor(t *testing.T) {
+	require := require.New(t)
+
+	dc := &cassdcapi.CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{Name: "dc1"},
+		Status: cassdcapi.CassandraDatacenterStatus{
+			Conditions: []cassdcapi.DatacenterCondition{
+				{Type: cassdcapi.DatacenterReady, Status: corev1.ConditionTrue},
+				{Type: cassdcapi.DatacenterStopped, Status: corev1.ConditionFalse},
+			},
+		},
+	}
+
+	condition, conditionType, err := parseFor("condition=Ready")
+	require.NoError(err)
+	require.Equal("Ready", conditionType)
+	met, err := condition(dc)
+	require.NoError(err)
+	require.True(met)
+
+	condition, _, err = parseFor("condition=Stopped")
+	require.NoError(err)
+	met, err = condition(dc)
+	require.NoError(err)
+	require.False(met)
+
+	condition, _, err = parseFor("condition=Stopped=false")
+	require.NoError(err)
+	met, err = condition(dc)
+	require.NoError(err)
+	require.True(met)
+
+	condition, _, err = parseFor("delete")
+	require.NoError(err)
+	met, err = condition(nil)
+	require.NoError(err)
+	require.True(met)
+
+	for _, invalid := range []string{"", "Ready", "condition=", "condition=Ready="} {
+		_, _, err = parseFor(invalid)
+		require.ErrorIs(err, errInvalidFor, invalid)
+	}
+}
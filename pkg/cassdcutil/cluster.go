@@ -0,0 +1,198 @@
+package cassdcutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	k8ssandraapi "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	errNoClusterDatacenters = errors.New("K8ssandraCluster has no datacenters")
+	errUnknownDatacenter    = errors.New("datacenter is not part of the K8ssandraCluster")
+)
+
+// ClusterStoppedStateOrder returns the datacenters of the K8ssandraCluster in the order they are started or stopped.
+// Datacenters are started in the order of the spec, the first one holding the seeds the others bootstrap from, and
+// stopped in the reverse order.
+func ClusterStoppedStateOrder(kc *k8ssandraapi.K8ssandraCluster, stop bool) ([]string, error) {
+	if kc.Spec.Cassandra == nil || len(kc.Spec.Cassandra.Datacenters) == 0 {
+		return nil, errNoClusterDatacenters
+	}
+
+	dcs := make([]string, 0, len(kc.Spec.Cassandra.Datacenters))
+	for _, dc := range kc.Spec.Cassandra.Datacenters {
+		dcs = append(dcs, dc.Meta.Name)
+	}
+
+	if stop {
+		slices.Reverse(dcs)
+	}
+
+	return dcs, nil
+}
+
+// ModifyClusterStoppedState stops or starts the datacenters of the K8ssandraCluster one at a time, waiting for each
+// datacenter to reach the state before moving to the next one. onDatacenter is called before each datacenter is
+// modified.
+func (c *CassManager) ModifyClusterStoppedState(ctx context.Context, name, namespace string, stop bool, timeout time.Duration, onDatacenter func(dc string)) error {
+	kc, err := c.K8ssandraCluster(ctx, name, namespace)
+	if err != nil {
+		return err
+	}
+
+	dcs, err := ClusterStoppedStateOrder(kc, stop)
+	if err != nil {
+		return err
+	}
+
+	for _, dc := range dcs {
+		if onDatacenter != nil {
+			onDatacenter(dc)
+		}
+
+		kc, err = c.SetClusterDatacenterStoppedState(ctx, name, namespace, dc, stop, cmdutil.DryRunNone)
+		if err != nil {
+			return err
+		}
+
+		if err := c.WaitForClusterDatacenterStoppedState(ctx, kc, dc, stop, timeout); err != nil {
+			return fmt.Errorf("datacenter %s: %w", dc, err)
+		}
+	}
+
+	return nil
+}
+
+// SetClusterStoppedState updates the stopped state of every datacenter in the K8ssandraCluster spec at once without
+// waiting for the change to happen. With a dry run strategy the cluster is not persisted, the returned object is the
+// one that would have been stored.
+func (c *CassManager) SetClusterStoppedState(ctx context.Context, name, namespace string, stop bool, dryRun cmdutil.DryRunStrategy) (*k8ssandraapi.K8ssandraCluster, error) {
+	kc, err := c.K8ssandraCluster(ctx, name, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	dcs, err := ClusterStoppedStateOrder(kc, stop)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.updateClusterStoppedState(ctx, kc, dcs, stop, dryRun)
+}
+
+// SetClusterDatacenterStoppedState updates the stopped state of a single datacenter in the K8ssandraCluster spec
+// without waiting for the change to happen. k8ssandra-operator owns the CassandraDatacenters of the cluster, thus the
+// change is made to the K8ssandraCluster instead of the CassandraDatacenter.
+func (c *CassManager) SetClusterDatacenterStoppedState(ctx context.Context, name, namespace, dcName string, stop bool, dryRun cmdutil.DryRunStrategy) (*k8ssandraapi.K8ssandraCluster, error) {
+	kc, err := c.K8ssandraCluster(ctx, name, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.updateClusterStoppedState(ctx, kc, []string{dcName}, stop, dryRun)
+}
+
+func (c *CassManager) updateClusterStoppedState(ctx context.Context, kc *k8ssandraapi.K8ssandraCluster, dcs []string, stop bool, dryRun cmdutil.DryRunStrategy) (*k8ssandraapi.K8ssandraCluster, error) {
+	if kc.Spec.Cassandra == nil {
+		return nil, errNoClusterDatacenters
+	}
+
+	kc = kc.DeepCopy()
+	patch := client.MergeFrom(kc.DeepCopy())
+
+	for _, dcName := range dcs {
+		i, err := clusterDatacenterIndex(kc, dcName)
+		if err != nil {
+			return nil, err
+		}
+		kc.Spec.Cassandra.Datacenters[i].Stopped = stop
+	}
+
+	if err := c.patch(ctx, kc, patch, dryRun); err != nil {
+		return nil, err
+	}
+
+	return kc, nil
+}
+
+// SetClusterDatacenterSize updates the size of a single datacenter in the K8ssandraCluster spec without waiting for the
+// change to happen. k8ssandra-operator renders the CassandraDatacenter size from it, reverting any change made directly
+// to the CassandraDatacenter.
+func (c *CassManager) SetClusterDatacenterSize(ctx context.Context, kc *k8ssandraapi.K8ssandraCluster, dcName string, size int32, dryRun cmdutil.DryRunStrategy) (*k8ssandraapi.K8ssandraCluster, error) {
+	i, err := clusterDatacenterIndex(kc, dcName)
+	if err != nil {
+		return nil, err
+	}
+
+	kc = kc.DeepCopy()
+	patch := client.MergeFrom(kc.DeepCopy())
+
+	kc.Spec.Cassandra.Datacenters[i].Size = size
+
+	if err := c.patch(ctx, kc, patch, dryRun); err != nil {
+		return nil, err
+	}
+
+	return kc, nil
+}
+
+// SetClusterDatacenterVersion updates the server version, and the image if set, of a single datacenter in the
+// K8ssandraCluster spec without waiting for the change to happen. The datacenter template overrides the version of the
+// cluster, leaving the other datacenters unchanged.
+func (c *CassManager) SetClusterDatacenterVersion(ctx context.Context, kc *k8ssandraapi.K8ssandraCluster, dcName, version, image string, dryRun cmdutil.DryRunStrategy) (*k8ssandraapi.K8ssandraCluster, error) {
+	i, err := clusterDatacenterIndex(kc, dcName)
+	if err != nil {
+		return nil, err
+	}
+
+	kc = kc.DeepCopy()
+	patch := client.MergeFrom(kc.DeepCopy())
+
+	kc.Spec.Cassandra.Datacenters[i].ServerVersion = version
+	if image != "" {
+		kc.Spec.Cassandra.Datacenters[i].ServerImage = image
+	}
+
+	if err := c.patch(ctx, kc, patch, dryRun); err != nil {
+		return nil, err
+	}
+
+	return kc, nil
+}
+
+// WaitForClusterDatacenterStoppedState waits until the K8ssandraCluster status reports the datacenter as stopped, or
+// as ready after starting. The status is used instead of the CassandraDatacenter since the datacenter can run in
+// another Kubernetes cluster.
+func (c *CassManager) WaitForClusterDatacenterStoppedState(ctx context.Context, kc *k8ssandraapi.K8ssandraCluster, dcName string, stop bool, timeout time.Duration) error {
+	if timeout == 0 {
+		timeout = defaultTimeout
+	}
+
+	stopped, ready := corev1.ConditionFalse, corev1.ConditionTrue
+	if stop {
+		stopped, ready = corev1.ConditionTrue, corev1.ConditionFalse
+	}
+
+	return c.waitFor(ctx, kc, defaultPollInterval, timeout, func(obj client.Object) (bool, error) {
+		if obj == nil {
+			return false, fmt.Errorf("K8ssandraCluster %s was deleted", kc.Name)
+		}
+
+		current := obj.(*k8ssandraapi.K8ssandraCluster)
+		status, found := current.Status.Datacenters[dcName]
+		if !found || status.Cassandra == nil {
+			return false, nil
+		}
+
+		return status.Cassandra.GetConditionStatus(cassdcapi.DatacenterStopped) == stopped &&
+			status.Cassandra.GetConditionStatus(cassdcapi.DatacenterReady) == ready, nil
+	})
+}
@@ -0,0 +1,222 @@
+package cassdcutil
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	k8ssandraapi "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func testCluster() *k8ssandraapi.K8ssandraCluster {
+	running := &cassdcapi.CassandraDatacenterStatus{
+		Conditions: []cassdcapi.DatacenterCondition{
+			{Type: cassdcapi.DatacenterReady, Status: corev1.ConditionTrue},
+			{Type: cassdcapi.DatacenterStopped, Status: corev1.ConditionFalse},
+		},
+	}
+
+	return &k8ssandraapi.K8ssandraCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "ns1"},
+		Spec: k8ssandraapi.K8ssandraClusterSpec{
+			Cassandra: &k8ssandraapi.CassandraClusterTemplate{
+				Datacenters: []k8ssandraapi.CassandraDatacenterTemplate{
+					{Meta: k8ssandraapi.EmbeddedObjectMeta{Name: "dc1"}, Size: 3},
+					{Meta: k8ssandraapi.EmbeddedObjectMeta{Name: "dc2"}, Size: 3},
+					{Meta: k8ssandraapi.EmbeddedObjectMeta{Name: "dc3"}, Size: 3},
+				},
+			},
+		},
+		Status: k8ssandraapi.K8ssandraClusterStatus{
+			Datacenters: map[string]k8ssandraapi.K8ssandraStatus{
+				"dc1": {Cassandra: running.DeepCopy()},
+				"dc2": {Cassandra: running.DeepCopy()},
+				"dc3": {Cassandra: running.DeepCopy()},
+			},
+		},
+	}
+}
+
+func clusterClient(t *testing.T) client.WithWatch {
+	scheme := runtime.NewScheme()
+	require.NoError(t, k8ssandraapi.AddToScheme(scheme))
+
+	kc := testCluster()
+	return fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(kc).
+		WithStatusSubresource(kc).
+		WithIndex(kc, "metadata.name", func(obj client.Object) []string { return []string{obj.GetName()} }).
+		Build()
+}
+
+// fakeClusterOperator copies the stopped state of the spec to the status of the datacenters, recording the order
+func fakeClusterOperator(ctx context.Context, kubeClient client.Client, changed *[]string) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(10 * time.Millisecond):
+		}
+
+		kc := &k8ssandraapi.K8ssandraCluster{}
+		if err := kubeClient.Get(ctx, types.NamespacedName{Name: "demo", Namespace: "ns1"}, kc); err != nil {
+			continue
+		}
+
+		for _, dc := range kc.Spec.Cassandra.Datacenters {
+			status := kc.Status.Datacenters[dc.Meta.Name].Cassandra
+			stopped := status.GetConditionStatus(cassdcapi.DatacenterStopped) == corev1.ConditionTrue
+			if stopped == dc.Stopped {
+				continue
+			}
+
+			ready, stoppedStatus := corev1.ConditionTrue, corev1.ConditionFalse
+			if dc.Stopped {
+				ready, stoppedStatus = corev1.ConditionFalse, corev1.ConditionTrue
+			}
+			status.Conditions = []cassdcapi.DatacenterCondition{
+				{Type: cassdcapi.DatacenterReady, Status: ready},
+				{Type: cassdcapi.DatacenterStopped, Status: stoppedStatus},
+			}
+			*changed = append(*changed, dc.Meta.Name)
+			_ = kubeClient.Status().Update(ctx, kc)
+			break
+		}
+	}
+}
+
+func TestClusterStoppedStateOrder(t *testing.T) {
+	require := require.New(t)
+
+	dcs, err := ClusterStoppedStateOrder(testCluster(), false)
+	require.NoError(err)
+	require.Equal([]string{"dc1", "dc2", "dc3"}, dcs)
+
+	dcs, err = ClusterStoppedStateOrder(testCluster(), true)
+	require.NoError(err)
+	require.Equal([]string{"dc3", "dc2", "dc1"}, dcs)
+
+	_, err = ClusterStoppedStateOrder(&k8ssandraapi.K8ssandraCluster{}, true)
+	require.ErrorIs(err, errNoClusterDatacenters)
+}
+
+func TestSetClusterStoppedState(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	kubeClient := clusterClient(t)
+	manager := NewManager(kubeClient)
+
+	modified, err := manager.SetClusterStoppedState(ctx, "demo", "ns1", true, cmdutil.DryRunClient)
+	require.NoError(err)
+	for _, dc := range modified.Spec.Cassandra.Datacenters {
+		require.True(dc.Stopped)
+	}
+
+	stored, err := manager.K8ssandraCluster(ctx, "demo", "ns1")
+	require.NoError(err)
+	for _, dc := range stored.Spec.Cassandra.Datacenters {
+		require.False(dc.Stopped)
+	}
+
+	_, err = manager.SetClusterDatacenterStoppedState(ctx, "demo", "ns1", "dc4", true, cmdutil.DryRunNone)
+	require.ErrorIs(err, errUnknownDatacenter)
+}
+
+func TestModifyClusterStoppedState(t *testing.T) {
+	require := require.New(t)
+	kubeClient := clusterClient(t)
+	manager := NewManager(kubeClient)
+
+	var changed []string
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		fakeClusterOperator(ctx, kubeClient, &changed)
+	}()
+
+	var started []string
+	err := manager.ModifyClusterStoppedState(context.Background(), "demo", "ns1", true, 5*time.Second, func(dc string) {
+		started = append(started, dc)
+	})
+	require.NoError(err)
+
+	err = manager.ModifyClusterStoppedState(context.Background(), "demo", "ns1", false, 5*time.Second, func(dc string) {
+		started = append(started, dc)
+	})
+	require.NoError(err)
+
+	cancel()
+	wg.Wait()
+
+	require.Equal([]string{"dc3", "dc2", "dc1", "dc1", "dc2", "dc3"}, started)
+	require.Equal(started, changed)
+
+	kc, err := manager.K8ssandraCluster(context.Background(), "demo", "ns1")
+	require.NoError(err)
+	for _, dc := range kc.Spec.Cassandra.Datacenters {
+		require.False(dc.Stopped)
+	}
+}
+
+func TestSetClusterDatacenterSize(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	kubeClient := clusterClient(t)
+	manager := NewManager(kubeClient)
+	key := types.NamespacedName{Name: "demo", Namespace: "ns1"}
+
+	modified, err := manager.SetClusterDatacenterSize(ctx, testCluster(), "dc2", 6, cmdutil.DryRunClient)
+	require.NoError(err)
+	require.Equal(int32(6), modified.Spec.Cassandra.Datacenters[1].Size)
+
+	stored := &k8ssandraapi.K8ssandraCluster{}
+	require.NoError(kubeClient.Get(ctx, key, stored))
+	require.Equal(int32(3), stored.Spec.Cassandra.Datacenters[1].Size)
+
+	_, err = manager.SetClusterDatacenterSize(ctx, stored, "dc2", 6, cmdutil.DryRunNone)
+	require.NoError(err)
+
+	require.NoError(kubeClient.Get(ctx, key, stored))
+	require.Equal(int32(6), stored.Spec.Cassandra.Datacenters[1].Size)
+	require.Equal(int32(3), stored.Spec.Cassandra.Datacenters[0].Size)
+
+	_, err = manager.SetClusterDatacenterSize(ctx, stored, "dc4", 6, cmdutil.DryRunNone)
+	require.ErrorIs(err, errUnknownDatacenter)
+}
+
+func TestSetClusterDatacenterVersion(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	kubeClient := clusterClient(t)
+	manager := NewManager(kubeClient)
+	key := types.NamespacedName{Name: "demo", Namespace: "ns1"}
+
+	_, err := manager.SetClusterDatacenterVersion(ctx, testCluster(), "dc2", "5.0.2", "", cmdutil.DryRunNone)
+	require.NoError(err)
+
+	stored := &k8ssandraapi.K8ssandraCluster{}
+	require.NoError(kubeClient.Get(ctx, key, stored))
+	require.Equal("5.0.2", stored.Spec.Cassandra.Datacenters[1].ServerVersion)
+	require.Empty(stored.Spec.Cassandra.Datacenters[1].ServerImage)
+	require.Empty(stored.Spec.Cassandra.Datacenters[0].ServerVersion)
+
+	_, err = manager.SetClusterDatacenterVersion(ctx, stored, "dc2", "5.0.3", "example/cassandra:5.0.3", cmdutil.DryRunNone)
+	require.NoError(err)
+
+	require.NoError(kubeClient.Get(ctx, key, stored))
+	require.Equal("5.0.3", stored.Spec.Cassandra.Datacenters[1].ServerVersion)
+	require.Equal("example/cassandra:5.0.3", stored.Spec.Cassandra.Datacenters[1].ServerImage)
+}
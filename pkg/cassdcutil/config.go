@@ -1,10 +1,22 @@
 package cassdcutil
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
 	"github.com/Jeffail/gabs/v2"
 	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	k8ssandraapi "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+var errClusterNotFound = errors.New("the datacenter is managed by a K8ssandraCluster which is not in this Kubernetes cluster, use the context of the control plane")
+
 func ClientEncryptionEnabled(dc *cassdcapi.CassandraDatacenter) bool {
 	config, err := gabs.ParseJSON(dc.Spec.Config)
 	if err != nil {
@@ -32,3 +44,97 @@ func SubSectionOfCassYaml(dc *cassdcapi.CassandraDatacenter, section string) map
 
 	return config.Path("cassandra-yaml").Path(section).ChildrenMap()
 }
+
+// DatacenterCluster returns the K8ssandraCluster managing the datacenter, or nil if the datacenter is not part of one
+func (c *CassManager) DatacenterCluster(ctx context.Context, dc *cassdcapi.CassandraDatacenter) (*k8ssandraapi.K8ssandraCluster, error) {
+	name, found := dc.Labels[k8ssandraapi.K8ssandraClusterNameLabel]
+	if !found {
+		return nil, nil
+	}
+
+	namespace, found := dc.Labels[k8ssandraapi.K8ssandraClusterNamespaceLabel]
+	if !found {
+		namespace = dc.Namespace
+	}
+
+	kc, err := c.K8ssandraCluster(ctx, name, namespace)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("%w: %s/%s", errClusterNotFound, namespace, name)
+		}
+		return nil, err
+	}
+
+	return kc, nil
+}
+
+// ClusterDatacenterConfig returns the config of the datacenter in the K8ssandraCluster spec, nil if none is set
+func ClusterDatacenterConfig(kc *k8ssandraapi.K8ssandraCluster, dcName string) (*k8ssandraapi.CassandraConfig, error) {
+	i, err := clusterDatacenterIndex(kc, dcName)
+	if err != nil {
+		return nil, err
+	}
+
+	return kc.Spec.Cassandra.Datacenters[i].CassandraConfig, nil
+}
+
+// SetConfig replaces Spec.Config of the datacenter. With a dry run strategy the datacenter is not persisted, the
+// returned object is the one that would have been stored.
+func (c *CassManager) SetConfig(ctx context.Context, cassdc *cassdcapi.CassandraDatacenter, config json.RawMessage, dryRun cmdutil.DryRunStrategy) (*cassdcapi.CassandraDatacenter, error) {
+	cassdc = cassdc.DeepCopy()
+	patch := client.MergeFrom(cassdc.DeepCopy())
+
+	cassdc.Spec.Config = config
+
+	if err := c.patch(ctx, cassdc, patch, dryRun); err != nil {
+		return nil, err
+	}
+
+	return cassdc, nil
+}
+
+// SetClusterDatacenterConfig replaces the config of the datacenter in the K8ssandraCluster spec. k8ssandra-operator
+// renders the CassandraDatacenter config from it, overwriting any change made directly to the CassandraDatacenter.
+func (c *CassManager) SetClusterDatacenterConfig(ctx context.Context, kc *k8ssandraapi.K8ssandraCluster, dcName string, config *k8ssandraapi.CassandraConfig, dryRun cmdutil.DryRunStrategy) (*k8ssandraapi.K8ssandraCluster, error) {
+	i, err := clusterDatacenterIndex(kc, dcName)
+	if err != nil {
+		return nil, err
+	}
+
+	kc = kc.DeepCopy()
+	patch := client.MergeFrom(kc.DeepCopy())
+
+	kc.Spec.Cassandra.Datacenters[i].CassandraConfig = config
+
+	if err := c.patch(ctx, kc, patch, dryRun); err != nil {
+		return nil, err
+	}
+
+	return kc, nil
+}
+
+func clusterDatacenterIndex(kc *k8ssandraapi.K8ssandraCluster, dcName string) (int, error) {
+	if kc.Spec.Cassandra == nil {
+		return -1, errNoClusterDatacenters
+	}
+
+	i := slices.IndexFunc(kc.Spec.Cassandra.Datacenters, func(dc k8ssandraapi.CassandraDatacenterTemplate) bool {
+		return dc.Meta.Name == dcName
+	})
+	if i < 0 {
+		return -1, fmt.Errorf("%w: %s", errUnknownDatacenter, dcName)
+	}
+
+	return i, nil
+}
+
+func (c *CassManager) patch(ctx context.Context, obj client.Object, patch client.Patch, dryRun cmdutil.DryRunStrategy) error {
+	switch dryRun {
+	case cmdutil.DryRunClient:
+		return nil
+	case cmdutil.DryRunServer:
+		return c.client.Patch(ctx, obj, patch, client.DryRunAll)
+	default:
+		return c.client.Patch(ctx, obj, patch)
+	}
+}
@@ -1,11 +1,20 @@
 package cassdcutil
 
 import (
+	"context"
 	"encoding/json"
 	"testing"
 
 	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	k8ssandraapi "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/unstructured"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 var clientEncryptionEnabled = `
@@ -99,3 +108,81 @@ func TestSubSectionPart(t *testing.T) {
 	assert.True(ok)
 	assert.False(optional)
 }
+
+func TestSetConfig(t *testing.T) {
+	require := require.New(t)
+	scheme := runtime.NewScheme()
+	require.NoError(cassdcapi.AddToScheme(scheme))
+
+	dc := &cassdcapi.CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{Name: "dc1", Namespace: "ns1"},
+		Spec: cassdcapi.CassandraDatacenterSpec{
+			Config: json.RawMessage(`{"cassandra-yaml": {"num_tokens": 16, "authenticator": "PasswordAuthenticator"}}`),
+		},
+	}
+	kubeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(dc).Build()
+	manager := NewManager(kubeClient)
+
+	kc, err := manager.DatacenterCluster(context.TODO(), dc)
+	require.NoError(err)
+	require.Nil(kc)
+
+	updated, err := manager.SetConfig(context.TODO(), dc, json.RawMessage(`{"cassandra-yaml": {"num_tokens": 8}}`), cmdutil.DryRunNone)
+	require.NoError(err)
+
+	stored := &cassdcapi.CassandraDatacenter{}
+	require.NoError(kubeClient.Get(context.TODO(), client.ObjectKeyFromObject(dc), stored))
+	require.JSONEq(`{"cassandra-yaml": {"num_tokens": 8}}`, string(stored.Spec.Config))
+	require.JSONEq(string(updated.Spec.Config), string(stored.Spec.Config))
+}
+
+func TestSetClusterDatacenterConfig(t *testing.T) {
+	require := require.New(t)
+	kubeClient := clusterClient(t)
+	manager := NewManager(kubeClient)
+
+	dc := &cassdcapi.CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "dc2",
+			Namespace: "ns1",
+			Labels:    map[string]string{k8ssandraapi.K8ssandraClusterNameLabel: "demo"},
+		},
+	}
+
+	kc, err := manager.DatacenterCluster(context.TODO(), dc)
+	require.NoError(err)
+	require.Equal("demo", kc.Name)
+
+	config, err := ClusterDatacenterConfig(kc, "dc2")
+	require.NoError(err)
+	require.Nil(config)
+
+	_, err = ClusterDatacenterConfig(kc, "dc4")
+	require.ErrorIs(err, errUnknownDatacenter)
+
+	config = &k8ssandraapi.CassandraConfig{
+		CassandraYaml: unstructured.Unstructured{"num_tokens": int64(16)},
+	}
+
+	updated, err := manager.SetClusterDatacenterConfig(context.TODO(), kc, "dc2", config, cmdutil.DryRunClient)
+	require.NoError(err)
+	require.Equal(config, updated.Spec.Cassandra.Datacenters[1].CassandraConfig)
+	require.Nil(kc.Spec.Cassandra.Datacenters[1].CassandraConfig)
+
+	kc, err = manager.K8ssandraCluster(context.TODO(), "demo", "ns1")
+	require.NoError(err)
+	require.Nil(kc.Spec.Cassandra.Datacenters[1].CassandraConfig)
+
+	_, err = manager.SetClusterDatacenterConfig(context.TODO(), kc, "dc2", config, cmdutil.DryRunNone)
+	require.NoError(err)
+
+	kc, err = manager.K8ssandraCluster(context.TODO(), "demo", "ns1")
+	require.NoError(err)
+	require.NotNil(kc.Spec.Cassandra.Datacenters[1].CassandraConfig)
+	require.EqualValues(16, kc.Spec.Cassandra.Datacenters[1].CassandraConfig.CassandraYaml["num_tokens"])
+	require.Nil(kc.Spec.Cassandra.Datacenters[0].CassandraConfig)
+
+	dc.Labels[k8ssandraapi.K8ssandraClusterNameLabel] = "missing"
+	_, err = manager.DatacenterCluster(context.TODO(), dc)
+	require.ErrorIs(err, errClusterNotFound)
+}
@@ -2,15 +2,21 @@ package cassdcutil
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
 
 	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	"github.com/k8ssandra/k8ssandra-client/pkg/kubernetes"
+	k8ssandraapi "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+var errNoReadyPod = errors.New("no ready pod in datacenter")
+
 // CassandraDatacenter fetches the CassandraDatacenter by its name and namespace
 func (c *CassManager) CassandraDatacenter(ctx context.Context, name, namespace string) (*cassdcapi.CassandraDatacenter, error) {
 	cassdcKey := types.NamespacedName{Namespace: namespace, Name: name}
@@ -23,6 +29,18 @@ func (c *CassManager) CassandraDatacenter(ctx context.Context, name, namespace s
 	return cassdc, nil
 }
 
+// K8ssandraCluster fetches the K8ssandraCluster by its name and namespace
+func (c *CassManager) K8ssandraCluster(ctx context.Context, name, namespace string) (*k8ssandraapi.K8ssandraCluster, error) {
+	kcKey := types.NamespacedName{Namespace: namespace, Name: name}
+	kc := &k8ssandraapi.K8ssandraCluster{}
+
+	if err := c.client.Get(ctx, kcKey, kc); err != nil {
+		return nil, err
+	}
+
+	return kc, nil
+}
+
 // PodDatacenter returns the CassandraDatacenter instance of the pod if it's managed by cass-operator
 // We use the OwnerReference method because the pod labels are incorrect if datacenter name override is used
 func (c *CassManager) PodDatacenter(ctx context.Context, podName, namespace string) (*cassdcapi.CassandraDatacenter, error) {
@@ -64,3 +82,23 @@ func (c *CassManager) CassandraDatacenterPods(ctx context.Context, cassdc *cassd
 	err := c.client.List(ctx, podList, client.InNamespace(cassdc.Namespace), client.MatchingLabels(map[string]string{cassdcapi.DatacenterLabel: cassdc.Name}))
 	return podList, err
 }
+
+// ReadyPod returns the first ready pod of the CassandraDatacenter by name
+func (c *CassManager) ReadyPod(ctx context.Context, cassdc *cassdcapi.CassandraDatacenter) (*corev1.Pod, error) {
+	pods, err := c.CassandraDatacenterPods(ctx, cassdc)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(pods.Items, func(i, j int) bool {
+		return pods.Items[i].Name < pods.Items[j].Name
+	})
+
+	for i := range pods.Items {
+		if pods.Items[i].DeletionTimestamp == nil && kubernetes.PodReady(&pods.Items[i]) {
+			return &pods.Items[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", errNoReadyPod, cassdc.Name)
+}
@@ -2,12 +2,17 @@ package cassdcutil
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	controlapi "github.com/k8ssandra/cass-operator/apis/control/v1alpha1"
+	"github.com/k8ssandra/k8ssandra-client/pkg/kubernetes"
 	"github.com/k8ssandra/k8ssandra-client/pkg/tasks"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	waitutil "k8s.io/apimachinery/pkg/util/wait"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -28,41 +33,135 @@ func NewManager(client client.Client) *CassManager {
 
 // ModifyStoppedState either stops or starts the cluster and does nothing if the state is already as requested
 func (c *CassManager) ModifyStoppedState(ctx context.Context, name, namespace string, stop, wait bool) error {
-	cassdc, err := c.CassandraDatacenter(ctx, name, namespace)
+	cassdc, err := c.SetStoppedState(ctx, name, namespace, stop, cmdutil.DryRunNone)
 	if err != nil {
 		return err
 	}
 
+	if wait {
+		return c.WaitForStoppedState(ctx, cassdc, stop, defaultTimeout)
+	}
+
+	return nil
+}
+
+// WaitForStoppedState waits until the datacenter has stopped or is ready again after starting. Both conditions are
+// checked together, the timeout applies to the whole wait.
+func (c *CassManager) WaitForStoppedState(ctx context.Context, cassdc *cassdcapi.CassandraDatacenter, stop bool, timeout time.Duration) error {
+	if timeout == 0 {
+		timeout = defaultTimeout
+	}
+
+	stopped, ready := corev1.ConditionFalse, corev1.ConditionTrue
+	if stop {
+		stopped, ready = corev1.ConditionTrue, corev1.ConditionFalse
+	}
+
+	return c.waitFor(ctx, cassdc, defaultPollInterval, timeout, func(obj client.Object) (bool, error) {
+		if obj == nil {
+			return false, fmt.Errorf("CassandraDatacenter %s was deleted", cassdc.Name)
+		}
+
+		current := obj.(*cassdcapi.CassandraDatacenter)
+		return current.Status.GetConditionStatus(cassdcapi.DatacenterStopped) == stopped &&
+			current.Status.GetConditionStatus(cassdcapi.DatacenterReady) == ready, nil
+	})
+}
+
+// SetStoppedState updates Spec.Stopped of the datacenter without waiting for the change to happen. With a dry run
+// strategy the datacenter is not persisted, the returned object is the one that would have been stored.
+func (c *CassManager) SetStoppedState(ctx context.Context, name, namespace string, stop bool, dryRun cmdutil.DryRunStrategy) (*cassdcapi.CassandraDatacenter, error) {
+	cassdc, err := c.CassandraDatacenter(ctx, name, namespace)
+	if err != nil {
+		return nil, err
+	}
+
 	cassdc = cassdc.DeepCopy()
 
 	cassdc.Spec.Stopped = stop
-	if err := c.client.Update(ctx, cassdc); err != nil {
-		// r.Log.Error(err, "failed to update the cassandradatacenter", "CassandraDatacenter", cassdcKey)
-		// return ctrl.Result{RequeueAfter: 10 * time.Second}, err
-		return err
+
+	switch dryRun {
+	case cmdutil.DryRunClient:
+		return cassdc, nil
+	case cmdutil.DryRunServer:
+		err = c.client.Update(ctx, cassdc, client.DryRunAll)
+	default:
+		err = c.client.Update(ctx, cassdc)
 	}
 
-	if wait {
-		if stop {
-			if err := c.WaitForStatus(ctx, cassdc, cassdcapi.DatacenterStopped, corev1.ConditionTrue, defaultPollInterval, defaultTimeout); err != nil {
-				return err
-			}
+	if err != nil {
+		return nil, err
+	}
 
-			if err := c.WaitForStatus(ctx, cassdc, cassdcapi.DatacenterReady, corev1.ConditionFalse, defaultPollInterval, defaultTimeout); err != nil {
-				return err
-			}
-		} else {
-			if err := c.WaitForStatus(ctx, cassdc, cassdcapi.DatacenterStopped, corev1.ConditionFalse, defaultPollInterval, defaultTimeout); err != nil {
-				return err
-			}
+	return cassdc, nil
+}
+
+// SetSize updates Spec.Size of the datacenter without waiting for the change to happen. With a dry run strategy the
+// datacenter is not persisted, the returned object is the one that would have been stored.
+func (c *CassManager) SetSize(ctx context.Context, name, namespace string, size int32, dryRun cmdutil.DryRunStrategy) (*cassdcapi.CassandraDatacenter, error) {
+	cassdc, err := c.CassandraDatacenter(ctx, name, namespace)
+	if err != nil {
+		return nil, err
+	}
 
-			if err := c.WaitForStatus(ctx, cassdc, cassdcapi.DatacenterReady, corev1.ConditionTrue, defaultPollInterval, defaultTimeout); err != nil {
-				return err
+	cassdc = cassdc.DeepCopy()
+
+	cassdc.Spec.Size = size
+
+	switch dryRun {
+	case cmdutil.DryRunClient:
+		return cassdc, nil
+	case cmdutil.DryRunServer:
+		err = c.client.Update(ctx, cassdc, client.DryRunAll)
+	default:
+		err = c.client.Update(ctx, cassdc)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return cassdc, nil
+}
+
+// WaitForSize waits until the datacenter has the Spec.Size of cassdc, is Ready with that many ready pods and is no
+// longer scaling
+func (c *CassManager) WaitForSize(ctx context.Context, cassdc *cassdcapi.CassandraDatacenter, timeout time.Duration) error {
+	if timeout == 0 {
+		timeout = defaultTimeout
+	}
+
+	return waitutil.PollUntilContextTimeout(ctx, defaultPollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+		current, err := c.CassandraDatacenter(ctx, cassdc.Name, cassdc.Namespace)
+		if err != nil {
+			return false, err
+		}
+
+		// The size of a K8ssandraCluster datacenter reaches the CassandraDatacenter only once the operator reconciles it
+		if current.Spec.Size != cassdc.Spec.Size {
+			return false, nil
+		}
+
+		if current.Status.GetConditionStatus(cassdcapi.DatacenterScalingUp) == corev1.ConditionTrue ||
+			current.Status.GetConditionStatus(cassdcapi.DatacenterScalingDown) == corev1.ConditionTrue ||
+			current.Status.GetConditionStatus(cassdcapi.DatacenterReady) != corev1.ConditionTrue {
+			return false, nil
+		}
+
+		pods, err := c.CassandraDatacenterPods(ctx, current)
+		if err != nil {
+			return false, err
+		}
+
+		ready := 0
+		for _, pod := range pods.Items {
+			if pod.DeletionTimestamp == nil && kubernetes.PodReady(&pod) {
+				ready++
 			}
 		}
-	}
 
-	return nil
+		return ready == int(current.Spec.Size) && len(pods.Items) == ready, nil
+	})
 }
 
 func (c *CassManager) RefreshStatus(ctx context.Context, cassdc *cassdcapi.CassandraDatacenter, status cassdcapi.DatacenterConditionType, wanted corev1.ConditionStatus) (bool, error) {
@@ -75,26 +174,28 @@ func (c *CassManager) RefreshStatus(ctx context.Context, cassdc *cassdcapi.Cassa
 }
 
 // RestartDc creates a task to restart the cluster and waits for completion if wait is set to true
-func (c *CassManager) RestartDc(ctx context.Context, name, namespace, rack string, wait bool) error {
+func (c *CassManager) RestartDc(ctx context.Context, name, namespace, rack string, wait bool, opts ...tasks.TaskOption) (*controlapi.CassandraTask, error) {
 	cassdc, err := c.CassandraDatacenter(ctx, name, namespace)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	task, err := tasks.CreateRestartTask(ctx, c.client, cassdc, rack)
+	task, err := tasks.CreateRestartTask(ctx, c.client, cassdc, rack, opts...)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if wait {
 		err = tasks.WaitForCompletion(ctx, c.client, task)
 		if err != nil {
-			return err
+			return task, err
 		}
 	}
-	return nil
+	return task, nil
 }
 
+// WaitForStatus waits until the datacenter condition has the wanted status. The datacenter is watched if the client
+// supports it, otherwise it is polled every interval.
 func (c *CassManager) WaitForStatus(ctx context.Context, cassdc *cassdcapi.CassandraDatacenter, status cassdcapi.DatacenterConditionType, wanted corev1.ConditionStatus, interval, timeout time.Duration) error {
 	if interval == 0 {
 		interval = defaultPollInterval
@@ -104,10 +205,25 @@ func (c *CassManager) WaitForStatus(ctx context.Context, cassdc *cassdcapi.Cassa
 		timeout = defaultTimeout
 	}
 
-	if err := waitutil.PollUntilContextTimeout(ctx, interval, timeout, true, func(context.Context) (bool, error) {
-		return c.RefreshStatus(ctx, cassdc, status, wanted)
-	}); err != nil {
+	return c.waitFor(ctx, cassdc, interval, timeout, kubernetes.HasCondition(string(status), string(wanted)))
+}
+
+// waitFor watches the object until condition is met if the client supports watches, otherwise it polls the object
+// every interval
+func (c *CassManager) waitFor(ctx context.Context, obj client.Object, interval, timeout time.Duration, condition kubernetes.ObjectCondition) error {
+	if watchClient, ok := c.client.(client.WithWatch); ok {
+		_, err := kubernetes.WaitFor(ctx, watchClient, obj, timeout, condition)
 		return err
 	}
-	return nil
+
+	return waitutil.PollUntilContextTimeout(ctx, interval, timeout, true, func(ctx context.Context) (bool, error) {
+		current := obj.DeepCopyObject().(client.Object)
+		if err := c.client.Get(ctx, client.ObjectKeyFromObject(obj), current); err != nil {
+			if apierrors.IsNotFound(err) {
+				return condition(nil)
+			}
+			return false, err
+		}
+		return condition(current)
+	})
 }
@@ -0,0 +1,147 @@
+package cassdcutil
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	"github.com/k8ssandra/k8ssandra-client/pkg/kubernetes"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestSetStoppedState(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	require.NoError(cassdcapi.AddToScheme(scheme))
+
+	dc := &cassdcapi.CassandraDatacenter{ObjectMeta: metav1.ObjectMeta{Name: "dc1", Namespace: "ns1"}}
+	kubeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(dc).Build()
+	manager := NewManager(kubeClient)
+	key := types.NamespacedName{Name: "dc1", Namespace: "ns1"}
+
+	for _, dryRun := range []cmdutil.DryRunStrategy{cmdutil.DryRunClient, cmdutil.DryRunServer} {
+		modified, err := manager.SetStoppedState(ctx, "dc1", "ns1", true, dryRun)
+		require.NoError(err)
+		require.True(modified.Spec.Stopped)
+
+		stored := &cassdcapi.CassandraDatacenter{}
+		require.NoError(kubeClient.Get(ctx, key, stored))
+		require.False(stored.Spec.Stopped)
+	}
+
+	_, err := manager.SetStoppedState(ctx, "dc1", "ns1", true, cmdutil.DryRunNone)
+	require.NoError(err)
+
+	stored := &cassdcapi.CassandraDatacenter{}
+	require.NoError(kubeClient.Get(ctx, key, stored))
+	require.True(stored.Spec.Stopped)
+}
+
+func TestSetSize(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	require.NoError(cassdcapi.AddToScheme(scheme))
+
+	dc := &cassdcapi.CassandraDatacenter{ObjectMeta: metav1.ObjectMeta{Name: "dc1", Namespace: "ns1"}, Spec: cassdcapi.CassandraDatacenterSpec{Size: 3}}
+	kubeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(dc).Build()
+	manager := NewManager(kubeClient)
+	key := types.NamespacedName{Name: "dc1", Namespace: "ns1"}
+
+	modified, err := manager.SetSize(ctx, "dc1", "ns1", 6, cmdutil.DryRunClient)
+	require.NoError(err)
+	require.Equal(int32(6), modified.Spec.Size)
+
+	stored := &cassdcapi.CassandraDatacenter{}
+	require.NoError(kubeClient.Get(ctx, key, stored))
+	require.Equal(int32(3), stored.Spec.Size)
+
+	_, err = manager.SetSize(ctx, "dc1", "ns1", 6, cmdutil.DryRunNone)
+	require.NoError(err)
+
+	require.NoError(kubeClient.Get(ctx, key, stored))
+	require.Equal(int32(6), stored.Spec.Size)
+}
+
+func TestWaitForStatus(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	require.NoError(cassdcapi.AddToScheme(scheme))
+
+	dc := &cassdcapi.CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{Name: "dc1", Namespace: "ns1"},
+		Status: cassdcapi.CassandraDatacenterStatus{
+			Conditions: []cassdcapi.DatacenterCondition{{Type: cassdcapi.DatacenterReady, Status: corev1.ConditionFalse}},
+		},
+	}
+	kubeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(dc).
+		WithStatusSubresource(dc).
+		WithIndex(dc, "metadata.name", func(obj client.Object) []string { return []string{obj.GetName()} }).
+		Build()
+	manager := NewManager(kubeClient)
+
+	err := manager.WaitForStatus(ctx, dc, cassdcapi.DatacenterReady, corev1.ConditionTrue, 0, 100*time.Millisecond)
+	require.ErrorIs(err, kubernetes.ErrWaitTimeout)
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		ready := dc.DeepCopy()
+		ready.Status.Conditions = []cassdcapi.DatacenterCondition{{Type: cassdcapi.DatacenterReady, Status: corev1.ConditionTrue}}
+		_ = kubeClient.Status().Update(ctx, ready)
+	}()
+
+	require.NoError(manager.WaitForStatus(ctx, dc, cassdcapi.DatacenterReady, corev1.ConditionTrue, 0, 5*time.Second))
+}
+
+func TestWaitForStoppedState(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	require.NoError(cassdcapi.AddToScheme(scheme))
+
+	// Stopped is reported but the datacenter is still Ready, the wait must time out once for both conditions
+	dc := &cassdcapi.CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{Name: "dc1", Namespace: "ns1"},
+		Status: cassdcapi.CassandraDatacenterStatus{
+			Conditions: []cassdcapi.DatacenterCondition{
+				{Type: cassdcapi.DatacenterStopped, Status: corev1.ConditionTrue},
+				{Type: cassdcapi.DatacenterReady, Status: corev1.ConditionTrue},
+			},
+		},
+	}
+	kubeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(dc).
+		WithStatusSubresource(dc).
+		WithIndex(dc, "metadata.name", func(obj client.Object) []string { return []string{obj.GetName()} }).
+		Build()
+	manager := NewManager(kubeClient)
+
+	err := manager.WaitForStoppedState(ctx, dc, true, 100*time.Millisecond)
+	require.ErrorIs(err, kubernetes.ErrWaitTimeout)
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		stopped := dc.DeepCopy()
+		stopped.Status.Conditions[1].Status = corev1.ConditionFalse
+		_ = kubeClient.Status().Update(ctx, stopped)
+	}()
+
+	require.NoError(manager.WaitForStoppedState(ctx, dc, true, 5*time.Second))
+}
@@ -0,0 +1,74 @@
+package cassdcutil
+
+import (
+	"context"
+	"sort"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	"github.com/k8ssandra/k8ssandra-client/pkg/kubernetes"
+	"github.com/k8ssandra/k8ssandra-client/pkg/tasks"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// PodProgress is the state of a single pod of a datacenter while an operation is running
+type PodProgress struct {
+	Pod      string
+	Phase    string
+	Ready    bool
+	Restarts int32
+
+	// Job is the state of the task's job on the pod as tracked by cass-operator, empty if there is none
+	Job string
+}
+
+// DatacenterProgress returns the state of every pod of the datacenter. If task is set, the job state of the
+// CassandraTask on each pod is included.
+func (c *CassManager) DatacenterProgress(ctx context.Context, cassdc *cassdcapi.CassandraDatacenter, task *types.NamespacedName) ([]PodProgress, error) {
+	pods, err := c.CassandraDatacenterPods(ctx, cassdc)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make(map[string]string)
+	if task != nil {
+		summary, err := tasks.GetTask(ctx, c.client, *task)
+		if err != nil {
+			return nil, err
+		}
+
+		statuses, err := tasks.PodJobStatuses(ctx, c.client, summary)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, status := range statuses {
+			jobs[status.Pod] = status.Status
+		}
+	}
+
+	progress := make([]PodProgress, 0, len(pods.Items))
+	for _, pod := range pods.Items {
+		p := PodProgress{
+			Pod:   pod.Name,
+			Phase: string(pod.Status.Phase),
+			Ready: kubernetes.PodReady(&pod),
+			Job:   jobs[pod.Name],
+		}
+
+		if pod.DeletionTimestamp != nil {
+			p.Phase = "Terminating"
+		}
+
+		for _, status := range pod.Status.ContainerStatuses {
+			p.Restarts += status.RestartCount
+		}
+
+		progress = append(progress, p)
+	}
+
+	sort.Slice(progress, func(i, j int) bool {
+		return progress[i].Pod < progress[j].Pod
+	})
+
+	return progress, nil
+}
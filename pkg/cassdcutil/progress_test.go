@@ -0,0 +1,62 @@
+package cassdcutil
+
+import (
+	"context"
+	"testing"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	controlapi "github.com/k8ssandra/cass-operator/apis/control/v1alpha1"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestDatacenterProgress(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	dc := &cassdcapi.CassandraDatacenter{ObjectMeta: metav1.ObjectMeta{Name: "dc1", Namespace: "ns1"}}
+	task := &controlapi.CassandraTask{
+		ObjectMeta: metav1.ObjectMeta{Name: "dc1-restart", Namespace: "ns1", UID: "task-uid"},
+		Spec: controlapi.CassandraTaskSpec{
+			Datacenter: corev1.ObjectReference{Name: "dc1", Namespace: "ns1"},
+		},
+	}
+
+	restarted := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "dc1-r1-sts-1",
+			Namespace:   "ns1",
+			Labels:      map[string]string{cassdcapi.DatacenterLabel: "dc1"},
+			Annotations: map[string]string{"control.k8ssandra.io/job-task-uid": `{"id":"1","status":"RUNNING"}`},
+		},
+		Status: corev1.PodStatus{
+			Phase:             corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "cassandra", RestartCount: 1}, {Name: "server-system-logger", RestartCount: 1}},
+		},
+	}
+	waiting := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "dc1-r1-sts-0", Namespace: "ns1", Labels: map[string]string{cassdcapi.DatacenterLabel: "dc1"}},
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+		},
+	}
+
+	kubeClient := fake.NewClientBuilder().WithScheme(statusScheme(t)).WithObjects(dc, task, restarted, waiting).Build()
+	manager := NewManager(kubeClient)
+
+	progress, err := manager.DatacenterProgress(ctx, dc, nil)
+	require.NoError(err)
+	require.Equal([]PodProgress{
+		{Pod: "dc1-r1-sts-0", Phase: "Running", Ready: true},
+		{Pod: "dc1-r1-sts-1", Phase: "Running", Restarts: 2},
+	}, progress)
+
+	progress, err = manager.DatacenterProgress(ctx, dc, &types.NamespacedName{Name: "dc1-restart", Namespace: "ns1"})
+	require.NoError(err)
+	require.Equal("", progress[0].Job)
+	require.Equal("RUNNING", progress[1].Job)
+}
@@ -0,0 +1,95 @@
+package cassdcutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var errReplicationTooHigh = errors.New("datacenter would have fewer nodes than the replication factor of keyspaces")
+
+// DecommissionedPods returns the pods cass-operator decommissions when Spec.Size of the datacenter shrinks to newSize,
+// in the order they are removed. cass-operator removes one node at a time, taking the last pod of the first rack that
+// has more nodes than the balanced split of the remaining nodes.
+func DecommissionedPods(dc *cassdcapi.CassandraDatacenter, newSize int) []string {
+	racks := dc.GetRacks()
+	counts := cassdcapi.SplitRacks(int(dc.Spec.Size), len(racks))
+
+	pods := make([]string, 0)
+	for size := int(dc.Spec.Size); size > newSize; size-- {
+		wanted := cassdcapi.SplitRacks(size-1, len(racks))
+		for i, rack := range racks {
+			if counts[i] > wanted[i] {
+				counts[i]--
+				pods = append(pods, fmt.Sprintf("%s-%d", statefulSetName(dc, rack.Name), counts[i]))
+				break
+			}
+		}
+	}
+
+	return pods
+}
+
+func statefulSetName(dc *cassdcapi.CassandraDatacenter, rack string) string {
+	return fmt.Sprintf("%s-%s-%s-sts", cassdcapi.CleanupForKubernetes(dc.Spec.ClusterName), dc.LabelResourceName(), cassdcapi.CleanupSubdomain(rack))
+}
+
+// CheckReplication verifies no keyspace has a higher replication factor than the number of nodes the datacenter will
+// have. replication maps the keyspaces to their replication factor in the datacenter.
+func CheckReplication(replication map[string]int, size int) error {
+	tooHigh := make([]string, 0)
+	for keyspace, rf := range replication {
+		if rf > size {
+			tooHigh = append(tooHigh, fmt.Sprintf("%s (RF %d)", keyspace, rf))
+		}
+	}
+
+	if len(tooHigh) > 0 {
+		sort.Strings(tooHigh)
+		return fmt.Errorf("%w: %d nodes, %s", errReplicationTooHigh, size, strings.Join(tooHigh, ", "))
+	}
+
+	return nil
+}
+
+// OrphanedPVCs returns the PersistentVolumeClaims of the datacenter which no longer belong to any of its pods, such as
+// the claims of decommissioned nodes cass-operator was unable to delete
+func (c *CassManager) OrphanedPVCs(ctx context.Context, dc *cassdcapi.CassandraDatacenter) ([]corev1.PersistentVolumeClaim, error) {
+	pods, err := c.CassandraDatacenterPods(ctx, dc)
+	if err != nil {
+		return nil, err
+	}
+
+	pvcs := &corev1.PersistentVolumeClaimList{}
+	if err := c.client.List(ctx, pvcs, client.InNamespace(dc.Namespace), client.MatchingLabels{cassdcapi.DatacenterLabel: cassdcapi.CleanLabelValue(dc.Name)}); err != nil {
+		return nil, err
+	}
+
+	// The claims of a StatefulSet are named <volume>-<pod>
+	orphaned := make([]corev1.PersistentVolumeClaim, 0)
+	for _, pvc := range pvcs.Items {
+		owned := false
+		for _, pod := range pods.Items {
+			if strings.HasSuffix(pvc.Name, "-"+pod.Name) {
+				owned = true
+				break
+			}
+		}
+
+		if !owned {
+			orphaned = append(orphaned, pvc)
+		}
+	}
+
+	sort.Slice(orphaned, func(i, j int) bool {
+		return orphaned[i].Name < orphaned[j].Name
+	})
+
+	return orphaned, nil
+}
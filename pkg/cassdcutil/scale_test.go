@@ -0,0 +1,68 @@
+package cassdcutil
+
+import (
+	"context"
+	"testing"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestDecommissionedPods(t *testing.T) {
+	require := require.New(t)
+
+	dc := &cassdcapi.CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{Name: "dc1", Namespace: "ns1"},
+		Spec: cassdcapi.CassandraDatacenterSpec{
+			ClusterName: "demo",
+			Size:        7,
+			Racks:       []cassdcapi.Rack{{Name: "r1"}, {Name: "r2"}, {Name: "r3"}},
+		},
+	}
+
+	// 3/2/2 to 2/2/1: r1 is above the 2/2/2 split of 6 nodes, then r3 is the only rack above the 2/2/1 split
+	require.Equal([]string{"demo-dc1-r1-sts-2", "demo-dc1-r3-sts-1"}, DecommissionedPods(dc, 5))
+	require.Empty(DecommissionedPods(dc, 7))
+}
+
+func TestCheckReplication(t *testing.T) {
+	require := require.New(t)
+
+	replication := map[string]int{"system_auth": 3, "app": 5, "metrics": 2}
+	require.NoError(CheckReplication(replication, 5))
+
+	err := CheckReplication(replication, 2)
+	require.ErrorIs(err, errReplicationTooHigh)
+	require.Contains(err.Error(), "app (RF 5), system_auth (RF 3)")
+	require.NotContains(err.Error(), "metrics")
+}
+
+func TestOrphanedPVCs(t *testing.T) {
+	require := require.New(t)
+
+	dc := &cassdcapi.CassandraDatacenter{ObjectMeta: metav1.ObjectMeta{Name: "dc1", Namespace: "ns1"}}
+	labels := map[string]string{cassdcapi.DatacenterLabel: "dc1"}
+
+	pvc := func(name string) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns1", Labels: labels}}
+	}
+
+	kubeClient := fake.NewClientBuilder().
+		WithScheme(statusScheme(t)).
+		WithObjects(
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "demo-dc1-r1-sts-1", Namespace: "ns1", Labels: labels}},
+			pvc("server-data-demo-dc1-r1-sts-1"),
+			pvc("server-data-demo-dc1-r1-sts-11"),
+			pvc("commitlog-demo-dc1-r1-sts-2"),
+		).
+		Build()
+
+	pvcs, err := NewManager(kubeClient).OrphanedPVCs(context.Background(), dc)
+	require.NoError(err)
+	require.Len(pvcs, 2)
+	require.Equal("commitlog-demo-dc1-r1-sts-2", pvcs[0].Name)
+	require.Equal("server-data-demo-dc1-r1-sts-11", pvcs[1].Name)
+}
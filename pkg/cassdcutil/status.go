@@ -0,0 +1,270 @@
+package cassdcutil
+
+import (
+	"context"
+	"sort"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	"github.com/k8ssandra/k8ssandra-client/pkg/kubernetes"
+	"github.com/k8ssandra/k8ssandra-client/pkg/tasks"
+	k8ssandraapi "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const cassandraContainer = "cassandra"
+
+// DatacenterSummary is the state of a CassandraDatacenter and its pods
+type DatacenterSummary struct {
+	Name      string
+	Namespace string
+
+	// Cluster is the Cassandra cluster name, K8ssandraCluster the owning K8ssandraCluster if any
+	Cluster          string
+	K8ssandraCluster string
+
+	ServerType    string
+	ServerVersion string
+	// Image is the image of the running cassandra containers, or the one set in the spec if there are no pods
+	Image string
+
+	Size      int32
+	ReadyPods int
+	Stopped   bool
+	Progress  string
+
+	// Conditions are the condition types with status True
+	Conditions []string
+
+	Nodes []NodeSummary
+
+	// LastTask is the most recently created CassandraTask targeting the datacenter
+	LastTask *tasks.Summary
+}
+
+// NodeSummary is a single Cassandra node of the datacenter as tracked in its NodeStatuses
+type NodeSummary struct {
+	Pod    string
+	HostID string
+	IP     string
+	Rack   string
+	Ready  bool
+}
+
+// ClusterSummary is the state of a K8ssandraCluster as reported in its status
+type ClusterSummary struct {
+	Name          string
+	Namespace     string
+	Cluster       string
+	ServerVersion string
+	Image         string
+
+	// Conditions are the condition types with status True
+	Conditions []string
+
+	Datacenters []ClusterDatacenterSummary
+}
+
+// ClusterDatacenterSummary is a datacenter of the K8ssandraCluster, which might run in another Kubernetes cluster
+type ClusterDatacenterSummary struct {
+	Name       string
+	Size       int32
+	Stopped    bool
+	Nodes      int
+	Progress   string
+	Conditions []string
+}
+
+// DatacenterSummaries returns the summaries of the CassandraDatacenters of the namespace, or of every namespace if
+// namespace is empty
+func (c *CassManager) DatacenterSummaries(ctx context.Context, namespace string) ([]*DatacenterSummary, error) {
+	dcs := &cassdcapi.CassandraDatacenterList{}
+	if err := c.client.List(ctx, dcs, namespaceOpts(namespace)...); err != nil {
+		return nil, err
+	}
+
+	taskSummaries, err := tasks.ListTasks(ctx, c.client, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]*DatacenterSummary, 0, len(dcs.Items))
+	for i := range dcs.Items {
+		summary, err := c.datacenterSummary(ctx, &dcs.Items[i], taskSummaries)
+		if err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, summary)
+	}
+
+	sort.SliceStable(summaries, func(i, j int) bool {
+		if summaries[i].Namespace != summaries[j].Namespace {
+			return summaries[i].Namespace < summaries[j].Namespace
+		}
+		return summaries[i].Name < summaries[j].Name
+	})
+
+	return summaries, nil
+}
+
+// DatacenterSummary returns the summary of a single CassandraDatacenter
+func (c *CassManager) DatacenterSummary(ctx context.Context, name, namespace string) (*DatacenterSummary, error) {
+	dc, err := c.CassandraDatacenter(ctx, name, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	taskSummaries, err := tasks.ListTasks(ctx, c.client, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.datacenterSummary(ctx, dc, taskSummaries)
+}
+
+func (c *CassManager) datacenterSummary(ctx context.Context, dc *cassdcapi.CassandraDatacenter, taskSummaries []*tasks.Summary) (*DatacenterSummary, error) {
+	summary := &DatacenterSummary{
+		Name:             dc.Name,
+		Namespace:        dc.Namespace,
+		Cluster:          dc.Spec.ClusterName,
+		K8ssandraCluster: dc.Labels[k8ssandraapi.K8ssandraClusterNameLabel],
+		ServerType:       dc.Spec.ServerType,
+		ServerVersion:    dc.Spec.ServerVersion,
+		Image:            dc.Spec.ServerImage,
+		Size:             dc.Spec.Size,
+		Stopped:          dc.Spec.Stopped,
+		Progress:         string(dc.Status.CassandraOperatorProgress),
+		Conditions:       make([]string, 0, len(dc.Status.Conditions)),
+		Nodes:            make([]NodeSummary, 0, len(dc.Status.NodeStatuses)),
+	}
+
+	for _, cond := range dc.Status.Conditions {
+		if cond.Status == corev1.ConditionTrue {
+			summary.Conditions = append(summary.Conditions, string(cond.Type))
+		}
+	}
+
+	pods, err := c.CassandraDatacenterPods(ctx, dc)
+	if err != nil {
+		return nil, err
+	}
+
+	ready := make(map[string]bool, len(pods.Items))
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		ready[pod.Name] = kubernetes.PodReady(pod)
+		if ready[pod.Name] {
+			summary.ReadyPods++
+		}
+
+		for _, container := range pod.Spec.Containers {
+			if container.Name == cassandraContainer && container.Image != "" {
+				summary.Image = container.Image
+			}
+		}
+	}
+
+	for pod, status := range dc.Status.NodeStatuses {
+		summary.Nodes = append(summary.Nodes, NodeSummary{
+			Pod:    pod,
+			HostID: status.HostID,
+			IP:     status.IP,
+			Rack:   status.Rack,
+			Ready:  ready[pod],
+		})
+	}
+
+	sort.Slice(summary.Nodes, func(i, j int) bool {
+		return summary.Nodes[i].Pod < summary.Nodes[j].Pod
+	})
+
+	for _, task := range taskSummaries {
+		if task.Kind != tasks.KindCassandraTask || task.Namespace != dc.Namespace || len(task.Datacenters) == 0 || task.Datacenters[0] != dc.Name {
+			continue
+		}
+
+		if summary.LastTask == nil || summary.LastTask.Created.Before(&task.Created) {
+			summary.LastTask = task
+		}
+	}
+
+	return summary, nil
+}
+
+// ClusterSummaries returns the summaries of the K8ssandraClusters of the namespace, or of every namespace if namespace
+// is empty. Nothing is returned if the K8ssandraCluster CRD is not installed.
+func (c *CassManager) ClusterSummaries(ctx context.Context, namespace string) ([]*ClusterSummary, error) {
+	kcs := &k8ssandraapi.K8ssandraClusterList{}
+	if err := c.client.List(ctx, kcs, namespaceOpts(namespace)...); err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	summaries := make([]*ClusterSummary, 0, len(kcs.Items))
+	for i := range kcs.Items {
+		summaries = append(summaries, ClusterSummaryFrom(&kcs.Items[i]))
+	}
+
+	sort.SliceStable(summaries, func(i, j int) bool {
+		if summaries[i].Namespace != summaries[j].Namespace {
+			return summaries[i].Namespace < summaries[j].Namespace
+		}
+		return summaries[i].Name < summaries[j].Name
+	})
+
+	return summaries, nil
+}
+
+// ClusterSummaryFrom creates a summary of the K8ssandraCluster
+func ClusterSummaryFrom(kc *k8ssandraapi.K8ssandraCluster) *ClusterSummary {
+	summary := &ClusterSummary{
+		Name:      kc.Name,
+		Namespace: kc.Namespace,
+		Cluster:   kc.CassClusterName(),
+	}
+
+	for _, cond := range kc.Status.Conditions {
+		if cond.Status == corev1.ConditionTrue {
+			summary.Conditions = append(summary.Conditions, string(cond.Type))
+		}
+	}
+
+	if kc.Spec.Cassandra == nil {
+		return summary
+	}
+
+	summary.ServerVersion = kc.Spec.Cassandra.ServerVersion
+	summary.Image = kc.Spec.Cassandra.ServerImage
+
+	for _, dcTemplate := range kc.Spec.Cassandra.Datacenters {
+		dc := ClusterDatacenterSummary{
+			Name:    dcTemplate.CassDcName(),
+			Size:    dcTemplate.Size,
+			Stopped: dcTemplate.Stopped,
+		}
+
+		if status, found := kc.Status.Datacenters[dcTemplate.Meta.Name]; found && status.Cassandra != nil {
+			dc.Nodes = len(status.Cassandra.NodeStatuses)
+			dc.Progress = string(status.Cassandra.CassandraOperatorProgress)
+			for _, cond := range status.Cassandra.Conditions {
+				if cond.Status == corev1.ConditionTrue {
+					dc.Conditions = append(dc.Conditions, string(cond.Type))
+				}
+			}
+		}
+
+		summary.Datacenters = append(summary.Datacenters, dc)
+	}
+
+	return summary
+}
+
+func namespaceOpts(namespace string) []client.ListOption {
+	if namespace == "" {
+		return nil
+	}
+	return []client.ListOption{client.InNamespace(namespace)}
+}
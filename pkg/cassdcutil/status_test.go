@@ -0,0 +1,127 @@
+package cassdcutil
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	controlapi "github.com/k8ssandra/cass-operator/apis/control/v1alpha1"
+	k8ssandrataskapi "github.com/k8ssandra/k8ssandra-operator/apis/control/v1alpha1"
+	k8ssandraapi "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func statusScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, cassdcapi.AddToScheme(scheme))
+	require.NoError(t, controlapi.AddToScheme(scheme))
+	require.NoError(t, k8ssandrataskapi.AddToScheme(scheme))
+	require.NoError(t, k8ssandraapi.AddToScheme(scheme))
+	return scheme
+}
+
+func TestDatacenterSummaries(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	dc := &cassdcapi.CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{Name: "dc1", Namespace: "ns1", Labels: map[string]string{k8ssandraapi.K8ssandraClusterNameLabel: "demo"}},
+		Spec: cassdcapi.CassandraDatacenterSpec{
+			ClusterName:   "demo",
+			ServerType:    "cassandra",
+			ServerVersion: "4.1.5",
+			Size:          2,
+		},
+		Status: cassdcapi.CassandraDatacenterStatus{
+			CassandraOperatorProgress: cassdcapi.ProgressReady,
+			Conditions: []cassdcapi.DatacenterCondition{
+				{Type: cassdcapi.DatacenterReady, Status: corev1.ConditionTrue},
+				{Type: cassdcapi.DatacenterScalingUp, Status: corev1.ConditionFalse},
+			},
+			NodeStatuses: cassdcapi.CassandraStatusMap{
+				"demo-dc1-r1-sts-1": {HostID: "host-2", Rack: "r1"},
+				"demo-dc1-r1-sts-0": {HostID: "host-1", Rack: "r1"},
+			},
+		},
+	}
+
+	pod := func(name string, ready corev1.ConditionStatus) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns1", Labels: map[string]string{cassdcapi.DatacenterLabel: "dc1"}},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "cassandra", Image: "k8ssandra/cass-management-api:4.1.5"}}},
+			Status:     corev1.PodStatus{Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: ready}}},
+		}
+	}
+
+	older := &controlapi.CassandraTask{
+		ObjectMeta: metav1.ObjectMeta{Name: "dc1-cleanup", Namespace: "ns1", CreationTimestamp: metav1.NewTime(time.Now().Add(-time.Hour))},
+		Spec: controlapi.CassandraTaskSpec{
+			Datacenter:            corev1.ObjectReference{Name: "dc1", Namespace: "ns1"},
+			CassandraTaskTemplate: controlapi.CassandraTaskTemplate{Jobs: []controlapi.CassandraJob{{Name: "cleanup", Command: controlapi.CommandCleanup}}},
+		},
+	}
+	newer := older.DeepCopy()
+	newer.Name = "dc1-restart"
+	newer.CreationTimestamp = metav1.Now()
+	newer.Spec.Jobs = []controlapi.CassandraJob{{Name: "restart", Command: controlapi.CommandRestart}}
+
+	kc := &k8ssandraapi.K8ssandraCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "ns1"},
+		Spec: k8ssandraapi.K8ssandraClusterSpec{
+			Cassandra: &k8ssandraapi.CassandraClusterTemplate{
+				DatacenterOptions: k8ssandraapi.DatacenterOptions{ServerVersion: "4.1.5"},
+				Datacenters: []k8ssandraapi.CassandraDatacenterTemplate{
+					{Meta: k8ssandraapi.EmbeddedObjectMeta{Name: "dc1"}, Size: 2},
+					{Meta: k8ssandraapi.EmbeddedObjectMeta{Name: "dc2"}, Size: 3, Stopped: true},
+				},
+			},
+		},
+		Status: k8ssandraapi.K8ssandraClusterStatus{
+			Datacenters: map[string]k8ssandraapi.K8ssandraStatus{"dc1": {Cassandra: &dc.Status}},
+		},
+	}
+
+	kubeClient := fake.NewClientBuilder().
+		WithScheme(statusScheme(t)).
+		WithObjects(dc, pod("demo-dc1-r1-sts-0", corev1.ConditionTrue), pod("demo-dc1-r1-sts-1", corev1.ConditionFalse), older, newer, kc).
+		Build()
+	manager := NewManager(kubeClient)
+
+	summaries, err := manager.DatacenterSummaries(ctx, "")
+	require.NoError(err)
+	require.Len(summaries, 1)
+
+	summary := summaries[0]
+	require.Equal("demo", summary.Cluster)
+	require.Equal("demo", summary.K8ssandraCluster)
+	require.Equal("k8ssandra/cass-management-api:4.1.5", summary.Image)
+	require.Equal(1, summary.ReadyPods)
+	require.Equal(int32(2), summary.Size)
+	require.Equal([]string{string(cassdcapi.DatacenterReady)}, summary.Conditions)
+	require.Equal([]NodeSummary{
+		{Pod: "demo-dc1-r1-sts-0", HostID: "host-1", Rack: "r1", Ready: true},
+		{Pod: "demo-dc1-r1-sts-1", HostID: "host-2", Rack: "r1", Ready: false},
+	}, summary.Nodes)
+	require.NotNil(summary.LastTask)
+	require.Equal("dc1-restart", summary.LastTask.Name)
+
+	single, err := manager.DatacenterSummary(ctx, "dc1", "ns1")
+	require.NoError(err)
+	require.Equal(summary, single)
+
+	clusters, err := manager.ClusterSummaries(ctx, "ns1")
+	require.NoError(err)
+	require.Len(clusters, 1)
+	require.Equal("demo", clusters[0].Cluster)
+	require.Equal([]ClusterDatacenterSummary{
+		{Name: "dc1", Size: 2, Nodes: 2, Progress: string(cassdcapi.ProgressReady), Conditions: []string{string(cassdcapi.DatacenterReady)}},
+		{Name: "dc2", Size: 3, Stopped: true},
+	}, clusters[0].Datacenters)
+}
@@ -0,0 +1,343 @@
+package cleaner
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	"github.com/k8ssandra/k8ssandra-client/pkg/kubernetes"
+	k8ssandrataskapi "github.com/k8ssandra/k8ssandra-operator/apis/control/v1alpha1"
+	k8ssandraapi "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/k8ssandra"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// k8ssandraTaskFinalizer is the finalizer of K8ssandraTasks, the operator only declares it in its controller
+	k8ssandraTaskFinalizer = "control.k8ssandra.io/finalizer"
+
+	cassOperator      = "cass-operator"
+	k8ssandraOperator = "k8ssandra-operator"
+)
+
+// OperatorFinalizers maps the finalizers cass-operator and k8ssandra-operator add to the objects they manage to the
+// operator removing them. Without the operator running, nothing removes them and the objects are stuck terminating.
+var OperatorFinalizers = map[string]string{
+	cassdcapi.Finalizer:                 cassOperator,
+	k8ssandra.K8ssandraClusterFinalizer: k8ssandraOperator,
+	k8ssandraTaskFinalizer:              k8ssandraOperator,
+}
+
+// operatorPodLabels select the pods of the operators, as deployed by the Helm charts and by the kustomize manifests
+var operatorPodLabels = map[string][]client.MatchingLabels{
+	cassOperator:      {{"app.kubernetes.io/name": cassOperator}, {"name": cassOperator}},
+	k8ssandraOperator: {{"app.kubernetes.io/name": k8ssandraOperator}, {"control-plane": k8ssandraOperator}},
+}
+
+// Resource is an object found by the cleaner
+type Resource struct {
+	Kind      string
+	Namespace string
+	Name      string
+
+	// Finalizers are the operator finalizers of a stuck object
+	Finalizers []string
+
+	// Reason explains why a terminating object is considered stuck
+	Reason string
+
+	// Datacenter is the deleted datacenter a left behind object belonged to
+	Datacenter string
+
+	// Object is the object as it was found
+	Object client.Object
+}
+
+// Report lists the objects stuck terminating and those left behind by deleted datacenters
+type Report struct {
+	Stuck      []Resource
+	LeftBehind []Resource
+}
+
+// Find looks for CassandraDatacenters, K8ssandraClusters and K8ssandraTasks which are being deleted but still have
+// operator finalizers, and for PersistentVolumeClaims, Secrets and Services labeled for a datacenter which no longer
+// exists. A terminating object is only stuck if the operator of one of its finalizers is not running, or if it has
+// been terminating for longer than stuckAfter, since a running operator removes its finalizers once it is done.
+// Datacenters being deleted count as deleted. Secrets and Services can be shared by the datacenters of a cluster, so
+// they are only left behind if no datacenter of their cluster exists. An empty namespace searches all namespaces.
+func Find(ctx context.Context, kubeClient client.Client, namespace string, stuckAfter time.Duration) (*Report, error) {
+	report := &Report{
+		Stuck:      make([]Resource, 0),
+		LeftBehind: make([]Resource, 0),
+	}
+
+	stuck := &stuckFinder{
+		kubeClient: kubeClient,
+		stuckAfter: stuckAfter,
+		now:        time.Now(),
+		running:    make(map[string]bool),
+	}
+
+	dcs := &cassdcapi.CassandraDatacenterList{}
+	if err := listInstalled(ctx, kubeClient, dcs, namespace); err != nil {
+		return nil, err
+	}
+
+	kcs := &k8ssandraapi.K8ssandraClusterList{}
+	if err := listInstalled(ctx, kubeClient, kcs, namespace); err != nil {
+		return nil, err
+	}
+
+	ktasks := &k8ssandrataskapi.K8ssandraTaskList{}
+	if err := listInstalled(ctx, kubeClient, ktasks, namespace); err != nil {
+		return nil, err
+	}
+
+	datacenters := make(map[string]bool)
+	clusters := make(map[string]bool)
+	for i := range dcs.Items {
+		dc := &dcs.Items[i]
+		if dc.DeletionTimestamp != nil {
+			if err := stuck.add(ctx, report, "CassandraDatacenter", dc); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		datacenters[dc.Namespace+"/"+cassdcapi.CleanLabelValue(dc.Name)] = true
+		clusters[dc.Namespace+"/"+cassdcapi.CleanLabelValue(dc.Spec.ClusterName)] = true
+	}
+
+	for i := range kcs.Items {
+		if err := stuck.add(ctx, report, "K8ssandraCluster", &kcs.Items[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	for i := range ktasks.Items {
+		if err := stuck.add(ctx, report, "K8ssandraTask", &ktasks.Items[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	leftBehind := []struct {
+		kind   string
+		list   client.ObjectList
+		shared bool
+	}{
+		{kind: "PersistentVolumeClaim", list: &corev1.PersistentVolumeClaimList{}},
+		{kind: "Secret", list: &corev1.SecretList{}, shared: true},
+		{kind: "Service", list: &corev1.ServiceList{}, shared: true},
+	}
+
+	for _, l := range leftBehind {
+		opts := append(namespaceOpts(namespace), client.HasLabels{cassdcapi.DatacenterLabel})
+		if err := kubeClient.List(ctx, l.list, opts...); err != nil {
+			return nil, err
+		}
+
+		objects, err := listObjects(l.list)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, obj := range objects {
+			dc := obj.GetLabels()[cassdcapi.DatacenterLabel]
+			if datacenters[obj.GetNamespace()+"/"+dc] {
+				continue
+			}
+			if cluster, found := obj.GetLabels()[cassdcapi.ClusterLabel]; l.shared && found && clusters[obj.GetNamespace()+"/"+cluster] {
+				continue
+			}
+
+			report.LeftBehind = append(report.LeftBehind, Resource{
+				Kind:       l.kind,
+				Namespace:  obj.GetNamespace(),
+				Name:       obj.GetName(),
+				Datacenter: dc,
+				Object:     obj,
+			})
+		}
+	}
+
+	sortResources(report.Stuck)
+	sortResources(report.LeftBehind)
+
+	return report, nil
+}
+
+// stuckFinder decides if a terminating object is stuck, looking up each operator at most once
+type stuckFinder struct {
+	kubeClient client.Client
+	stuckAfter time.Duration
+	now        time.Time
+	running    map[string]bool
+}
+
+// add reports the object as stuck if it is terminating with operator finalizers which are not going to be removed
+func (s *stuckFinder) add(ctx context.Context, r *Report, kind string, obj client.Object) error {
+	deleted := obj.GetDeletionTimestamp()
+	if deleted == nil {
+		return nil
+	}
+
+	finalizers := make([]string, 0)
+	reason := ""
+	for _, f := range obj.GetFinalizers() {
+		operator, found := OperatorFinalizers[f]
+		if !found {
+			continue
+		}
+		finalizers = append(finalizers, f)
+
+		if reason != "" {
+			continue
+		}
+
+		running, err := s.operatorRunning(ctx, operator)
+		if err != nil {
+			return err
+		}
+		if !running {
+			reason = fmt.Sprintf("%s is not running", operator)
+		}
+	}
+
+	if len(finalizers) == 0 {
+		return nil
+	}
+
+	if reason == "" {
+		terminating := s.now.Sub(deleted.Time)
+		if terminating < s.stuckAfter {
+			return nil
+		}
+		reason = fmt.Sprintf("terminating for %s", terminating.Round(time.Second))
+	}
+
+	r.Stuck = append(r.Stuck, Resource{
+		Kind:       kind,
+		Namespace:  obj.GetNamespace(),
+		Name:       obj.GetName(),
+		Finalizers: finalizers,
+		Reason:     reason,
+		Object:     obj,
+	})
+
+	return nil
+}
+
+// operatorRunning reports if a ready pod of the operator runs in any namespace. Without the permission to list the
+// pods of every namespace the operator is assumed to be running, leaving only the deletion age to decide.
+func (s *stuckFinder) operatorRunning(ctx context.Context, operator string) (bool, error) {
+	if running, found := s.running[operator]; found {
+		return running, nil
+	}
+
+	running, err := s.findOperatorPod(ctx, operator)
+	if err != nil {
+		if !apierrors.IsForbidden(err) {
+			return false, err
+		}
+		running = true
+	}
+
+	s.running[operator] = running
+	return running, nil
+}
+
+func (s *stuckFinder) findOperatorPod(ctx context.Context, operator string) (bool, error) {
+	for _, labels := range operatorPodLabels[operator] {
+		pods := &corev1.PodList{}
+		if err := s.kubeClient.List(ctx, pods, labels); err != nil {
+			return false, err
+		}
+
+		for i := range pods.Items {
+			if kubernetes.PodReady(&pods.Items[i]) {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// RemoveFinalizers removes the operator finalizers of a stuck object, letting Kubernetes finish deleting it. It returns
+// the object without the finalizers.
+func RemoveFinalizers(ctx context.Context, kubeClient client.Client, r Resource, dryRun cmdutil.DryRunStrategy) (client.Object, error) {
+	obj := r.Object.DeepCopyObject().(client.Object)
+	patch := client.MergeFrom(r.Object)
+
+	for _, f := range r.Finalizers {
+		controllerutil.RemoveFinalizer(obj, f)
+	}
+
+	switch dryRun {
+	case cmdutil.DryRunClient:
+		return obj, nil
+	case cmdutil.DryRunServer:
+		return obj, client.IgnoreNotFound(kubeClient.Patch(ctx, obj, patch, client.DryRunAll))
+	default:
+		return obj, client.IgnoreNotFound(kubeClient.Patch(ctx, obj, patch))
+	}
+}
+
+// Delete deletes an object left behind by a deleted datacenter
+func Delete(ctx context.Context, kubeClient client.Client, r Resource, dryRun cmdutil.DryRunStrategy) error {
+	switch dryRun {
+	case cmdutil.DryRunClient:
+		return nil
+	case cmdutil.DryRunServer:
+		return client.IgnoreNotFound(kubeClient.Delete(ctx, r.Object, client.DryRunAll))
+	default:
+		return client.IgnoreNotFound(kubeClient.Delete(ctx, r.Object))
+	}
+}
+
+// listInstalled lists the objects, leaving the list empty if their CRD is not installed
+func listInstalled(ctx context.Context, kubeClient client.Client, list client.ObjectList, namespace string) error {
+	if err := kubeClient.List(ctx, list, namespaceOpts(namespace)...); err != nil && !meta.IsNoMatchError(err) {
+		return err
+	}
+	return nil
+}
+
+func listObjects(list client.ObjectList) ([]client.Object, error) {
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make([]client.Object, 0, len(items))
+	for _, item := range items {
+		objects = append(objects, item.(client.Object))
+	}
+	return objects, nil
+}
+
+func sortResources(resources []Resource) {
+	sort.SliceStable(resources, func(i, j int) bool {
+		a, b := resources[i], resources[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Name < b.Name
+	})
+}
+
+func namespaceOpts(namespace string) []client.ListOption {
+	if namespace == "" {
+		return nil
+	}
+	return []client.ListOption{client.InNamespace(namespace)}
+}
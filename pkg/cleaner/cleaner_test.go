@@ -0,0 +1,151 @@
+package cleaner
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	k8ssandrataskapi "github.com/k8ssandra/k8ssandra-operator/apis/control/v1alpha1"
+	k8ssandraapi "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/k8ssandra"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func testClient(t *testing.T, extra ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, cassdcapi.AddToScheme(scheme))
+	require.NoError(t, k8ssandraapi.AddToScheme(scheme))
+	require.NoError(t, k8ssandrataskapi.AddToScheme(scheme))
+
+	deleted := metav1.Now()
+	labels := func(cluster, dc string) map[string]string {
+		return map[string]string{cassdcapi.ClusterLabel: cluster, cassdcapi.DatacenterLabel: dc}
+	}
+
+	objects := []client.Object{
+		&cassdcapi.CassandraDatacenter{
+			ObjectMeta: metav1.ObjectMeta{Name: "dc1", Namespace: "ns1", DeletionTimestamp: &deleted, Finalizers: []string{cassdcapi.Finalizer}},
+			Spec:       cassdcapi.CassandraDatacenterSpec{ClusterName: "demo"},
+		},
+		&cassdcapi.CassandraDatacenter{
+			ObjectMeta: metav1.ObjectMeta{Name: "dc2", Namespace: "ns1"},
+			Spec:       cassdcapi.CassandraDatacenterSpec{ClusterName: "demo"},
+		},
+		&k8ssandraapi.K8ssandraCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "ns1", DeletionTimestamp: &deleted, Finalizers: []string{k8ssandra.K8ssandraClusterFinalizer, "example.com/finalizer"}},
+		},
+		&k8ssandrataskapi.K8ssandraTask{
+			ObjectMeta: metav1.ObjectMeta{Name: "running", Namespace: "ns1", Finalizers: []string{k8ssandraTaskFinalizer}},
+		},
+		&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "server-data-demo-dc1-default-sts-0", Namespace: "ns1", Labels: labels("demo", "dc1")}},
+		&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "server-data-demo-dc2-default-sts-0", Namespace: "ns1", Labels: labels("demo", "dc2")}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "demo-superuser", Namespace: "ns1", Labels: labels("demo", "dc1")}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "user-secret", Namespace: "ns1"}},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "old-dc3-service", Namespace: "ns1", Labels: labels("old", "dc3")}},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "demo-dc2-service", Namespace: "ns1", Labels: labels("demo", "dc2")}},
+	}
+
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(objects, extra...)...).Build()
+}
+
+func TestFind(t *testing.T) {
+	require := require.New(t)
+	kubeClient := testClient(t)
+
+	report, err := Find(context.TODO(), kubeClient, "ns1", time.Hour)
+	require.NoError(err)
+
+	require.Len(report.Stuck, 2)
+	require.Equal("CassandraDatacenter", report.Stuck[0].Kind)
+	require.Equal("dc1", report.Stuck[0].Name)
+	require.Equal([]string{cassdcapi.Finalizer}, report.Stuck[0].Finalizers)
+	require.Equal("cass-operator is not running", report.Stuck[0].Reason)
+	require.Equal("K8ssandraCluster", report.Stuck[1].Kind)
+	require.Equal([]string{k8ssandra.K8ssandraClusterFinalizer}, report.Stuck[1].Finalizers)
+
+	require.Len(report.LeftBehind, 2)
+	require.Equal("PersistentVolumeClaim", report.LeftBehind[0].Kind)
+	require.Equal("server-data-demo-dc1-default-sts-0", report.LeftBehind[0].Name)
+	require.Equal("dc1", report.LeftBehind[0].Datacenter)
+	require.Equal("Service", report.LeftBehind[1].Kind)
+	require.Equal("old-dc3-service", report.LeftBehind[1].Name)
+
+	report, err = Find(context.TODO(), kubeClient, "ns2", time.Hour)
+	require.NoError(err)
+	require.Empty(report.Stuck)
+	require.Empty(report.LeftBehind)
+}
+
+func TestFindOperatorRunning(t *testing.T) {
+	require := require.New(t)
+
+	// A ready cass-operator, deployed in its own namespace, removes the finalizer of the datacenter deleted just now
+	operator := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "cass-operator-0", Namespace: "cass-operator", Labels: map[string]string{"app.kubernetes.io/name": "cass-operator"}},
+		Status:     corev1.PodStatus{Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}},
+	}
+	kubeClient := testClient(t, operator)
+
+	report, err := Find(context.TODO(), kubeClient, "ns1", time.Hour)
+	require.NoError(err)
+	require.Len(report.Stuck, 1)
+	require.Equal("K8ssandraCluster", report.Stuck[0].Kind)
+	require.Equal("k8ssandra-operator is not running", report.Stuck[0].Reason)
+
+	// Once terminating for longer than the threshold, the datacenter is stuck even with its operator running
+	report, err = Find(context.TODO(), kubeClient, "ns1", 0)
+	require.NoError(err)
+	require.Len(report.Stuck, 2)
+	require.Equal("CassandraDatacenter", report.Stuck[0].Kind)
+	require.Contains(report.Stuck[0].Reason, "terminating for")
+}
+
+func TestRemoveFinalizersAndDelete(t *testing.T) {
+	require := require.New(t)
+	kubeClient := testClient(t)
+
+	report, err := Find(context.TODO(), kubeClient, "", time.Hour)
+	require.NoError(err)
+
+	// Client dry run changes nothing
+	for _, r := range report.Stuck {
+		obj, err := RemoveFinalizers(context.TODO(), kubeClient, r, cmdutil.DryRunClient)
+		require.NoError(err)
+		require.NotContains(obj.GetFinalizers(), cassdcapi.Finalizer)
+		require.NoError(Delete(context.TODO(), kubeClient, r, cmdutil.DryRunClient))
+	}
+	require.NoError(kubeClient.Get(context.TODO(), client.ObjectKey{Name: "dc1", Namespace: "ns1"}, &cassdcapi.CassandraDatacenter{}))
+
+	for _, r := range report.Stuck {
+		_, err := RemoveFinalizers(context.TODO(), kubeClient, r, cmdutil.DryRunNone)
+		require.NoError(err)
+	}
+
+	// Without finalizers left, the datacenter is gone
+	err = kubeClient.Get(context.TODO(), client.ObjectKey{Name: "dc1", Namespace: "ns1"}, &cassdcapi.CassandraDatacenter{})
+	require.True(apierrors.IsNotFound(err))
+
+	// Finalizers of other controllers are kept
+	kc := &k8ssandraapi.K8ssandraCluster{}
+	require.NoError(kubeClient.Get(context.TODO(), client.ObjectKey{Name: "demo", Namespace: "ns1"}, kc))
+	require.Equal([]string{"example.com/finalizer"}, kc.Finalizers)
+
+	for _, r := range report.LeftBehind {
+		require.NoError(Delete(context.TODO(), kubeClient, r, cmdutil.DryRunNone))
+		require.NoError(Delete(context.TODO(), kubeClient, r, cmdutil.DryRunNone))
+	}
+
+	err = kubeClient.Get(context.TODO(), client.ObjectKey{Name: "old-dc3-service", Namespace: "ns1"}, &corev1.Service{})
+	require.True(apierrors.IsNotFound(err))
+	require.NoError(kubeClient.Get(context.TODO(), client.ObjectKey{Name: "demo-superuser", Namespace: "ns1"}, &corev1.Secret{}))
+}
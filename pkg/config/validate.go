@@ -0,0 +1,151 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	metadata "github.com/burmanm/definitions-parser/pkg/types"
+)
+
+const (
+	additionalJvmOptsKey = "additional-jvm-opts"
+	garbageCollectorKey  = "garbage_collector"
+)
+
+// optionSections maps the JVM options sections of the cass-operator input to the options files they are rendered to
+var optionSections = map[string]string{
+	"jvm-server-options":   "jvm-server.options",
+	"jvm11-server-options": "jvm11-server.options",
+	"jvm17-server-options": "jvm17-server.options",
+}
+
+var cassandraEnvKeys = []string{"malloc-arena-max", "heap-dump-dir", additionalJvmOptsKey}
+
+// ValidateConfig checks the config of a CassandraDatacenter (Spec.Config) against the options the builder knows how to
+// render. The keys of the JVM options sections are validated against the definitions metadata and the cassandra-env-sh
+// keys against the supported settings. There is no metadata for cassandra.yaml, so its keys are not validated.
+func ValidateConfig(data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	config := make(map[string]interface{})
+	if err := json.Unmarshal(data, &config); err != nil {
+		return fmt.Errorf("config is not a valid JSON object: %w", err)
+	}
+
+	problems := make([]string, 0)
+	for section, value := range config {
+		if filename, found := optionSections[section]; found {
+			problems = append(problems, validateServerOptions(section, value, optionsFilenameToMap(filename))...)
+		} else if section == "cassandra-env-sh" {
+			problems = append(problems, validateCassandraEnv(value)...)
+		}
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return fmt.Errorf("invalid config: %s", strings.Join(problems, "; "))
+	}
+
+	return nil
+}
+
+func validateServerOptions(section string, value interface{}, options map[string]metadata.Metadata) []string {
+	values, ok := value.(map[string]interface{})
+	if !ok {
+		return []string{fmt.Sprintf("%s must be an object", section)}
+	}
+
+	problems := make([]string, 0)
+	for k, v := range values {
+		switch k {
+		case additionalJvmOptsKey:
+			if !isStringList(v) {
+				problems = append(problems, fmt.Sprintf("%s.%s must be a list of strings", section, k))
+			}
+		case garbageCollectorKey:
+			if gc, ok := v.(string); !ok || !slices.Contains(supportedGCs, gc) {
+				problems = append(problems, fmt.Sprintf("%s.%s must be one of %s", section, k, strings.Join(supportedGCs, ", ")))
+			}
+		default:
+			option, found := options[k]
+			if !found {
+				problems = append(problems, fmt.Sprintf("%s.%s is not a known option", section, k))
+				continue
+			}
+			if !validOptionValue(option, v) {
+				problems = append(problems, fmt.Sprintf("%s.%s has an invalid value %v", section, k, v))
+			}
+		}
+	}
+
+	return problems
+}
+
+func validateCassandraEnv(value interface{}) []string {
+	values, ok := value.(map[string]interface{})
+	if !ok {
+		return []string{"cassandra-env-sh must be an object"}
+	}
+
+	problems := make([]string, 0)
+	for k, v := range values {
+		if !slices.Contains(cassandraEnvKeys, k) {
+			problems = append(problems, fmt.Sprintf("cassandra-env-sh.%s is not a known option", k))
+			continue
+		}
+		if k == additionalJvmOptsKey && !isStringList(v) {
+			problems = append(problems, fmt.Sprintf("cassandra-env-sh.%s must be a list of strings", k))
+		}
+	}
+
+	return problems
+}
+
+// validOptionValue checks the value can be rendered by the option's builder type
+func validOptionValue(option metadata.Metadata, value interface{}) bool {
+	switch option.BuilderType {
+	case metadata.BooleanBuilder:
+		switch v := value.(type) {
+		case bool:
+			return true
+		case string:
+			_, err := strconv.ParseBool(v)
+			return err == nil
+		}
+		return false
+	case metadata.IntegerBuilder:
+		switch v := value.(type) {
+		case float64:
+			return v == float64(int64(v))
+		case string:
+			_, err := strconv.ParseInt(v, 10, 64)
+			return err == nil
+		}
+		return false
+	default:
+		switch value.(type) {
+		case map[string]interface{}, []interface{}, nil:
+			return false
+		}
+		return true
+	}
+}
+
+func isStringList(value interface{}) bool {
+	values, ok := value.([]interface{})
+	if !ok {
+		return false
+	}
+	for _, v := range values {
+		if _, ok := v.(string); !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,34 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateConfig(t *testing.T) {
+	require := require.New(t)
+
+	require.NoError(ValidateConfig(nil))
+	require.NoError(ValidateConfig([]byte(existingConfig)))
+	require.NoError(ValidateConfig([]byte(`{"jvm17-server-options": {"garbage_collector": "ZGC", "additional-jvm-opts": ["-Dfoo=bar"]}}`)))
+	require.NoError(ValidateConfig([]byte(`{"cassandra-yaml": {"anything_goes": true}}`)))
+
+	err := ValidateConfig([]byte(`{"jvm-server-options": {"max_heap_sise": "512m"}}`))
+	require.ErrorContains(err, "jvm-server-options.max_heap_sise is not a known option")
+
+	err = ValidateConfig([]byte(`{"jvm11-server-options": {"garbage_collector": "ParallelGC"}}`))
+	require.ErrorContains(err, "jvm11-server-options.garbage_collector must be one of G1GC, CMS, Shenandoah, ZGC")
+
+	err = ValidateConfig([]byte(`{"jvm17-server-options": {"max_heap_size": "512m"}}`))
+	require.ErrorContains(err, "jvm17-server-options.max_heap_size is not a known option")
+
+	err = ValidateConfig([]byte(`{"jvm-server-options": {"cassandra_ring_delay_ms": "soon"}}`))
+	require.ErrorContains(err, "jvm-server-options.cassandra_ring_delay_ms has an invalid value soon")
+
+	err = ValidateConfig([]byte(`{"cassandra-env-sh": {"malloc-arena-max": 4, "heap-dump": "/tmp", "additional-jvm-opts": "-Dfoo"}}`))
+	require.ErrorContains(err, "cassandra-env-sh.heap-dump is not a known option")
+	require.ErrorContains(err, "cassandra-env-sh.additional-jvm-opts must be a list of strings")
+
+	require.Error(ValidateConfig([]byte(`[]`)))
+}
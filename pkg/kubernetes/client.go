@@ -4,9 +4,14 @@ import (
 	"context"
 
 	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	controlapi "github.com/k8ssandra/cass-operator/apis/control/v1alpha1"
+	k8ssandrataskapi "github.com/k8ssandra/k8ssandra-operator/apis/control/v1alpha1"
+	k8ssandraapi "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	medusaapi "github.com/k8ssandra/k8ssandra-operator/apis/medusa/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -18,18 +23,50 @@ type NamespacedClient struct {
 	Namespace string
 }
 
-// GetClient returns a controller-runtime client with cass-operator API defined
+// GetClient returns a controller-runtime client with cass-operator and k8ssandra-operator cluster, task and Medusa APIs defined
 func GetClient(restConfig *rest.Config) (client.Client, error) {
 	c, err := client.New(restConfig, client.Options{})
 	if err != nil {
 		return nil, err
 	}
 
-	err = cassdcapi.AddToScheme(c.Scheme())
+	err = addToScheme(c.Scheme())
 
 	return c, err
 }
 
+// GetWatchClient returns a controller-runtime client that is also able to watch, with the same APIs as GetClient
+func GetWatchClient(restConfig *rest.Config) (client.WithWatch, error) {
+	c, err := client.NewWithWatch(restConfig, client.Options{})
+	if err != nil {
+		return nil, err
+	}
+
+	err = addToScheme(c.Scheme())
+
+	return c, err
+}
+
+func addToScheme(s *runtime.Scheme) error {
+	if err := cassdcapi.AddToScheme(s); err != nil {
+		return err
+	}
+
+	if err := controlapi.AddToScheme(s); err != nil {
+		return err
+	}
+
+	if err := k8ssandrataskapi.AddToScheme(s); err != nil {
+		return err
+	}
+
+	if err := k8ssandraapi.AddToScheme(s); err != nil {
+		return err
+	}
+
+	return medusaapi.AddToScheme(s)
+}
+
 func GetClientInNamespace(restConfig *rest.Config, namespace string) (NamespacedClient, error) {
 	c, err := GetClient(restConfig)
 	if err != nil {
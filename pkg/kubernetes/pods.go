@@ -0,0 +1,15 @@
+package kubernetes
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// PodReady returns true if the pod reports the Ready condition
+func PodReady(pod *corev1.Pod) bool {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -0,0 +1,136 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	errServicePortNotFound = errors.New("the service does not expose the port")
+	errNoReadyPod          = errors.New("the service has no ready pods")
+)
+
+// PortForward is a running forward of a local port to a pod
+type PortForward struct {
+	LocalPort uint16
+	stopCh    chan struct{}
+}
+
+// Close stops forwarding the port
+func (p *PortForward) Close() {
+	close(p.stopCh)
+}
+
+// ForwardServicePort forwards a random local port to the given port of the service, like kubectl port-forward svc/name
+// does. A single ready pod of the service is chosen, the forward does not move to another pod if that pod goes away.
+func ForwardServicePort(ctx context.Context, restConfig *rest.Config, kubeClient client.Client, namespace, serviceName string, port int32) (*PortForward, error) {
+	svc := &corev1.Service{}
+	if err := kubeClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: serviceName}, svc); err != nil {
+		return nil, err
+	}
+
+	var servicePort *corev1.ServicePort
+	for i := range svc.Spec.Ports {
+		if svc.Spec.Ports[i].Port == port {
+			servicePort = &svc.Spec.Ports[i]
+		}
+	}
+	if servicePort == nil {
+		return nil, fmt.Errorf("%w: service/%s port %d", errServicePortNotFound, serviceName, port)
+	}
+
+	pods := &corev1.PodList{}
+	if err := kubeClient.List(ctx, pods, client.InNamespace(namespace), client.MatchingLabelsSelector{Selector: labels.SelectorFromSet(svc.Spec.Selector)}); err != nil {
+		return nil, err
+	}
+
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil || !PodReady(pod) {
+			continue
+		}
+
+		targetPort, err := containerPort(pod, servicePort.TargetPort, port)
+		if err != nil {
+			return nil, err
+		}
+
+		return ForwardPodPort(restConfig, namespace, pod.Name, targetPort)
+	}
+
+	return nil, fmt.Errorf("%w: service/%s", errNoReadyPod, serviceName)
+}
+
+// ForwardPodPort forwards a random local port to the given port of the pod
+func ForwardPodPort(restConfig *rest.Config, namespace, podName string, port int32) (*PortForward, error) {
+	coreClient, err := corev1client.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	transport, upgrader, err := spdy.RoundTripperFor(restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	reqURL := coreClient.RESTClient().Post().Resource("pods").Namespace(namespace).Name(podName).SubResource("portforward").URL()
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, reqURL)
+
+	stopCh := make(chan struct{})
+	readyCh := make(chan struct{})
+	forwarder, err := portforward.NewOnAddresses(dialer, []string{"localhost"}, []string{fmt.Sprintf("0:%d", port)}, stopCh, readyCh, io.Discard, io.Discard)
+	if err != nil {
+		return nil, err
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- forwarder.ForwardPorts()
+	}()
+
+	select {
+	case <-readyCh:
+	case err := <-errCh:
+		return nil, fmt.Errorf("failed to forward a port to pod/%s: %w", podName, err)
+	}
+
+	ports, err := forwarder.GetPorts()
+	if err != nil {
+		close(stopCh)
+		return nil, err
+	}
+
+	return &PortForward{LocalPort: ports[0].Local, stopCh: stopCh}, nil
+}
+
+// containerPort resolves the target port of a service port to the port number in the pod
+func containerPort(pod *corev1.Pod, targetPort intstr.IntOrString, servicePort int32) (int32, error) {
+	if targetPort.Type == intstr.Int {
+		if targetPort.IntVal == 0 {
+			return servicePort, nil
+		}
+		return targetPort.IntVal, nil
+	}
+
+	for _, c := range pod.Spec.Containers {
+		for _, p := range c.Ports {
+			if p.Name == targetPort.StrVal {
+				return p.ContainerPort, nil
+			}
+		}
+	}
+
+	return 0, fmt.Errorf("%w: pod/%s has no port named %s", errServicePortNotFound, pod.Name, targetPort.StrVal)
+}
@@ -0,0 +1,172 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	waitutil "k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	watchtools "k8s.io/client-go/tools/watch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// ObjectCondition reports if the object has reached the wanted state. The object is nil once it has been deleted.
+type ObjectCondition func(obj client.Object) (bool, error)
+
+// ErrWaitTimeout is returned by WaitFor when the condition was not met within the timeout
+var ErrWaitTimeout = errors.New("timed out waiting for the condition")
+
+// WaitFor watches the object identified by the name and namespace of obj until condition is met, the context is
+// cancelled or the timeout passes. The last seen state of the object is returned, nil if it was deleted. The watch
+// is reestablished if the server closes it, no polling is involved. A watch that ends before the condition is met is
+// reported as an error wrapping watchtools.ErrWatchClosed, only the timeout passing returns ErrWaitTimeout.
+func WaitFor(ctx context.Context, kubeClient client.WithWatch, obj client.Object, timeout time.Duration, condition ObjectCondition) (client.Object, error) {
+	gvk, err := apiutil.GVKForObject(obj, kubeClient.Scheme())
+	if err != nil {
+		return nil, err
+	}
+
+	newList := func() (client.ObjectList, error) {
+		list, err := kubeClient.Scheme().New(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err != nil {
+			return nil, err
+		}
+		objList, ok := list.(client.ObjectList)
+		if !ok {
+			return nil, fmt.Errorf("%s is not a list type", gvk.Kind)
+		}
+		return objList, nil
+	}
+
+	listOpts := func(options metav1.ListOptions) *client.ListOptions {
+		return &client.ListOptions{
+			Namespace:     obj.GetNamespace(),
+			FieldSelector: fields.OneTermEqualSelector("metadata.name", obj.GetName()),
+			Raw:           &options,
+		}
+	}
+
+	lw := &cache.ListWatch{
+		ListWithContextFunc: func(ctx context.Context, options metav1.ListOptions) (runtime.Object, error) {
+			list, err := newList()
+			if err != nil {
+				return nil, err
+			}
+			return list, kubeClient.List(ctx, list, listOpts(options))
+		},
+		WatchFuncWithContext: func(ctx context.Context, options metav1.ListOptions) (watch.Interface, error) {
+			list, err := newList()
+			if err != nil {
+				return nil, err
+			}
+			return kubeClient.Watch(ctx, list, listOpts(options))
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var last client.Object
+	check := func(current client.Object) (bool, error) {
+		last = current
+		return condition(current)
+	}
+
+	precondition := func(store cache.Store) (bool, error) {
+		item, exists, err := store.GetByKey(client.ObjectKeyFromObject(obj).String())
+		if err != nil {
+			return false, err
+		}
+		if !exists {
+			return check(nil)
+		}
+		return check(item.(client.Object))
+	}
+
+	_, err = watchtools.UntilWithSync(ctx, lw, obj.DeepCopyObject(), precondition, func(event watch.Event) (bool, error) {
+		current, ok := event.Object.(client.Object)
+		if !ok || current.GetName() != obj.GetName() || current.GetNamespace() != obj.GetNamespace() {
+			return false, nil
+		}
+		if event.Type == watch.Deleted {
+			return check(nil)
+		}
+		return check(current)
+	})
+	if err != nil {
+		if errors.Is(err, watchtools.ErrWatchClosed) {
+			return last, fmt.Errorf("watch of %s %s failed: %w", gvk.Kind, client.ObjectKeyFromObject(obj), err)
+		}
+		if waitutil.Interrupted(err) {
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return last, fmt.Errorf("%w after %s", ErrWaitTimeout, timeout)
+			}
+			if ctx.Err() != nil {
+				return last, ctx.Err()
+			}
+		}
+		return last, err
+	}
+
+	return last, nil
+}
+
+// HasCondition returns an ObjectCondition met once the object has a status condition of the given type and status.
+// It works for any object storing its conditions in status.conditions, such as CassandraDatacenters and tasks.
+func HasCondition(conditionType, status string) ObjectCondition {
+	return func(obj client.Object) (bool, error) {
+		if obj == nil {
+			return false, nil
+		}
+
+		current, found, err := ConditionStatus(obj, conditionType)
+		if err != nil || !found {
+			return false, err
+		}
+
+		return strings.EqualFold(current, status), nil
+	}
+}
+
+// IsDeleted returns an ObjectCondition met once the object no longer exists
+func IsDeleted() ObjectCondition {
+	return func(obj client.Object) (bool, error) {
+		return obj == nil, nil
+	}
+}
+
+// ConditionStatus returns the status of the condition of the given type from the status.conditions of the object
+func ConditionStatus(obj client.Object, conditionType string) (string, bool, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return "", false, err
+	}
+
+	conditions, found, err := unstructured.NestedSlice(content, "status", "conditions")
+	if err != nil || !found {
+		return "", false, err
+	}
+
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if t, _ := condition["type"].(string); strings.EqualFold(t, conditionType) {
+			status, _ := condition["status"].(string)
+			return status, true, nil
+		}
+	}
+
+	return "", false, nil
+}
@@ -0,0 +1,200 @@
+package medusa
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	medusaapi "github.com/k8ssandra/k8ssandra-operator/apis/medusa/v1alpha1"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/shared"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// StatusSuccess is the status Medusa reports for a backup which finished on every node
+	StatusSuccess = "SUCCESS"
+
+	// medusaContainer is the name of the container k8ssandra-operator adds to the pods when Medusa is enabled
+	medusaContainer = "medusa"
+)
+
+var (
+	errMedusaNotDeployed = errors.New("medusa is not deployed in the datacenter, enable it in the K8ssandraCluster")
+	errBackupNotFinished = errors.New("the backup has not finished")
+	errBackupFailed      = errors.New("the backup did not complete successfully")
+	errRestoreFailed     = errors.New("the restore failed")
+	errTopologyMismatch  = errors.New("the topology of the datacenter does not match the backup")
+)
+
+// CheckDeployed returns an error if the operator has not added the Medusa container to the pods of the datacenter
+func CheckDeployed(dc *cassdcapi.CassandraDatacenter) error {
+	if dc.Spec.PodTemplateSpec != nil {
+		for _, c := range dc.Spec.PodTemplateSpec.Spec.Containers {
+			if c.Name == medusaContainer {
+				return nil
+			}
+		}
+	}
+	return errMedusaNotDeployed
+}
+
+// NewBackupJob returns a MedusaBackupJob taking a backup of the datacenter, the MedusaBackup created by the operator
+// gets the same name
+func NewBackupJob(dc *cassdcapi.CassandraDatacenter, name string, backupType shared.BackupType) *medusaapi.MedusaBackupJob {
+	return &medusaapi.MedusaBackupJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: dc.Namespace,
+		},
+		Spec: medusaapi.MedusaBackupJobSpec{
+			CassandraDatacenter: dc.Name,
+			Type:                backupType,
+		},
+	}
+}
+
+// NewRestoreJob returns a MedusaRestoreJob restoring the backup to the datacenter
+func NewRestoreJob(dc *cassdcapi.CassandraDatacenter, name, backupName string) *medusaapi.MedusaRestoreJob {
+	return &medusaapi.MedusaRestoreJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: dc.Namespace,
+		},
+		Spec: medusaapi.MedusaRestoreJobSpec{
+			Backup:              backupName,
+			CassandraDatacenter: dc.Name,
+		},
+	}
+}
+
+// ListBackups returns the MedusaBackups of the namespace, or every namespace if it is empty, ordered by their start
+// time. Only the backups of the given CassandraDatacenter are returned if dcName is set.
+func ListBackups(ctx context.Context, kubeClient client.Client, namespace, dcName string) ([]medusaapi.MedusaBackup, error) {
+	backupList := &medusaapi.MedusaBackupList{}
+	if err := kubeClient.List(ctx, backupList, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+
+	backups := make([]medusaapi.MedusaBackup, 0, len(backupList.Items))
+	for _, backup := range backupList.Items {
+		if dcName == "" || backup.Spec.CassandraDatacenter == dcName {
+			backups = append(backups, backup)
+		}
+	}
+
+	sort.SliceStable(backups, func(i, j int) bool {
+		if backups[i].Status.StartTime.Equal(&backups[j].Status.StartTime) {
+			return backups[i].Name < backups[j].Name
+		}
+		return backups[i].Status.StartTime.Before(&backups[j].Status.StartTime)
+	})
+
+	return backups, nil
+}
+
+// DeleteBackup deletes the files of the backup from the storage bucket, then the MedusaBackup and the MedusaBackupJob
+// which created it. The objects are only deleted once the files are gone, otherwise a sync MedusaTask would recreate
+// the MedusaBackup.
+func DeleteBackup(ctx context.Context, kubeClient client.Client, namespace, name string, deleteFiles StorageDeleter) error {
+	backup := &medusaapi.MedusaBackup{}
+	if err := kubeClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, backup); err != nil {
+		return err
+	}
+
+	if err := deleteFiles(ctx, backup); err != nil {
+		return fmt.Errorf("unable to delete the files of backup %s: %w", name, err)
+	}
+
+	if err := kubeClient.Delete(ctx, backup); client.IgnoreNotFound(err) != nil {
+		return err
+	}
+
+	job := &medusaapi.MedusaBackupJob{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+	return client.IgnoreNotFound(kubeClient.Delete(ctx, job))
+}
+
+// BackupJobFinished reports if the operator has finished the job, successfully or not
+func BackupJobFinished(job *medusaapi.MedusaBackupJob) bool {
+	return !job.Status.FinishTime.IsZero()
+}
+
+// BackupJobError returns an error listing the pods where the backup failed, nil if none did
+func BackupJobError(job *medusaapi.MedusaBackupJob) error {
+	if len(job.Status.Failed) > 0 {
+		return fmt.Errorf("%w on pods %s", errBackupFailed, strings.Join(job.Status.Failed, ", "))
+	}
+	return nil
+}
+
+// RestoreJobFinished reports if the operator has finished the job, successfully or not
+func RestoreJobFinished(job *medusaapi.MedusaRestoreJob) bool {
+	return !job.Status.FinishTime.IsZero()
+}
+
+// RestoreJobError returns the reason the restore failed, nil if it did not
+func RestoreJobError(job *medusaapi.MedusaRestoreJob) error {
+	if job.Status.Message != "" {
+		return fmt.Errorf("%w: %s", errRestoreFailed, job.Status.Message)
+	}
+	if len(job.Status.Failed) > 0 {
+		return fmt.Errorf("%w on pods %s", errRestoreFailed, strings.Join(job.Status.Failed, ", "))
+	}
+	return nil
+}
+
+// CheckTopology verifies the backup can be restored to the datacenter. The backup must have completed and the
+// datacenter must have the same name, racks and number of nodes per rack as the datacenter the backup was taken
+// from, since the operator maps the nodes of the backup to the pods rack by rack.
+func CheckTopology(backup *medusaapi.MedusaBackup, dc *cassdcapi.CassandraDatacenter) error {
+	if backup.Status.FinishTime.IsZero() {
+		return errBackupNotFinished
+	}
+
+	if backup.Status.FinishedNodes != backup.Status.TotalNodes || (backup.Status.Status != "" && backup.Status.Status != StatusSuccess) {
+		return errBackupFailed
+	}
+
+	backupRacks := make(map[string]int)
+	for _, node := range backup.Status.Nodes {
+		if node.Datacenter != dc.DatacenterName() {
+			return fmt.Errorf("%w: the backup was taken from datacenter %s, not %s", errTopologyMismatch, node.Datacenter, dc.DatacenterName())
+		}
+		backupRacks[node.Rack]++
+	}
+
+	dcRacks := make(map[string]int)
+	racks := dc.GetRacks()
+	for i, count := range cassdcapi.SplitRacks(int(dc.Spec.Size), len(racks)) {
+		dcRacks[racks[i].Name] = count
+	}
+
+	if len(backup.Status.Nodes) != int(dc.Spec.Size) {
+		return fmt.Errorf("%w: the backup has %d nodes, the datacenter %d", errTopologyMismatch, len(backup.Status.Nodes), dc.Spec.Size)
+	}
+
+	if backupLayout, dcLayout := rackLayout(backupRacks), rackLayout(dcRacks); backupLayout != dcLayout {
+		return fmt.Errorf("%w: the backup has nodes per rack %s, the datacenter %s", errTopologyMismatch, backupLayout, dcLayout)
+	}
+
+	return nil
+}
+
+// rackLayout formats the node counts per rack in the order of the rack names
+func rackLayout(racks map[string]int) string {
+	names := make([]string, 0, len(racks))
+	for name := range racks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s=%d", name, racks[name]))
+	}
+
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,183 @@
+package medusa
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	medusaapi "github.com/k8ssandra/k8ssandra-operator/apis/medusa/v1alpha1"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/shared"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func testClient(t *testing.T, objects ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	require.NoError(t, medusaapi.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+}
+
+func testDatacenter(size int32, racks ...string) *cassdcapi.CassandraDatacenter {
+	dc := &cassdcapi.CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{Name: "dc1", Namespace: "ns1"},
+		Spec:       cassdcapi.CassandraDatacenterSpec{ClusterName: "demo", Size: size},
+	}
+	for _, rack := range racks {
+		dc.Spec.Racks = append(dc.Spec.Racks, cassdcapi.Rack{Name: rack})
+	}
+	return dc
+}
+
+func testBackup(name string, started time.Time, nodes ...*medusaapi.MedusaBackupNode) *medusaapi.MedusaBackup {
+	return &medusaapi.MedusaBackup{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns1"},
+		Spec:       medusaapi.MedusaBackupSpec{CassandraDatacenter: "dc1", Type: shared.FullBackup},
+		Status: medusaapi.MedusaBackupStatus{
+			StartTime:     metav1.NewTime(started),
+			FinishTime:    metav1.NewTime(started.Add(time.Minute)),
+			TotalNodes:    int32(len(nodes)),
+			FinishedNodes: int32(len(nodes)),
+			Nodes:         nodes,
+			Status:        StatusSuccess,
+		},
+	}
+}
+
+func node(dc, rack string) *medusaapi.MedusaBackupNode {
+	return &medusaapi.MedusaBackupNode{Datacenter: dc, Rack: rack}
+}
+
+func TestListBackups(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Now()
+	other := testBackup("other", now)
+	other.Spec.CassandraDatacenter = "dc2"
+	kubeClient := testClient(t, testBackup("newer", now), testBackup("older", now.Add(-time.Hour)), other)
+
+	backups, err := ListBackups(context.TODO(), kubeClient, "ns1", "dc1")
+	require.NoError(err)
+	require.Len(backups, 2)
+	require.Equal("older", backups[0].Name)
+	require.Equal("newer", backups[1].Name)
+
+	backups, err = ListBackups(context.TODO(), kubeClient, "", "")
+	require.NoError(err)
+	require.Len(backups, 3)
+}
+
+func TestDeleteBackup(t *testing.T) {
+	require := require.New(t)
+
+	dc := testDatacenter(1)
+	kubeClient := testClient(t, testBackup("backup1", time.Now()), NewBackupJob(dc, "backup1", shared.FullBackup))
+
+	// The objects are kept when the files could not be deleted, a sync would recreate the MedusaBackup otherwise
+	errStorage := errors.New("bucket unreachable")
+	err := DeleteBackup(context.TODO(), kubeClient, "ns1", "backup1", func(ctx context.Context, backup *medusaapi.MedusaBackup) error {
+		return errStorage
+	})
+	require.ErrorIs(err, errStorage)
+	require.NoError(kubeClient.Get(context.TODO(), client.ObjectKey{Namespace: "ns1", Name: "backup1"}, &medusaapi.MedusaBackup{}))
+
+	deleted := []string{}
+	deleteFiles := func(ctx context.Context, backup *medusaapi.MedusaBackup) error {
+		deleted = append(deleted, backup.Name)
+		return nil
+	}
+
+	require.NoError(DeleteBackup(context.TODO(), kubeClient, "ns1", "backup1", deleteFiles))
+	require.Equal([]string{"backup1"}, deleted)
+	require.True(apierrors.IsNotFound(kubeClient.Get(context.TODO(), client.ObjectKey{Namespace: "ns1", Name: "backup1"}, &medusaapi.MedusaBackup{})))
+	require.True(apierrors.IsNotFound(kubeClient.Get(context.TODO(), client.ObjectKey{Namespace: "ns1", Name: "backup1"}, &medusaapi.MedusaBackupJob{})))
+
+	require.True(apierrors.IsNotFound(DeleteBackup(context.TODO(), kubeClient, "ns1", "backup1", deleteFiles)))
+	require.Len(deleted, 1)
+}
+
+func TestStorageDeleterNoReadyPod(t *testing.T) {
+	notReady := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "demo-dc1-r1-sts-0", Namespace: "ns1", Labels: map[string]string{cassdcapi.DatacenterLabel: "dc1"}}}
+	kubeClient := testClient(t, notReady)
+	err := NewStorageDeleter(nil, kubeClient)(context.TODO(), testBackup("backup1", time.Now()))
+	require.ErrorIs(t, err, errNoReadyPod)
+}
+
+func TestCheckDeployed(t *testing.T) {
+	dc := testDatacenter(1)
+	require.ErrorIs(t, CheckDeployed(dc), errMedusaNotDeployed)
+
+	dc.Spec.PodTemplateSpec = &corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "cassandra"}, {Name: "medusa"}}},
+	}
+	require.NoError(t, CheckDeployed(dc))
+}
+
+func TestJobErrors(t *testing.T) {
+	require := require.New(t)
+
+	dc := testDatacenter(1)
+	backupJob := NewBackupJob(dc, "backup1", shared.DifferentialBackup)
+	require.Equal("dc1", backupJob.Spec.CassandraDatacenter)
+	require.False(BackupJobFinished(backupJob))
+	require.NoError(BackupJobError(backupJob))
+
+	backupJob.Status.FinishTime = metav1.Now()
+	backupJob.Status.Failed = []string{"demo-dc1-default-sts-0"}
+	require.True(BackupJobFinished(backupJob))
+	require.ErrorIs(BackupJobError(backupJob), errBackupFailed)
+
+	restoreJob := NewRestoreJob(dc, "restore1", "backup1")
+	require.Equal("backup1", restoreJob.Spec.Backup)
+	require.False(RestoreJobFinished(restoreJob))
+	require.NoError(RestoreJobError(restoreJob))
+
+	restoreJob.Status.FinishTime = metav1.Now()
+	restoreJob.Status.Message = "target backup has not finished"
+	require.True(RestoreJobFinished(restoreJob))
+	require.ErrorIs(RestoreJobError(restoreJob), errRestoreFailed)
+}
+
+func TestCheckTopology(t *testing.T) {
+	now := time.Now()
+
+	unfinished := testBackup("backup", now, node("dc1", "default"))
+	unfinished.Status.FinishTime = metav1.Time{}
+
+	failed := testBackup("backup", now, node("dc1", "default"))
+	failed.Status.FinishedNodes = 0
+
+	tests := []struct {
+		name   string
+		backup *medusaapi.MedusaBackup
+		dc     *cassdcapi.CassandraDatacenter
+		err    error
+	}{
+		{"single rack", testBackup("backup", now, node("dc1", "default"), node("dc1", "default")), testDatacenter(2), nil},
+		{"multiple racks", testBackup("backup", now, node("dc1", "r1"), node("dc1", "r2"), node("dc1", "r3")), testDatacenter(3, "r1", "r2", "r3"), nil},
+		{"unfinished", unfinished, testDatacenter(1), errBackupNotFinished},
+		{"failed", failed, testDatacenter(1), errBackupFailed},
+		{"node count", testBackup("backup", now, node("dc1", "default")), testDatacenter(3), errTopologyMismatch},
+		{"datacenter name", testBackup("backup", now, node("dc2", "default")), testDatacenter(1), errTopologyMismatch},
+		{"rack names", testBackup("backup", now, node("dc1", "r1"), node("dc1", "r2")), testDatacenter(2, "r1", "r3"), errTopologyMismatch},
+		{"rack sizes", testBackup("backup", now, node("dc1", "r1"), node("dc1", "r1"), node("dc1", "r2")), testDatacenter(3, "r1", "r2", "r3"), errTopologyMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckTopology(tt.backup, tt.dc)
+			if tt.err == nil {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package medusa
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	"github.com/k8ssandra/k8ssandra-client/pkg/kubernetes"
+	medusaapi "github.com/k8ssandra/k8ssandra-operator/apis/medusa/v1alpha1"
+	medusagrpc "github.com/k8ssandra/k8ssandra-operator/pkg/medusa"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/shared"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var errNoReadyPod = errors.New("no ready pod to reach Medusa through")
+
+// StorageDeleter deletes the files of a backup from the storage bucket
+type StorageDeleter func(ctx context.Context, backup *medusaapi.MedusaBackup) error
+
+// NewStorageDeleter returns a StorageDeleter calling the gRPC API of the Medusa container in a ready pod of the
+// datacenter the backup was taken from, through a port forward. Medusa deletes the files of every node of the backup.
+func NewStorageDeleter(restConfig *rest.Config, kubeClient client.Client) StorageDeleter {
+	return func(ctx context.Context, backup *medusaapi.MedusaBackup) error {
+		pods := &corev1.PodList{}
+		if err := kubeClient.List(ctx, pods, client.InNamespace(backup.Namespace), client.MatchingLabels{cassdcapi.DatacenterLabel: cassdcapi.CleanLabelValue(backup.Spec.CassandraDatacenter)}); err != nil {
+			return err
+		}
+
+		for i := range pods.Items {
+			pod := &pods.Items[i]
+			if !kubernetes.PodReady(pod) {
+				continue
+			}
+
+			forward, err := kubernetes.ForwardPodPort(restConfig, pod.Namespace, pod.Name, shared.BackupSidecarPort)
+			if err != nil {
+				return err
+			}
+			defer forward.Close()
+
+			conn, err := grpc.NewClient(fmt.Sprintf("localhost:%d", forward.LocalPort), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			if err != nil {
+				return err
+			}
+			defer conn.Close()
+
+			_, err = medusagrpc.NewMedusaClient(conn).DeleteBackup(ctx, &medusagrpc.DeleteBackupRequest{Name: backup.Name})
+			return err
+		}
+
+		return fmt.Errorf("%w: cassandradatacenter/%s", errNoReadyPod, backup.Spec.CassandraDatacenter)
+	}
+}
@@ -0,0 +1,51 @@
+package mgmtapi
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/k8ssandra/cass-operator/pkg/httphelper"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// CheckNodeHealth verifies through the management-api of the pod that its node sees every node of the cluster UP and
+// NORMAL in gossip and that all the nodes agree on the schema
+func CheckNodeHealth(mgmtClient httphelper.NodeMgmtClient, pod *corev1.Pod) error {
+	endpoints, err := mgmtClient.CallMetadataEndpointsEndpoint(pod)
+	if err != nil {
+		return fmt.Errorf("unable to fetch gossip state from pod %s: %w", pod.Name, err)
+	}
+
+	if len(endpoints.Entity) == 0 {
+		return fmt.Errorf("pod %s does not see any nodes in gossip", pod.Name)
+	}
+
+	unhealthy := make([]string, 0)
+	for _, endpoint := range endpoints.Entity {
+		if endpoint.IsAlive != "true" {
+			unhealthy = append(unhealthy, fmt.Sprintf("%s is DOWN", endpoint.EndpointAddress()))
+			continue
+		}
+
+		if !endpoint.HasStatus(httphelper.StatusNormal) {
+			unhealthy = append(unhealthy, fmt.Sprintf("%s is %s", endpoint.EndpointAddress(), endpoint.Status))
+		}
+	}
+
+	if len(unhealthy) > 0 {
+		sort.Strings(unhealthy)
+		return fmt.Errorf("pod %s sees unhealthy nodes: %s", pod.Name, strings.Join(unhealthy, ", "))
+	}
+
+	versions, err := mgmtClient.CallSchemaVersionsEndpoint(pod)
+	if err != nil {
+		return fmt.Errorf("unable to fetch schema versions from pod %s: %w", pod.Name, err)
+	}
+
+	if len(versions) != 1 {
+		return fmt.Errorf("pod %s reports schema disagreement: %v", pod.Name, versions)
+	}
+
+	return nil
+}
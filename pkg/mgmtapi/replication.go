@@ -0,0 +1,63 @@
+package mgmtapi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/k8ssandra/cass-operator/pkg/httphelper"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	replicationClassKey  = "class"
+	replicationFactorKey = "replication_factor"
+)
+
+// DatacenterReplication returns the replication factor of every keyspace replicated to the Cassandra datacenter, as
+// seen by the node of the pod. SimpleStrategy keyspaces are included with their replication factor since they place
+// replicas without regard to datacenters. Keyspaces using LocalStrategy or EverywhereStrategy are not included.
+func DatacenterReplication(mgmtClient httphelper.NodeMgmtClient, pod *corev1.Pod, datacenter string) (map[string]int, error) {
+	keyspaces, err := mgmtClient.ListKeyspaces(pod)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list keyspaces from pod %s: %w", pod.Name, err)
+	}
+
+	factors := make(map[string]int, len(keyspaces))
+	for _, keyspace := range keyspaces {
+		replication, err := mgmtClient.GetKeyspaceReplication(pod, keyspace)
+		if err != nil {
+			return nil, fmt.Errorf("unable to fetch replication of keyspace %s from pod %s: %w", keyspace, pod.Name, err)
+		}
+
+		value, found := replication[datacenter]
+		if !found {
+			if !strings.HasSuffix(replication[replicationClassKey], "SimpleStrategy") {
+				continue
+			}
+			value = replication[replicationFactorKey]
+		}
+
+		rf, err := parseReplicationFactor(value)
+		if err != nil {
+			return nil, fmt.Errorf("keyspace %s: %w", keyspace, err)
+		}
+
+		if rf > 0 {
+			factors[keyspace] = rf
+		}
+	}
+
+	return factors, nil
+}
+
+// parseReplicationFactor parses a replication factor, including the "<replicas>/<transient>" form of transient
+// replication
+func parseReplicationFactor(value string) (int, error) {
+	replicas, _, _ := strings.Cut(value, "/")
+	rf, err := strconv.Atoi(strings.TrimSpace(replicas))
+	if err != nil {
+		return 0, fmt.Errorf("invalid replication factor %q", value)
+	}
+	return rf, nil
+}
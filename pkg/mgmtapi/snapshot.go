@@ -0,0 +1,52 @@
+package mgmtapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/k8ssandra/cass-operator/pkg/httphelper"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const snapshotsEndpoint = "/api/v0/ops/node/snapshots"
+
+type takeSnapshotRequest struct {
+	SnapshotName string `json:"snapshot_name"`
+}
+
+// TakeSnapshot takes a snapshot of every keyspace on the node of the pod. cass-operator has no task for snapshots and
+// its client does not expose the endpoint, thus the request is made here.
+func TakeSnapshot(mgmtClient httphelper.NodeMgmtClient, pod *corev1.Pod, name string) error {
+	host, port, err := httphelper.BuildPodHostFromPod(pod)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(&takeSnapshotRequest{SnapshotName: name})
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s://%s:%d%s", mgmtClient.Protocol, host, port, snapshotsEndpoint)
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := mgmtClient.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to take snapshot %s on pod %s: %w", name, pod.Name, err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		message, _ := io.ReadAll(res.Body)
+		return fmt.Errorf("unable to take snapshot %s on pod %s, status %d: %s", name, pod.Name, res.StatusCode, bytes.TrimSpace(message))
+	}
+
+	return nil
+}
@@ -0,0 +1,337 @@
+package migrate
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	k8ssandraapi "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	telemetryapi "github.com/k8ssandra/k8ssandra-operator/apis/telemetry/v1alpha1"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/cassandra"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/meta"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/unstructured"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	cassandraContainer    = "cassandra"
+	metricFiltersEnvVar   = "METRIC_FILTERS"
+	mcacDisabledEnvVar    = "MGMT_API_DISABLE_MCAC"
+	mgmtApiHeapSizeEnvVar = "MANAGEMENT_API_HEAP_SIZE"
+
+	// metricsAgentVolume is added by k8ssandra-operator to the storage config of every datacenter
+	metricsAgentVolume = "metrics-agent-config"
+)
+
+var errUnsupportedConfig = fmt.Errorf("the datacenter config has settings without a K8ssandraCluster equivalent")
+
+// NewCluster generates a K8ssandraCluster with a single datacenter adopting the CassandraDatacenter. The cluster name,
+// size, config, storage and superuser secret of the datacenter are kept. Settings k8ssandra-operator manages itself
+// (MCAC, management API heap, system replication) are converted back to their K8ssandraCluster fields.
+func NewCluster(dc *cassdcapi.CassandraDatacenter, name string) (*k8ssandraapi.K8ssandraCluster, error) {
+	config, err := clusterConfig(dc)
+	if err != nil {
+		return nil, err
+	}
+
+	podTemplate := &corev1.PodTemplateSpec{}
+	if dc.Spec.PodTemplateSpec != nil {
+		podTemplate = dc.Spec.PodTemplateSpec.DeepCopy()
+	}
+
+	telemetry, mgmtAPIHeap, err := operatorEnv(podTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	superuserSecret := dc.GetSuperuserSecretNamespacedName().Name
+
+	template := k8ssandraapi.CassandraDatacenterTemplate{
+		Meta: k8ssandraapi.EmbeddedObjectMeta{
+			Name:              dc.Name,
+			CommonLabels:      dc.Spec.AdditionalLabels,
+			CommonAnnotations: dc.Spec.AdditionalAnnotations,
+			Pods: meta.Tags{
+				Labels:      podTemplate.Labels,
+				Annotations: podTemplate.Annotations,
+			},
+			ServiceConfig: serviceConfig(dc.Spec.AdditionalServiceConfig),
+		},
+		Size:    dc.Spec.Size,
+		Stopped: dc.Spec.Stopped,
+		DatacenterOptions: k8ssandraapi.DatacenterOptions{
+			CassandraConfig:        config,
+			StorageConfig:          storageConfig(dc.Spec.StorageConfig),
+			Networking:             networking(dc.Spec.Networking),
+			Racks:                  dc.Spec.Racks,
+			Tolerations:            dc.Spec.Tolerations,
+			MgmtAPIHeap:            mgmtAPIHeap,
+			CDC:                    dc.Spec.CDC,
+			Containers:             podTemplate.Spec.Containers,
+			InitContainers:         podTemplate.Spec.InitContainers,
+			DseWorkloads:           dc.Spec.DseWorkloads,
+			PodSecurityContext:     podTemplate.Spec.SecurityContext,
+			PodPriorityClassName:   podTemplate.Spec.PriorityClassName,
+			ServiceAccount:         dc.Spec.ServiceAccountName,
+			DatacenterName:         dc.Spec.DatacenterName,
+			ReadOnlyRootFilesystem: dc.Spec.ReadOnlyRootFilesystem,
+		},
+	}
+
+	if len(podTemplate.Spec.Volumes) > 0 {
+		template.ExtraVolumes = &k8ssandraapi.K8ssandraVolumes{Volumes: podTemplate.Spec.Volumes}
+	}
+
+	if !reflect.DeepEqual(dc.Spec.Resources, corev1.ResourceRequirements{}) {
+		template.Resources = dc.Spec.Resources.DeepCopy()
+	}
+
+	if dc.Spec.AllowMultipleNodesPerWorker {
+		template.SoftPodAntiAffinity = ptr.To(true)
+	}
+
+	if !reflect.DeepEqual(dc.Spec.ManagementApiAuth, cassdcapi.ManagementApiAuthConfig{}) {
+		template.ManagementApiAuth = dc.Spec.ManagementApiAuth.DeepCopy()
+	}
+
+	kc := &k8ssandraapi.K8ssandraCluster{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: k8ssandraapi.GroupVersion.String(),
+			Kind:       "K8ssandraCluster",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: dc.Namespace,
+		},
+		Spec: k8ssandraapi.K8ssandraClusterSpec{
+			Auth: ptr.To(authEnabled(config)),
+			Cassandra: &k8ssandraapi.CassandraClusterTemplate{
+				ClusterName:        dc.Spec.ClusterName,
+				ServerType:         k8ssandraapi.ServerDistribution(dc.Spec.ServerType),
+				SuperuserSecretRef: corev1.LocalObjectReference{Name: superuserSecret},
+				DatacenterOptions: k8ssandraapi.DatacenterOptions{
+					ServerVersion: dc.Spec.ServerVersion,
+					ServerImage:   dc.Spec.ServerImage,
+					Telemetry:     telemetry,
+				},
+				Datacenters: []k8ssandraapi.CassandraDatacenterTemplate{template},
+			},
+		},
+	}
+
+	// The operator adds the system replication option of the first datacenter only if it is missing, keeping the
+	// current one avoids changing the JVM options
+	if replication := systemReplication(config); replication != "" {
+		kc.Annotations = map[string]string{k8ssandraapi.InitialSystemReplicationAnnotation: replication}
+	}
+
+	return kc, nil
+}
+
+// clusterConfig converts the config of the datacenter, in the cass-config-builder format, to the typed config of the
+// K8ssandraCluster. The JVM options are matched with the cass-config tags of the K8ssandraCluster fields.
+func clusterConfig(dc *cassdcapi.CassandraDatacenter) (*k8ssandraapi.CassandraConfig, error) {
+	if len(dc.Spec.Config) == 0 {
+		return nil, nil
+	}
+
+	sections := make(map[string]map[string]interface{})
+	if err := json.Unmarshal(dc.Spec.Config, &sections); err != nil {
+		return nil, err
+	}
+
+	fields := jvmOptionFields()
+	config := &k8ssandraapi.CassandraConfig{}
+	jvmOptions := make(map[string]interface{})
+	unsupported := make([]string, 0)
+
+	for section, values := range sections {
+		switch section {
+		case "cassandra-yaml":
+			config.CassandraYaml = unstructured.Unstructured(values)
+		case "dse-yaml":
+			config.DseYaml = unstructured.Unstructured(values)
+		default:
+			for key, value := range values {
+				path := section + "/" + key
+				field, found := fields[path]
+				if !found {
+					unsupported = append(unsupported, path)
+					continue
+				}
+				jvmOptions[field] = value
+			}
+		}
+	}
+
+	if len(unsupported) > 0 {
+		sort.Strings(unsupported)
+		return nil, fmt.Errorf("%w: %s", errUnsupportedConfig, strings.Join(unsupported, ", "))
+	}
+
+	data, err := json.Marshal(jvmOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &config.JvmOptions); err != nil {
+		return nil, fmt.Errorf("unable to convert the JVM options: %w", err)
+	}
+
+	return config, nil
+}
+
+// jvmOptionFields maps the cass-config paths of the JvmOptions fields, for all server versions, to their JSON names
+func jvmOptionFields() map[string]string {
+	fields := make(map[string]string)
+	t := reflect.TypeOf(k8ssandraapi.JvmOptions{})
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, found := field.Tag.Lookup("cass-config")
+		if !found {
+			continue
+		}
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		for _, part := range strings.Split(tag, ";") {
+			fields[part[strings.LastIndex(part, ":")+1:]] = name
+		}
+	}
+	return fields
+}
+
+// operatorEnv removes the environment variables k8ssandra-operator sets on the cassandra container and returns the
+// settings they come from
+func operatorEnv(podTemplate *corev1.PodTemplateSpec) (*telemetryapi.TelemetrySpec, *resource.Quantity, error) {
+	i, found := cassandra.FindContainer(podTemplate, cassandraContainer)
+	if !found {
+		return nil, nil, nil
+	}
+
+	container := &podTemplate.Spec.Containers[i]
+	var telemetry *telemetryapi.TelemetrySpec
+	var heap *resource.Quantity
+
+	env := make([]corev1.EnvVar, 0, len(container.Env))
+	for _, e := range container.Env {
+		switch {
+		case e.Name == metricFiltersEnvVar && e.ValueFrom == nil:
+			if telemetry == nil {
+				telemetry = &telemetryapi.TelemetrySpec{Mcac: &telemetryapi.McacTelemetrySpec{}}
+			}
+			telemetry.Mcac.MetricFilters = ptr.To(strings.Fields(e.Value))
+		case e.Name == mcacDisabledEnvVar && e.Value == "true":
+			if telemetry == nil {
+				telemetry = &telemetryapi.TelemetrySpec{Mcac: &telemetryapi.McacTelemetrySpec{}}
+			}
+			telemetry.Mcac.Enabled = ptr.To(false)
+		case e.Name == mgmtApiHeapSizeEnvVar && e.ValueFrom == nil:
+			bytes, err := strconv.ParseInt(e.Value, 10, 64)
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid %s value %q: %w", mgmtApiHeapSizeEnvVar, e.Value, err)
+			}
+			heap = resource.NewQuantity(bytes, resource.BinarySI)
+		default:
+			env = append(env, e)
+		}
+	}
+
+	if len(env) == 0 {
+		env = nil
+	}
+	container.Env = env
+
+	return telemetry, heap, nil
+}
+
+// authEnabled tells if the cassandra.yaml uses an authenticator, the default AllowAllAuthenticator means it is disabled
+func authEnabled(config *k8ssandraapi.CassandraConfig) bool {
+	if config == nil {
+		return false
+	}
+	authenticator, found := config.CassandraYaml["authenticator"]
+	if !found {
+		return false
+	}
+	name := fmt.Sprintf("%v", authenticator)
+	return name != "AllowAllAuthenticator" && name != "org.apache.cassandra.auth.AllowAllAuthenticator"
+}
+
+// systemReplication returns the system replication JVM option of the datacenter in the format of the
+// InitialSystemReplicationAnnotation, or an empty string if it has none
+func systemReplication(config *k8ssandraapi.CassandraConfig) string {
+	if config == nil {
+		return ""
+	}
+
+	for _, opt := range config.JvmOptions.AdditionalOptions {
+		value, found := strings.CutPrefix(opt, cassandra.SystemReplicationFactorStrategy+"=")
+		if !found {
+			continue
+		}
+
+		replication := make(map[string]int)
+		for _, dcReplication := range strings.Split(value, ",") {
+			dc, rf, found := strings.Cut(dcReplication, ":")
+			if !found {
+				return ""
+			}
+			factor, err := strconv.Atoi(rf)
+			if err != nil {
+				return ""
+			}
+			replication[dc] = factor
+		}
+
+		data, err := json.Marshal(replication)
+		if err != nil {
+			return ""
+		}
+		return string(data)
+	}
+
+	return ""
+}
+
+func storageConfig(s cassdcapi.StorageConfig) *cassdcapi.StorageConfig {
+	out := s.DeepCopy()
+	out.AdditionalVolumes = slices.DeleteFunc(out.AdditionalVolumes, func(v cassdcapi.AdditionalVolumes) bool {
+		return v.Name == metricsAgentVolume
+	})
+	if len(out.AdditionalVolumes) == 0 {
+		out.AdditionalVolumes = nil
+	}
+	return out
+}
+
+func networking(n *cassdcapi.NetworkingConfig) *k8ssandraapi.NetworkingConfig {
+	if n == nil {
+		return nil
+	}
+	out := &k8ssandraapi.NetworkingConfig{NodePort: n.NodePort}
+	if n.HostNetwork {
+		out.HostNetwork = ptr.To(true)
+	}
+	return out
+}
+
+func serviceConfig(s cassdcapi.ServiceConfig) meta.CassandraDatacenterServicesMeta {
+	tags := func(a cassdcapi.ServiceConfigAdditions) meta.Tags {
+		return meta.Tags{Labels: a.Labels, Annotations: a.Annotations}
+	}
+
+	return meta.CassandraDatacenterServicesMeta{
+		DatacenterService:     tags(s.DatacenterService),
+		SeedService:           tags(s.SeedService),
+		AdditionalSeedService: tags(s.AdditionalSeedService),
+		AllPodsService:        tags(s.AllPodsService),
+		NodePortService:       tags(s.NodePortService),
+	}
+}
@@ -0,0 +1,75 @@
+package migrate
+
+import (
+	"encoding/json"
+	"testing"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewCluster(t *testing.T) {
+	require := require.New(t)
+
+	dc := &cassdcapi.CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{Name: "dc1", Namespace: "ns1"},
+		Spec: cassdcapi.CassandraDatacenterSpec{
+			ClusterName:         "demo",
+			ServerType:          "cassandra",
+			ServerVersion:       "4.0.10",
+			Size:                3,
+			SuperuserSecretName: "my-superuser",
+			StorageConfig:       *testStorage(),
+			Config:              json.RawMessage(`{"cassandra-yaml": {"authenticator": "AllowAllAuthenticator"}, "jvm-server-options": {"initial_heap_size": "512M"}, "jvm11-server-options": {"garbage_collector": "G1GC"}}`),
+			PodTemplateSpec: &corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name: "cassandra",
+							Env: []corev1.EnvVar{
+								{Name: "MGMT_API_DISABLE_MCAC", Value: "true"},
+								{Name: "MANAGEMENT_API_HEAP_SIZE", Value: "268435456"},
+								{Name: "TZ", Value: "UTC"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	kc, err := NewCluster(dc, "demo")
+	require.NoError(err)
+	require.Equal("ns1", kc.Namespace)
+	require.False(*kc.Spec.Auth)
+	require.Equal("demo", kc.Spec.Cassandra.ClusterName)
+	require.Equal("my-superuser", kc.Spec.Cassandra.SuperuserSecretRef.Name)
+	require.Equal("4.0.10", kc.Spec.Cassandra.ServerVersion)
+	require.False(*kc.Spec.Cassandra.Telemetry.Mcac.Enabled)
+
+	template := kc.Spec.Cassandra.Datacenters[0]
+	require.Equal("dc1", template.Meta.Name)
+	require.Equal(int32(3), template.Size)
+	require.Equal("256Mi", template.MgmtAPIHeap.String())
+	require.Equal([]corev1.EnvVar{{Name: "TZ", Value: "UTC"}}, template.Containers[0].Env)
+	require.Equal("512M", template.CassandraConfig.JvmOptions.InitialHeapSize.String())
+	require.Equal("G1GC", *template.CassandraConfig.JvmOptions.GarbageCollector)
+	require.Equal("AllowAllAuthenticator", template.CassandraConfig.CassandraYaml["authenticator"])
+}
+
+func TestNewClusterUnsupportedConfig(t *testing.T) {
+	dc := &cassdcapi.CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{Name: "dc1", Namespace: "ns1"},
+		Spec: cassdcapi.CassandraDatacenterSpec{
+			ClusterName:   "demo",
+			ServerVersion: "4.0.10",
+			Config:        json.RawMessage(`{"cassandra-env-sh": {"malloc-arena-max": 4}, "logback-xml": {"debuglog-enabled": false}}`),
+		},
+	}
+
+	_, err := NewCluster(dc, "demo")
+	require.ErrorIs(t, err, errUnsupportedConfig)
+	require.ErrorContains(t, err, "cassandra-env-sh/malloc-arena-max, logback-xml/debuglog-enabled")
+}
@@ -0,0 +1,227 @@
+package migrate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	k8ssandraapi "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/annotations"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/cassandra"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/nodeconfig"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/secret"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/telemetry"
+	agent "github.com/k8ssandra/k8ssandra-operator/pkg/telemetry/cassandra_agent"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var (
+	errNoDatacenters      = errors.New("the K8ssandraCluster has no datacenters")
+	errUnsupportedCluster = errors.New("only K8ssandraClusters without Medusa, Reaper, Stargate and encryption stores can be rendered")
+	errMcacRequired       = errors.New("MCAC cannot be disabled before Cassandra 3.11.13 and 4.0.4")
+)
+
+// RenderDatacenter renders the CassandraDatacenter k8ssandra-operator creates for the first datacenter of the
+// K8ssandraCluster. It follows the steps of the operator reconciliation which do not need the Kubernetes API, the
+// same way as the operator version this client is built with.
+func RenderDatacenter(kc *k8ssandraapi.K8ssandraCluster) (*cassdcapi.CassandraDatacenter, error) {
+	if kc.Spec.Cassandra == nil || len(kc.Spec.Cassandra.Datacenters) == 0 {
+		return nil, errNoDatacenters
+	}
+
+	if kc.Spec.Medusa != nil || kc.Spec.Reaper != nil || kc.Spec.Stargate != nil ||
+		kc.Spec.Cassandra.ServerEncryptionStores != nil || kc.Spec.Cassandra.ClientEncryptionStores != nil {
+		return nil, errUnsupportedCluster
+	}
+
+	kcKey := types.NamespacedName{Namespace: kc.Namespace, Name: kc.Name}
+	dcTemplate := kc.Spec.Cassandra.Datacenters[0]
+
+	dcConfig := cassandra.Coalesce(kc.CassClusterName(), kc.Spec.Cassandra.DeepCopy(), dcTemplate.DeepCopy())
+	if err := cassandra.ValidateDatacenterConfig(dcConfig); err != nil {
+		return nil, err
+	}
+
+	dcConfig.ExternalSecrets = kc.Spec.UseExternalSecrets()
+	dcConfig.SuperuserSecretRef.Name = kc.Spec.Cassandra.SuperuserSecretRef.Name
+	if dcConfig.SuperuserSecretRef.Name == "" {
+		dcConfig.SuperuserSecretRef.Name = secret.DefaultSuperuserSecretName(kc.SanitizedName())
+	}
+
+	cassandra.ApplyAuth(dcConfig, kc.Spec.IsAuthEnabled(), kc.Spec.UseExternalSecrets(), false)
+
+	if kc.Spec.Cassandra.ServerType.IsCassandra() {
+		replication, err := initialSystemReplication(kc)
+		if err != nil {
+			return nil, err
+		}
+		cassandra.ApplySystemReplication(dcConfig, replication)
+
+		if dcConfig.ServerVersion.Major() != 3 {
+			cassandra.AllowAlterRfDuringRangeMovement(dcConfig)
+		}
+	}
+
+	if kc.Spec.Cassandra.Telemetry.IsMcacEnabled() {
+		telemetry.InjectCassandraTelemetryFilters(kc.Spec.Cassandra.Telemetry, dcConfig)
+	}
+
+	mergedTelemetry := dcTemplate.Telemetry.MergeWith(kc.Spec.Cassandra.Telemetry)
+	if !mergedTelemetry.IsMcacEnabled() && !telemetry.IsNewMetricsEndpointAvailable(dcConfig.ServerVersion.String()) && kc.Spec.Cassandra.ServerType == k8ssandraapi.ServerDistributionCassandra {
+		return nil, errMcacRequired
+	}
+
+	cassandra.AddNumTokens(dcConfig)
+	cassandra.AddStartRpc(dcConfig)
+	cassandra.HandleDeprecatedJvmOptions(&dcConfig.CassandraConfig.JvmOptions)
+	cassandra.EnableSmartTokenAllocation(dcConfig)
+
+	// Like the operator, the initial tokens are only computed when the config allows it
+	_ = cassandra.ComputeInitialTokens([]*cassandra.DatacenterConfig{dcConfig})
+
+	if dcConfig.PerNodeConfigMapRef.Name == "" {
+		if perNodeConfig := nodeconfig.NewDefaultPerNodeConfigMap(kcKey, kc, dcConfig); perNodeConfig != nil {
+			annotations.AddHashAnnotation(perNodeConfig)
+			dcConfig.PerNodeConfigMapRef.Name = perNodeConfig.Name
+			annotations.AddAnnotation(&dcConfig.PodTemplateSpec, k8ssandraapi.PerNodeConfigHashAnnotation, annotations.GetAnnotation(perNodeConfig, k8ssandraapi.ResourceHashAnnotation))
+			nodeconfig.MountPerNodeConfig(dcConfig)
+		}
+	}
+
+	dc, err := cassandra.NewDatacenter(kcKey, dcConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	configurator := agent.Configurator{Kluster: kc, DcNamespace: dc.Namespace, DcName: dc.DatacenterName()}
+	if err := configurator.AddVolumeSource(dc); err != nil {
+		return nil, err
+	}
+
+	return dc, nil
+}
+
+func initialSystemReplication(kc *k8ssandraapi.K8ssandraCluster) (cassandra.SystemReplication, error) {
+	if value := annotations.GetAnnotation(kc, k8ssandraapi.InitialSystemReplicationAnnotation); value != "" {
+		replication := make(cassandra.SystemReplication)
+		if err := json.Unmarshal([]byte(value), &replication); err != nil {
+			return nil, fmt.Errorf("invalid %s annotation: %w", k8ssandraapi.InitialSystemReplicationAnnotation, err)
+		}
+		return replication, nil
+	}
+
+	return cassandra.ComputeReplicationFromDatacenters(3, kc.Spec.ExternalDatacenters, kc.Spec.Cassandra.Datacenters[0]), nil
+}
+
+// Compare lists the spec fields of the rendered datacenter which differ from the existing one, as JSON paths. The
+// differences of the config are listed per setting. The operator keeps the superuser secret of existing datacenters
+// and labels their all pods service, neither restarts the pods so they are not compared.
+func Compare(rendered, actual *cassdcapi.CassandraDatacenter) ([]string, error) {
+	desired := rendered.Spec.DeepCopy()
+	current := actual.Spec.DeepCopy()
+
+	desired.SuperuserSecretName = current.SuperuserSecretName
+	for _, label := range []string{k8ssandraapi.K8ssandraClusterNameLabel, k8ssandraapi.K8ssandraClusterNamespaceLabel} {
+		delete(desired.AdditionalServiceConfig.AllPodsService.Labels, label)
+		delete(current.AdditionalServiceConfig.AllPodsService.Labels, label)
+	}
+
+	differences := make([]string, 0)
+
+	desiredVal := reflect.ValueOf(*desired)
+	currentVal := reflect.ValueOf(*current)
+	t := desiredVal.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		path := "spec." + name
+
+		switch field.Name {
+		case "Config":
+			configDiffs, err := compareConfig(path, desired.Config, current.Config)
+			if err != nil {
+				return nil, err
+			}
+			differences = append(differences, configDiffs...)
+		case "PodTemplateSpec":
+			if !equality.Semantic.DeepEqual(normalizePodTemplate(desired.PodTemplateSpec), normalizePodTemplate(current.PodTemplateSpec)) {
+				differences = append(differences, path)
+			}
+		default:
+			if !equality.Semantic.DeepEqual(desiredVal.Field(i).Interface(), currentVal.Field(i).Interface()) {
+				differences = append(differences, path)
+			}
+		}
+	}
+
+	sort.Strings(differences)
+	return differences, nil
+}
+
+func compareConfig(path string, desired, current json.RawMessage) ([]string, error) {
+	desiredMap := make(map[string]interface{})
+	currentMap := make(map[string]interface{})
+
+	if len(desired) > 0 {
+		if err := json.Unmarshal(desired, &desiredMap); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(current) > 0 {
+		if err := json.Unmarshal(current, &currentMap); err != nil {
+			return nil, err
+		}
+	}
+
+	return compareMaps(path, desiredMap, currentMap), nil
+}
+
+func compareMaps(path string, desired, current map[string]interface{}) []string {
+	keys := make(map[string]bool)
+	for k := range desired {
+		keys[k] = true
+	}
+	for k := range current {
+		keys[k] = true
+	}
+
+	differences := make([]string, 0)
+	for k := range keys {
+		d, c := desired[k], current[k]
+		dMap, dIsMap := d.(map[string]interface{})
+		cMap, cIsMap := c.(map[string]interface{})
+		if dIsMap && cIsMap {
+			differences = append(differences, compareMaps(path+"."+k, dMap, cMap)...)
+		} else if !reflect.DeepEqual(d, c) {
+			differences = append(differences, path+"."+k)
+		}
+	}
+	return differences
+}
+
+// normalizePodTemplate drops the containers with only a name, the operator declares the cassandra container even if
+// nothing is set on it
+func normalizePodTemplate(podTemplate *corev1.PodTemplateSpec) *corev1.PodTemplateSpec {
+	normalized := &corev1.PodTemplateSpec{}
+	if podTemplate != nil {
+		normalized = podTemplate.DeepCopy()
+	}
+
+	containers := make([]corev1.Container, 0, len(normalized.Spec.Containers))
+	for _, c := range normalized.Spec.Containers {
+		if !reflect.DeepEqual(c, corev1.Container{Name: c.Name}) {
+			containers = append(containers, c)
+		}
+	}
+	normalized.Spec.Containers = containers
+
+	return normalized
+}
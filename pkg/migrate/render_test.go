@@ -0,0 +1,101 @@
+package migrate
+
+import (
+	"encoding/json"
+	"testing"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	k8ssandraapi "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	"github.com/k8ssandra/k8ssandra-operator/pkg/unstructured"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func testStorage() *cassdcapi.StorageConfig {
+	return &cassdcapi.StorageConfig{
+		CassandraDataVolumeClaimSpec: &corev1.PersistentVolumeClaimSpec{
+			StorageClassName: ptr.To("standard"),
+			AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+			},
+		},
+	}
+}
+
+func TestRenderDatacenterRoundTrip(t *testing.T) {
+	require := require.New(t)
+
+	heap := resource.MustParse("1Gi")
+	original := &k8ssandraapi.K8ssandraCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "ns1"},
+		Spec: k8ssandraapi.K8ssandraClusterSpec{
+			Cassandra: &k8ssandraapi.CassandraClusterTemplate{
+				ClusterName: "Demo Cluster",
+				ServerType:  k8ssandraapi.ServerDistributionCassandra,
+				DatacenterOptions: k8ssandraapi.DatacenterOptions{
+					ServerVersion: "4.1.5",
+					StorageConfig: testStorage(),
+					CassandraConfig: &k8ssandraapi.CassandraConfig{
+						CassandraYaml: unstructured.Unstructured{"concurrent_reads": int64(64)},
+						JvmOptions:    k8ssandraapi.JvmOptions{MaxHeapSize: &heap, InitialHeapSize: &heap},
+					},
+				},
+				Datacenters: []k8ssandraapi.CassandraDatacenterTemplate{
+					{Meta: k8ssandraapi.EmbeddedObjectMeta{Name: "dc1"}, Size: 3},
+				},
+			},
+		},
+	}
+
+	dc, err := RenderDatacenter(original)
+	require.NoError(err)
+	require.Equal("Demo Cluster", dc.Spec.ClusterName)
+	require.Equal("democluster-superuser", dc.Spec.SuperuserSecretName)
+
+	// A datacenter created by the operator can be adopted again without changes
+	kc, err := NewCluster(dc, "demo")
+	require.NoError(err)
+	require.True(*kc.Spec.Auth)
+	require.JSONEq(`{"dc1":3}`, kc.Annotations[k8ssandraapi.InitialSystemReplicationAnnotation])
+
+	rendered, err := RenderDatacenter(kc)
+	require.NoError(err)
+
+	differences, err := Compare(rendered, dc)
+	require.NoError(err)
+	require.Empty(differences)
+}
+
+func TestCompareStandaloneDatacenter(t *testing.T) {
+	require := require.New(t)
+
+	dc := &cassdcapi.CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{Name: "dc1", Namespace: "ns1"},
+		Spec: cassdcapi.CassandraDatacenterSpec{
+			ClusterName:   "demo",
+			ServerType:    "cassandra",
+			ServerVersion: "4.1.5",
+			Size:          3,
+			StorageConfig: *testStorage(),
+			Config:        json.RawMessage(`{"cassandra-yaml": {"num_tokens": 16, "authenticator": "PasswordAuthenticator", "authorizer": "CassandraAuthorizer", "role_manager": "CassandraRoleManager"}, "jvm-server-options": {"max_heap_size": 1073741824}}`),
+		},
+	}
+
+	kc, err := NewCluster(dc, "demo")
+	require.NoError(err)
+
+	rendered, err := RenderDatacenter(kc)
+	require.NoError(err)
+
+	differences, err := Compare(rendered, dc)
+	require.NoError(err)
+	require.Equal([]string{
+		"spec.config.cassandra-env-sh",
+		"spec.podTemplateSpec",
+		"spec.storageConfig",
+	}, differences)
+}
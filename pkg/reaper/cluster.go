@@ -0,0 +1,121 @@
+package reaper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	k8ssandraapi "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	reaperapi "github.com/k8ssandra/k8ssandra-operator/apis/reaper/v1alpha1"
+	operatorreaper "github.com/k8ssandra/k8ssandra-operator/pkg/reaper"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ServicePort is the port of the Reaper REST API in the Reaper service
+const ServicePort = 8080
+
+var (
+	errReaperNotEnabled   = errors.New("reaper is not enabled in the K8ssandraCluster")
+	errDatacenterNotFound = errors.New("the datacenter is not part of the K8ssandraCluster")
+	errNoRunningReaper    = errors.New("every datacenter of the K8ssandraCluster is stopped, no Reaper is running")
+	errInvalidUiSecret    = errors.New("the reaper UI secret must have username and password keys")
+)
+
+// ServiceKey returns the Reaper service serving the K8ssandraCluster, following the naming of k8ssandra-operator. In
+// the PER_DC deployment mode dcName selects the Reaper of that datacenter, otherwise the first running datacenter is
+// used.
+func ServiceKey(kc *k8ssandraapi.K8ssandraCluster, dcName string) (types.NamespacedName, error) {
+	if kc.Spec.Reaper == nil {
+		return types.NamespacedName{}, errReaperNotEnabled
+	}
+
+	if kc.Spec.Reaper.HasReaperRef() {
+		namespace := kc.Spec.Reaper.ReaperRef.Namespace
+		if namespace == "" {
+			namespace = kc.Namespace
+		}
+		return types.NamespacedName{Namespace: namespace, Name: operatorreaper.GetServiceName(kc.Spec.Reaper.ReaperRef.Name)}, nil
+	}
+
+	if kc.Spec.Cassandra == nil {
+		return types.NamespacedName{}, errNoRunningReaper
+	}
+
+	perDc := dcName != "" && kc.Spec.Reaper.DeploymentMode != reaperapi.DeploymentModeSingle
+
+	var target *k8ssandraapi.CassandraDatacenterTemplate
+	for i := range kc.Spec.Cassandra.Datacenters {
+		dc := &kc.Spec.Cassandra.Datacenters[i]
+		if (perDc && dc.Meta.Name == dcName) || (!perDc && !dc.Stopped) {
+			target = dc
+			break
+		}
+	}
+
+	if target == nil {
+		if perDc {
+			return types.NamespacedName{}, fmt.Errorf("%w: %s", errDatacenterNotFound, dcName)
+		}
+		return types.NamespacedName{}, errNoRunningReaper
+	}
+
+	namespace := target.Meta.Namespace
+	if namespace == "" {
+		namespace = kc.Namespace
+	}
+
+	dc := &cassdcapi.CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{Name: target.Meta.Name},
+		Spec:       cassdcapi.CassandraDatacenterSpec{ClusterName: kc.CassClusterName(), DatacenterName: target.DatacenterName},
+	}
+	name := operatorreaper.DefaultResourceName(dc)
+	return types.NamespacedName{Namespace: namespace, Name: operatorreaper.GetServiceName(name)}, nil
+}
+
+// ClusterName returns the name Reaper knows the cluster by
+func ClusterName(kc *k8ssandraapi.K8ssandraCluster) string {
+	return cassdcapi.CleanupForKubernetes(kc.CassClusterName())
+}
+
+// Credentials returns the Reaper UI and REST API user from the secret referenced by the K8ssandraCluster, or the
+// secret the operator generates when authentication is enabled. Empty values are returned if Reaper has no
+// authentication.
+func Credentials(ctx context.Context, kubeClient client.Client, kc *k8ssandraapi.K8ssandraCluster) (string, string, error) {
+	if kc.Spec.Reaper == nil {
+		return "", "", errReaperNotEnabled
+	}
+
+	secretName := uiSecretName(kc)
+	if secretName == "" {
+		return "", "", nil
+	}
+
+	secret := &corev1.Secret{}
+	if err := kubeClient.Get(ctx, types.NamespacedName{Namespace: kc.Namespace, Name: secretName}, secret); err != nil {
+		return "", "", err
+	}
+
+	username, password := secret.Data["username"], secret.Data["password"]
+	if len(username) == 0 || len(password) == 0 {
+		return "", "", fmt.Errorf("%w: secret/%s", errInvalidUiSecret, secretName)
+	}
+
+	return string(username), string(password), nil
+}
+
+func uiSecretName(kc *k8ssandraapi.K8ssandraCluster) string {
+	if ref := kc.Spec.Reaper.UiUserSecretRef; ref != nil {
+		return ref.Name
+	}
+
+	// A referenced Reaper is not configured by the operator, it only uses the secret set in the K8ssandraCluster
+	if kc.Spec.Reaper.HasReaperRef() || !kc.Spec.IsAuthEnabled() {
+		return ""
+	}
+
+	return operatorreaper.DefaultUiSecretName(kc.SanitizedName())
+}
@@ -0,0 +1,92 @@
+package reaper
+
+import (
+	"context"
+	"testing"
+
+	k8ssandraapi "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	reaperapi "github.com/k8ssandra/k8ssandra-operator/apis/reaper/v1alpha1"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func testCluster(reaper *reaperapi.ReaperClusterTemplate) *k8ssandraapi.K8ssandraCluster {
+	return &k8ssandraapi.K8ssandraCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "ns1"},
+		Spec: k8ssandraapi.K8ssandraClusterSpec{
+			Reaper: reaper,
+			Cassandra: &k8ssandraapi.CassandraClusterTemplate{
+				ClusterName: "Demo Cluster",
+				Datacenters: []k8ssandraapi.CassandraDatacenterTemplate{
+					{Meta: k8ssandraapi.EmbeddedObjectMeta{Name: "dc1"}, Stopped: true},
+					{Meta: k8ssandraapi.EmbeddedObjectMeta{Name: "dc2", Namespace: "ns2"}, DatacenterOptions: k8ssandraapi.DatacenterOptions{DatacenterName: "East"}},
+				},
+			},
+		},
+	}
+}
+
+func TestServiceKey(t *testing.T) {
+	tests := []struct {
+		name   string
+		reaper *reaperapi.ReaperClusterTemplate
+		dc     string
+		want   types.NamespacedName
+		err    error
+	}{
+		{"not enabled", nil, "", types.NamespacedName{}, errReaperNotEnabled},
+		{"first running datacenter", &reaperapi.ReaperClusterTemplate{}, "", types.NamespacedName{Namespace: "ns2", Name: "democluster-east-reaper-service"}, nil},
+		{"chosen datacenter", &reaperapi.ReaperClusterTemplate{DeploymentMode: reaperapi.DeploymentModePerDc}, "dc1", types.NamespacedName{Namespace: "ns1", Name: "democluster-dc1-reaper-service"}, nil},
+		{"unknown datacenter", &reaperapi.ReaperClusterTemplate{}, "dc3", types.NamespacedName{}, errDatacenterNotFound},
+		{"single ignores datacenter", &reaperapi.ReaperClusterTemplate{DeploymentMode: reaperapi.DeploymentModeSingle}, "dc1", types.NamespacedName{Namespace: "ns2", Name: "democluster-east-reaper-service"}, nil},
+		{"reaper reference", &reaperapi.ReaperClusterTemplate{ReaperRef: corev1.ObjectReference{Name: "central"}}, "", types.NamespacedName{Namespace: "ns1", Name: "central-service"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := ServiceKey(testCluster(tt.reaper), tt.dc)
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, key)
+		})
+	}
+}
+
+func TestCredentials(t *testing.T) {
+	require := require.New(t)
+
+	kubeClient := fake.NewClientBuilder().WithObjects(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "democluster-reaper-ui", Namespace: "ns1"},
+			Data:       map[string][]byte{"username": []byte("reaper"), "password": []byte("secret")},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "custom-ui", Namespace: "ns1"},
+			Data:       map[string][]byte{"username": []byte("custom")},
+		},
+	).Build()
+
+	// The operator generates the secret when authentication is enabled
+	kc := testCluster(&reaperapi.ReaperClusterTemplate{})
+	username, password, err := Credentials(context.TODO(), kubeClient, kc)
+	require.NoError(err)
+	require.Equal("reaper", username)
+	require.Equal("secret", password)
+
+	kc.Spec.Reaper.UiUserSecretRef = &corev1.LocalObjectReference{Name: "custom-ui"}
+	_, _, err = Credentials(context.TODO(), kubeClient, kc)
+	require.ErrorIs(err, errInvalidUiSecret)
+
+	kc.Spec.Reaper.UiUserSecretRef = nil
+	kc.Spec.Auth = ptr.To(false)
+	username, _, err = Credentials(context.TODO(), kubeClient, kc)
+	require.NoError(err)
+	require.Empty(username)
+}
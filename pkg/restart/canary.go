@@ -0,0 +1,313 @@
+package restart
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	"github.com/k8ssandra/k8ssandra-client/pkg/cassdcutil"
+	"github.com/k8ssandra/k8ssandra-client/pkg/kubernetes"
+	"github.com/k8ssandra/k8ssandra-client/pkg/mgmtapi"
+	"github.com/k8ssandra/k8ssandra-client/pkg/tasks"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	waitutil "k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	defaultInterval = 5 * time.Second
+	defaultTimeout  = 10 * time.Minute
+)
+
+var (
+	errNoPods          = errors.New("datacenter has no pods")
+	errUnknownCanary   = errors.New("canary pod is not part of the datacenter")
+	errCanaryRestarted = errors.New("canary restart failed")
+)
+
+// HealthChecker returns nil once the node of the pod is healthy
+type HealthChecker func(ctx context.Context, pod *corev1.Pod) error
+
+// CanaryOptions modify the behavior of CanaryRestart
+type CanaryOptions struct {
+	// CanaryPod is restarted first, defaults to the first pod of the first rack
+	CanaryPod string
+
+	// Timeout limits each step, restarting the canary or a rack and waiting for the nodes to be healthy
+	Timeout time.Duration
+
+	// Interval is the polling interval of the pod, task and health checks
+	Interval time.Duration
+
+	// HealthChecker defaults to the management-api gossip and schema agreement check
+	HealthChecker HealthChecker
+
+	// OnStep is called when a step starts and when it has finished
+	OnStep func(step *Step)
+}
+
+// Step is a single phase of the canary restart
+type Step struct {
+	Name     string
+	Pods     []string
+	Finished bool
+	Err      error
+}
+
+// Report lists the steps CanaryRestart took, the last step is the one that failed if the restart did not complete
+type Report struct {
+	Steps []*Step
+}
+
+// Failed returns the failed step or nil
+func (r *Report) Failed() *Step {
+	for _, step := range r.Steps {
+		if step.Err != nil {
+			return step
+		}
+	}
+	return nil
+}
+
+type canary struct {
+	kubeClient client.Client
+	dc         *cassdcapi.CassandraDatacenter
+	opts       CanaryOptions
+	report     *Report
+}
+
+// CanaryRestart restarts a single canary pod of the datacenter and verifies its node comes back healthy before
+// restarting the datacenter rack by rack, checking the health of the nodes after each rack. The other pods of the
+// canary's rack are restarted one at a time, so the canary is not restarted twice. The restart stops at the
+// first failure, the returned Report describes how far the restart got.
+func CanaryRestart(ctx context.Context, kubeClient client.Client, dc *cassdcapi.CassandraDatacenter, opts CanaryOptions) (*Report, error) {
+	if opts.Timeout == 0 {
+		opts.Timeout = defaultTimeout
+	}
+
+	if opts.Interval == 0 {
+		opts.Interval = defaultInterval
+	}
+
+	if opts.HealthChecker == nil {
+		mgmtClient, err := mgmtapi.NewManagementClient(ctx, kubeClient, dc.Namespace, dc.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		opts.HealthChecker = func(ctx context.Context, pod *corev1.Pod) error {
+			return mgmtapi.CheckNodeHealth(mgmtClient, pod)
+		}
+	}
+
+	c := &canary{
+		kubeClient: kubeClient,
+		dc:         dc,
+		opts:       opts,
+		report:     &Report{},
+	}
+
+	return c.report, c.run(ctx)
+}
+
+func (c *canary) run(ctx context.Context) error {
+	racks, err := c.rackPods(ctx)
+	if err != nil {
+		return err
+	}
+
+	canaryPod, err := c.canaryPod(racks)
+	if err != nil {
+		return err
+	}
+
+	if err := c.step("verify cluster health before the restart", []string{canaryPod.Name}, func() error {
+		return c.waitHealthy(ctx, []corev1.Pod{*canaryPod})
+	}); err != nil {
+		return err
+	}
+
+	if err := c.step("restart canary", []string{canaryPod.Name}, func() error {
+		return c.restartPod(ctx, canaryPod)
+	}); err != nil {
+		return fmt.Errorf("%w: %w", errCanaryRestarted, err)
+	}
+
+	canaryRack := canaryPod.Labels[cassdcapi.RackLabel]
+	for _, rack := range c.dc.GetRacks() {
+		rackLabel := cassdcapi.CleanLabelValue(rack.Name)
+		pods := racks[rackLabel]
+
+		restart := func() error {
+			return c.restartRack(ctx, rack.Name)
+		}
+
+		// A restart task would restart the canary again, the other pods of its rack are restarted one by one
+		if rackLabel == canaryRack {
+			pods = slices.DeleteFunc(slices.Clone(pods), func(pod corev1.Pod) bool {
+				return pod.Name == canaryPod.Name
+			})
+			restart = func() error {
+				for i := range pods {
+					if err := c.restartPod(ctx, &pods[i]); err != nil {
+						return err
+					}
+				}
+				return nil
+			}
+		}
+
+		if len(pods) == 0 {
+			continue
+		}
+
+		if err := c.step(fmt.Sprintf("restart rack %s", rack.Name), podNames(pods), restart); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *canary) step(name string, pods []string, f func() error) error {
+	step := &Step{Name: name, Pods: pods}
+	c.report.Steps = append(c.report.Steps, step)
+	c.notify(step)
+
+	step.Err = f()
+	step.Finished = true
+	c.notify(step)
+
+	return step.Err
+}
+
+func (c *canary) notify(step *Step) {
+	if c.opts.OnStep != nil {
+		c.opts.OnStep(step)
+	}
+}
+
+// rackPods returns the pods of the datacenter grouped by their rack label
+func (c *canary) rackPods(ctx context.Context) (map[string][]corev1.Pod, error) {
+	podList, err := cassdcutil.NewManager(c.kubeClient).CassandraDatacenterPods(ctx, c.dc)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(podList.Items) == 0 {
+		return nil, errNoPods
+	}
+
+	racks := make(map[string][]corev1.Pod)
+	for _, pod := range podList.Items {
+		rack := pod.Labels[cassdcapi.RackLabel]
+		racks[rack] = append(racks[rack], pod)
+	}
+
+	for _, pods := range racks {
+		sort.Slice(pods, func(i, j int) bool {
+			return pods[i].Name < pods[j].Name
+		})
+	}
+
+	return racks, nil
+}
+
+func (c *canary) canaryPod(racks map[string][]corev1.Pod) (*corev1.Pod, error) {
+	for _, rack := range c.dc.GetRacks() {
+		for _, pod := range racks[cassdcapi.CleanLabelValue(rack.Name)] {
+			if c.opts.CanaryPod == "" || c.opts.CanaryPod == pod.Name {
+				return &pod, nil
+			}
+		}
+	}
+
+	if c.opts.CanaryPod != "" {
+		return nil, fmt.Errorf("%w: %s", errUnknownCanary, c.opts.CanaryPod)
+	}
+
+	return nil, errNoPods
+}
+
+// restartPod deletes the pod and waits for the StatefulSet to bring it back ready and healthy
+func (c *canary) restartPod(ctx context.Context, pod *corev1.Pod) error {
+	if err := c.kubeClient.Delete(ctx, pod, client.Preconditions{UID: &pod.UID}); err != nil {
+		return err
+	}
+
+	var replaced *corev1.Pod
+	err := waitutil.PollUntilContextTimeout(ctx, c.opts.Interval, c.opts.Timeout, false, func(ctx context.Context) (bool, error) {
+		current := &corev1.Pod{}
+		if err := c.kubeClient.Get(ctx, types.NamespacedName{Name: pod.Name, Namespace: pod.Namespace}, current); err != nil {
+			return false, client.IgnoreNotFound(err)
+		}
+
+		if current.UID == pod.UID || !kubernetes.PodReady(current) {
+			return false, nil
+		}
+
+		replaced = current
+		return true, nil
+	})
+	if err != nil {
+		return fmt.Errorf("pod %s did not become ready: %w", pod.Name, err)
+	}
+
+	return c.waitHealthy(ctx, []corev1.Pod{*replaced})
+}
+
+// restartRack runs a restart task for the rack and checks the health of its nodes afterwards
+func (c *canary) restartRack(ctx context.Context, rackName string) error {
+	task, err := tasks.CreateRestartTask(ctx, c.kubeClient, c.dc, rackName)
+	if err != nil {
+		return err
+	}
+
+	result, err := tasks.WaitForTask(ctx, c.kubeClient, types.NamespacedName{Name: task.Name, Namespace: task.Namespace}, tasks.WithInterval(c.opts.Interval), tasks.WithTimeout(c.opts.Timeout))
+	if err != nil {
+		return err
+	}
+
+	if err := result.Err(); err != nil {
+		return err
+	}
+
+	racks, err := c.rackPods(ctx)
+	if err != nil {
+		return err
+	}
+
+	return c.waitHealthy(ctx, racks[cassdcapi.CleanLabelValue(rackName)])
+}
+
+// waitHealthy waits until every pod reports a healthy cluster, returning the last health error on timeout
+func (c *canary) waitHealthy(ctx context.Context, pods []corev1.Pod) error {
+	var lastErr error
+	err := waitutil.PollUntilContextTimeout(ctx, c.opts.Interval, c.opts.Timeout, true, func(ctx context.Context) (bool, error) {
+		for i := range pods {
+			if lastErr = c.opts.HealthChecker(ctx, &pods[i]); lastErr != nil {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+
+	if err != nil && lastErr != nil {
+		return lastErr
+	}
+
+	return err
+}
+
+func podNames(pods []corev1.Pod) []string {
+	names := make([]string, 0, len(pods))
+	for _, pod := range pods {
+		names = append(names, pod.Name)
+	}
+	return names
+}
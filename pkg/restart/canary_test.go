@@ -0,0 +1,239 @@
+package restart
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	controlapi "github.com/k8ssandra/cass-operator/apis/control/v1alpha1"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func testDatacenter() *cassdcapi.CassandraDatacenter {
+	return &cassdcapi.CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{Name: "dc1", Namespace: "ns1"},
+		Spec: cassdcapi.CassandraDatacenterSpec{
+			Size:  4,
+			Racks: []cassdcapi.Rack{{Name: "r2"}, {Name: "r1"}},
+		},
+	}
+}
+
+func testPod(name, rack string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "ns1",
+			UID:       types.UID(name),
+			Labels:    map[string]string{cassdcapi.DatacenterLabel: "dc1", cassdcapi.RackLabel: rack},
+		},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+		},
+	}
+}
+
+func testClient(t *testing.T) client.Client {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, cassdcapi.AddToScheme(scheme))
+	require.NoError(t, controlapi.AddToScheme(scheme))
+
+	return fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(
+			testDatacenter(),
+			testPod("dc1-r1-sts-0", "r1"), testPod("dc1-r1-sts-1", "r1"),
+			testPod("dc1-r2-sts-1", "r2"), testPod("dc1-r2-sts-0", "r2"),
+		).
+		WithStatusSubresource(&controlapi.CassandraTask{}).
+		Build()
+}
+
+// fakeOperator recreates deleted pods and completes the restart tasks until the context is cancelled
+func fakeOperator(ctx context.Context, kubeClient client.Client, deleted chan<- string) {
+	existing := []string{"dc1-r1-sts-0", "dc1-r1-sts-1", "dc1-r2-sts-0", "dc1-r2-sts-1"}
+	generation := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(10 * time.Millisecond):
+		}
+
+		for _, name := range existing {
+			pod := &corev1.Pod{}
+			if err := kubeClient.Get(ctx, types.NamespacedName{Name: name, Namespace: "ns1"}, pod); err == nil {
+				continue
+			}
+			generation++
+			deleted <- name
+			replacement := testPod(name, name[4:6])
+			replacement.UID = types.UID(fmt.Sprintf("%s-%d", name, generation))
+			_ = kubeClient.Create(ctx, replacement)
+		}
+
+		taskList := &controlapi.CassandraTaskList{}
+		if err := kubeClient.List(ctx, taskList); err != nil {
+			continue
+		}
+		for i := range taskList.Items {
+			task := &taskList.Items[i]
+			if task.Status.CompletionTime != nil {
+				continue
+			}
+			now := metav1.Now()
+			task.Status.CompletionTime = &now
+			task.Status.Succeeded = 1
+			_ = kubeClient.Status().Update(ctx, task)
+		}
+	}
+}
+
+func runCanary(t *testing.T, opts CanaryOptions) (client.Client, *Report, []string, error) {
+	kubeClient := testClient(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	deleted := make(chan string, 10)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		fakeOperator(ctx, kubeClient, deleted)
+	}()
+
+	opts.Interval = 10 * time.Millisecond
+	if opts.Timeout == 0 {
+		opts.Timeout = 2 * time.Second
+	}
+
+	report, err := CanaryRestart(context.Background(), kubeClient, testDatacenter(), opts)
+	cancel()
+	wg.Wait()
+	close(deleted)
+
+	deletedPods := make([]string, 0)
+	for name := range deleted {
+		deletedPods = append(deletedPods, name)
+	}
+
+	return kubeClient, report, deletedPods, err
+}
+
+func stepNames(report *Report) []string {
+	names := make([]string, 0, len(report.Steps))
+	for _, step := range report.Steps {
+		names = append(names, step.Name)
+	}
+	return names
+}
+
+func TestCanaryRestart(t *testing.T) {
+	require := require.New(t)
+
+	var checked []string
+	var mu sync.Mutex
+	healthy := func(ctx context.Context, pod *corev1.Pod) error {
+		mu.Lock()
+		defer mu.Unlock()
+		checked = append(checked, pod.Name)
+		return nil
+	}
+
+	kubeClient, report, deleted, err := runCanary(t, CanaryOptions{HealthChecker: healthy})
+	require.NoError(err)
+	require.Nil(report.Failed())
+
+	// The canary is the first pod of the first rack in the datacenter's rack order, the rest of its rack is restarted
+	// pod by pod and the canary exactly once
+	require.Equal([]string{"dc1-r2-sts-0", "dc1-r2-sts-1"}, deleted)
+	require.Equal([]string{
+		"verify cluster health before the restart",
+		"restart canary",
+		"restart rack r2",
+		"restart rack r1",
+	}, stepNames(report))
+	require.Equal([]string{"dc1-r2-sts-1"}, report.Steps[2].Pods)
+	require.Equal([]string{"dc1-r1-sts-0", "dc1-r1-sts-1"}, report.Steps[3].Pods)
+	require.Subset(checked, []string{"dc1-r1-sts-0", "dc1-r1-sts-1", "dc1-r2-sts-0", "dc1-r2-sts-1"})
+
+	// Only the rack without the canary is restarted with a task
+	taskList := &controlapi.CassandraTaskList{}
+	require.NoError(kubeClient.List(context.Background(), taskList))
+	require.Len(taskList.Items, 1)
+	require.Equal(controlapi.CommandRestart, taskList.Items[0].Spec.Jobs[0].Command)
+	require.Equal("r1", taskList.Items[0].Spec.Jobs[0].Arguments.RackName)
+}
+
+func TestCanaryRestartSelectedPod(t *testing.T) {
+	require := require.New(t)
+
+	healthy := func(ctx context.Context, pod *corev1.Pod) error { return nil }
+
+	_, report, deleted, err := runCanary(t, CanaryOptions{CanaryPod: "dc1-r1-sts-1", HealthChecker: healthy})
+	require.NoError(err)
+	require.Nil(report.Failed())
+	require.Equal([]string{"dc1-r1-sts-1", "dc1-r1-sts-0"}, deleted)
+
+	_, _, _, err = runCanary(t, CanaryOptions{CanaryPod: "other-pod", HealthChecker: healthy})
+	require.ErrorIs(err, errUnknownCanary)
+}
+
+func TestCanaryRestartUnhealthyCanary(t *testing.T) {
+	require := require.New(t)
+
+	errGossip := errors.New("pod dc1-r2-sts-0 sees unhealthy nodes: 10.0.0.1 is DOWN")
+	canaryRestarted := false
+	var mu sync.Mutex
+	checker := func(ctx context.Context, pod *corev1.Pod) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if pod.UID != types.UID(pod.Name) {
+			canaryRestarted = true
+		}
+		if canaryRestarted {
+			return errGossip
+		}
+		return nil
+	}
+
+	kubeClient, report, deleted, err := runCanary(t, CanaryOptions{HealthChecker: checker, Timeout: 100 * time.Millisecond})
+	require.ErrorIs(err, errCanaryRestarted)
+	require.ErrorIs(err, errGossip)
+	require.Equal([]string{"dc1-r2-sts-0"}, deleted)
+
+	failed := report.Failed()
+	require.NotNil(failed)
+	require.Equal("restart canary", failed.Name)
+	require.Equal([]string{"dc1-r2-sts-0"}, failed.Pods)
+	require.Len(report.Steps, 2)
+
+	// No rack was restarted after the canary failed
+	taskList := &controlapi.CassandraTaskList{}
+	require.NoError(kubeClient.List(context.Background(), taskList))
+	require.Empty(taskList.Items)
+}
+
+func TestCanaryRestartUnhealthyBefore(t *testing.T) {
+	require := require.New(t)
+
+	errSchema := errors.New("pod dc1-r2-sts-0 reports schema disagreement")
+	checker := func(ctx context.Context, pod *corev1.Pod) error { return errSchema }
+
+	_, report, deleted, err := runCanary(t, CanaryOptions{HealthChecker: checker, Timeout: 100 * time.Millisecond})
+	require.ErrorIs(err, errSchema)
+	require.Empty(deleted)
+	require.Equal([]string{"verify cluster health before the restart"}, stepNames(report))
+}
@@ -0,0 +1,203 @@
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	cassandraContainer    = "cassandra"
+	systemLoggerContainer = "server-system-logger"
+	configInitContainer   = "server-config-init"
+
+	// zoneLabel is the node label cass-operator pins the deprecated rack zone to
+	zoneLabel = "failure-domain.beta.kubernetes.io/zone"
+)
+
+// DatacenterPods returns the pods cass-operator would add to the racks of the datacenter when Spec.Size grows to
+// newSize. The pods are built from the datacenter spec the same way cass-operator builds the StatefulSets: resources,
+// node selector, tolerations, node affinity of the datacenter and its racks and the anti-affinity keeping one Cassandra
+// node per worker.
+func DatacenterPods(dc *cassdcapi.CassandraDatacenter, newSize int) []*corev1.Pod {
+	racks := dc.GetRacks()
+	current := cassdcapi.SplitRacks(int(dc.Spec.Size), len(racks))
+	wanted := cassdcapi.SplitRacks(newSize, len(racks))
+
+	pods := make([]*corev1.Pod, 0)
+	for i, rack := range racks {
+		for ordinal := current[i]; ordinal < wanted[i]; ordinal++ {
+			pods = append(pods, rackPod(dc, rack, ordinal))
+		}
+	}
+
+	return pods
+}
+
+func rackPod(dc *cassdcapi.CassandraDatacenter, rack cassdcapi.Rack, ordinal int) *corev1.Pod {
+	template := &corev1.PodTemplateSpec{}
+	if dc.Spec.PodTemplateSpec != nil {
+		template = dc.Spec.PodTemplateSpec.DeepCopy()
+	}
+
+	stsName := fmt.Sprintf("%s-%s-%s-sts", cassdcapi.CleanupForKubernetes(dc.Spec.ClusterName), dc.LabelResourceName(), cassdcapi.CleanupSubdomain(rack.Name))
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%d", stsName, ordinal),
+			Namespace: dc.Namespace,
+			Labels: map[string]string{
+				cassdcapi.ClusterLabel:    cassdcapi.CleanLabelValue(dc.Spec.ClusterName),
+				cassdcapi.DatacenterLabel: cassdcapi.CleanLabelValue(dc.DatacenterName()),
+				cassdcapi.RackLabel:       cassdcapi.CleanLabelValue(rack.Name),
+			},
+		},
+		Spec: template.Spec,
+	}
+
+	pod.Spec.Containers = withContainerResources(pod.Spec.Containers, cassandraContainer, dc.Spec.Resources)
+	pod.Spec.Containers = withContainerResources(pod.Spec.Containers, systemLoggerContainer, dc.Spec.SystemLoggerResources)
+	pod.Spec.InitContainers = withContainerResources(pod.Spec.InitContainers, configInitContainer, dc.Spec.ConfigBuilderResources)
+
+	pod.Spec.NodeSelector = mergeLabels(pod.Spec.NodeSelector, dc.Spec.NodeSelector)
+	pod.Spec.Tolerations = append(pod.Spec.Tolerations, dc.Spec.Tolerations...)
+
+	var rackAffinity *corev1.Affinity
+	if rack.Affinity != nil {
+		rackAffinity = rack.Affinity
+	} else {
+		rackAffinity = &corev1.Affinity{}
+	}
+
+	affinity := pod.Spec.Affinity
+	if affinity == nil {
+		affinity = &corev1.Affinity{}
+	}
+
+	affinity.NodeAffinity = nodeAffinity(rackNodeAffinityLabels(dc, rack), affinity.NodeAffinity, rackAffinity.NodeAffinity)
+	affinity.PodAntiAffinity = podAntiAffinity(dc.Spec.AllowMultipleNodesPerWorker, affinity.PodAntiAffinity, rackAffinity.PodAntiAffinity)
+	pod.Spec.Affinity = affinity
+
+	return pod
+}
+
+// withContainerResources sets the resources of the named container, adding the container if the template does not
+// have it. Unset requests default to the limits like in the API server.
+func withContainerResources(containers []corev1.Container, name string, resources corev1.ResourceRequirements) []corev1.Container {
+	resources = *resources.DeepCopy()
+	if len(resources.Requests) == 0 && len(resources.Limits) > 0 {
+		resources.Requests = resources.Limits.DeepCopy()
+	}
+
+	for i := range containers {
+		if containers[i].Name == name {
+			if len(resources.Requests) > 0 {
+				containers[i].Resources = resources
+			}
+			return containers
+		}
+	}
+
+	return append(containers, corev1.Container{Name: name, Resources: resources})
+}
+
+func rackNodeAffinityLabels(dc *cassdcapi.CassandraDatacenter, rack cassdcapi.Rack) map[string]string {
+	labels := mergeLabels(nil, dc.Spec.NodeAffinityLabels, rack.NodeAffinityLabels)
+	if rack.DeprecatedZone != "" {
+		labels[zoneLabel] = rack.DeprecatedZone
+	}
+	return labels
+}
+
+// nodeAffinity requires the node affinity labels and adds the terms of the pod template and the rack, like cass-operator
+func nodeAffinity(labels map[string]string, existing, rack *corev1.NodeAffinity) *corev1.NodeAffinity {
+	result := &corev1.NodeAffinity{}
+
+	if len(labels) > 0 {
+		keys := make([]string, 0, len(labels))
+		for key := range labels {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		requirements := make([]corev1.NodeSelectorRequirement, 0, len(keys))
+		for _, key := range keys {
+			requirements = append(requirements, corev1.NodeSelectorRequirement{
+				Key:      key,
+				Operator: corev1.NodeSelectorOpIn,
+				Values:   []string{labels[key]},
+			})
+		}
+
+		result.RequiredDuringSchedulingIgnoredDuringExecution = &corev1.NodeSelector{
+			NodeSelectorTerms: []corev1.NodeSelectorTerm{{MatchExpressions: requirements}},
+		}
+	}
+
+	for _, other := range []*corev1.NodeAffinity{existing, rack} {
+		if other == nil || other.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+			continue
+		}
+
+		if result.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+			result.RequiredDuringSchedulingIgnoredDuringExecution = other.RequiredDuringSchedulingIgnoredDuringExecution.DeepCopy()
+			continue
+		}
+
+		result.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms = append(result.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms, other.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms...)
+	}
+
+	if result.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return nil
+	}
+
+	return result
+}
+
+// podAntiAffinity keeps the Cassandra pods on separate workers unless multiple nodes per worker are allowed
+func podAntiAffinity(allowMultipleNodesPerWorker bool, existing, rack *corev1.PodAntiAffinity) *corev1.PodAntiAffinity {
+	result := &corev1.PodAntiAffinity{}
+	if !allowMultipleNodesPerWorker {
+		result.RequiredDuringSchedulingIgnoredDuringExecution = []corev1.PodAffinityTerm{
+			{
+				LabelSelector: &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{Key: cassdcapi.ClusterLabel, Operator: metav1.LabelSelectorOpExists},
+						{Key: cassdcapi.DatacenterLabel, Operator: metav1.LabelSelectorOpExists},
+						{Key: cassdcapi.RackLabel, Operator: metav1.LabelSelectorOpExists},
+					},
+				},
+				TopologyKey: corev1.LabelHostname,
+			},
+		}
+	}
+
+	for _, other := range []*corev1.PodAntiAffinity{existing, rack} {
+		if other != nil {
+			result.RequiredDuringSchedulingIgnoredDuringExecution = append(result.RequiredDuringSchedulingIgnoredDuringExecution, other.RequiredDuringSchedulingIgnoredDuringExecution...)
+		}
+	}
+
+	if len(result.RequiredDuringSchedulingIgnoredDuringExecution) == 0 {
+		return nil
+	}
+
+	return result
+}
+
+func mergeLabels(destination map[string]string, sources ...map[string]string) map[string]string {
+	if destination == nil {
+		destination = make(map[string]string)
+	}
+
+	for _, source := range sources {
+		for key, value := range source {
+			destination[key] = value
+		}
+	}
+
+	return destination
+}
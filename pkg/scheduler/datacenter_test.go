@@ -0,0 +1,111 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+
+	api "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func makeDatacenter() *api.CassandraDatacenter {
+	return &api.CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{Name: "dc1", Namespace: "ns1"},
+		Spec: api.CassandraDatacenterSpec{
+			ClusterName: "demo",
+			Size:        3,
+			Racks: []api.Rack{
+				{Name: "r1", NodeAffinityLabels: map[string]string{"topology.kubernetes.io/zone": "a"}},
+				{Name: "r2", DeprecatedZone: "b"},
+				{Name: "r3"},
+			},
+			Resources: corev1.ResourceRequirements{
+				Limits: makeResources(500, 500, 1),
+			},
+			Tolerations: []corev1.Toleration{
+				{Key: "cassandra.datastax.com/node-purpose", Operator: corev1.TolerationOpEqual, Value: "database", Effect: corev1.TaintEffectNoSchedule},
+			},
+		},
+	}
+}
+
+func TestDatacenterPods(t *testing.T) {
+	require := require.New(t)
+
+	dc := makeDatacenter()
+	pods := DatacenterPods(dc, 5)
+	require.Len(pods, 2)
+
+	// cass-operator adds the extra nodes to the first racks
+	require.Equal("demo-dc1-r1-sts-1", pods[0].Name)
+	require.Equal("demo-dc1-r2-sts-1", pods[1].Name)
+	require.Equal("r2", pods[1].Labels[api.RackLabel])
+
+	for _, pod := range pods {
+		require.Equal(dc.Spec.Tolerations, pod.Spec.Tolerations)
+
+		var cassandra *corev1.Container
+		for i := range pod.Spec.Containers {
+			if pod.Spec.Containers[i].Name == cassandraContainer {
+				cassandra = &pod.Spec.Containers[i]
+			}
+		}
+		require.NotNil(cassandra)
+		require.True(cassandra.Resources.Requests.Cpu().Equal(*dc.Spec.Resources.Limits.Cpu()))
+
+		require.NotNil(pod.Spec.Affinity.PodAntiAffinity)
+	}
+
+	zone := pods[0].Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].MatchExpressions[0]
+	require.Equal("topology.kubernetes.io/zone", zone.Key)
+	require.Equal([]string{"a"}, zone.Values)
+
+	zone = pods[1].Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].MatchExpressions[0]
+	require.Equal(zoneLabel, zone.Key)
+	require.Equal([]string{"b"}, zone.Values)
+
+	require.Empty(DatacenterPods(dc, 3))
+	require.Empty(DatacenterPods(dc, 2))
+
+	dc.Spec.AllowMultipleNodesPerWorker = true
+	require.Nil(DatacenterPods(dc, 4)[0].Spec.Affinity.PodAntiAffinity)
+}
+
+func TestDatacenterPodsScheduling(t *testing.T) {
+	require := require.New(t)
+	ctx := context.TODO()
+	cli := createClient()
+
+	dc := makeDatacenter()
+	dc.Spec.Racks = []api.Rack{{Name: "r1", NodeAffinityLabels: map[string]string{"topology.kubernetes.io/zone": "a"}}}
+	dc.Spec.Size = 1
+
+	zoneNode := func(name, zone string) *corev1.Node {
+		n := makeNode(name)
+		n.Labels = map[string]string{"topology.kubernetes.io/zone": zone, corev1.LabelHostname: name}
+		n.Spec.Taints = []corev1.Taint{{Key: "cassandra.datastax.com/node-purpose", Value: "database", Effect: corev1.TaintEffectNoSchedule}}
+		return n
+	}
+
+	require.NoError(cli.Create(ctx, zoneNode("node1", "a")))
+	require.NoError(cli.Create(ctx, zoneNode("node2", "b")))
+
+	// node1 already runs the existing Cassandra pod, the anti-affinity keeps the new pod away from it and node2 is in
+	// the wrong zone
+	existing := makePod("demo-dc1-r1-sts-0", makeResources(100, 100, 1))
+	existing.Namespace = "ns1"
+	existing.Labels = map[string]string{api.ClusterLabel: "demo", api.DatacenterLabel: "dc1", api.RackLabel: "r1"}
+	existing.Spec.NodeName = "node1"
+	require.NoError(cli.Create(ctx, existing))
+
+	require.Error(TryScheduling(ctx, cli, DatacenterPods(dc, 2)))
+
+	dc.Spec.AllowMultipleNodesPerWorker = true
+	require.NoError(TryScheduling(ctx, cli, DatacenterPods(dc, 2)))
+	dc.Spec.AllowMultipleNodesPerWorker = false
+
+	require.NoError(cli.Create(ctx, zoneNode("node3", "a")))
+	require.NoError(TryScheduling(ctx, cli, DatacenterPods(dc, 2)))
+}
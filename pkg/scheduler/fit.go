@@ -3,6 +3,7 @@ package scheduler
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	"k8s.io/kubernetes/pkg/scheduler/apis/config"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
@@ -13,6 +14,8 @@ import (
 	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/tainttoleration"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -108,6 +111,10 @@ NextPod:
 				return err
 			}
 
+			if conflictsWithPods(pod, node) {
+				continue NextNode
+			}
+
 			for _, plugin := range plugins {
 				var preFilterStatus, filterStatus *framework.Status
 				if prefilterPlugin, ok := plugin.(framework.PreFilterPlugin); ok {
@@ -138,6 +145,42 @@ NextPod:
 	return nil
 }
 
+// conflictsWithPods checks the required pod anti-affinity terms of the hostname topology against the pods already
+// running on the node, as the interpodaffinity plugin can not be used here
+func conflictsWithPods(pod *corev1.Pod, node *framework.NodeInfo) bool {
+	if pod.Spec.Affinity == nil || pod.Spec.Affinity.PodAntiAffinity == nil {
+		return false
+	}
+
+	for _, term := range pod.Spec.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution {
+		if term.TopologyKey != corev1.LabelHostname || term.LabelSelector == nil {
+			continue
+		}
+
+		selector, err := metav1.LabelSelectorAsSelector(term.LabelSelector)
+		if err != nil {
+			continue
+		}
+
+		namespaces := term.Namespaces
+		if len(namespaces) == 0 && term.NamespaceSelector == nil {
+			namespaces = []string{pod.Namespace}
+		}
+
+		for _, existing := range node.Pods {
+			if len(namespaces) > 0 && !slices.Contains(namespaces, existing.Pod.Namespace) {
+				continue
+			}
+
+			if selector.Matches(labels.Set(existing.Pod.Labels)) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 var defaultScoringStrategy = &config.ScoringStrategy{
 	Type: config.LeastAllocated,
 	Resources: []config.ResourceSpec{
@@ -11,14 +11,59 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-func CreateClusterTask(ctx context.Context, kubeClient client.Client, command controlapi.CassandraCommand, namespace, kcName string, datacenters []string, args *controlapi.JobArguments) (*k8ssandrataskapi.K8ssandraTask, error) {
+func CreateClusterTask(ctx context.Context, kubeClient client.Client, command controlapi.CassandraCommand, namespace, kcName string, datacenters []string, args *controlapi.JobArguments, opts ...TaskOption) (*k8ssandrataskapi.K8ssandraTask, error) {
 	if kcName == "" || namespace == "" {
 		return nil, fmt.Errorf("clusterName and namespace must be specified")
 	}
 
+	job := controlapi.CassandraJob{
+		Command: command,
+	}
+	if args != nil {
+		job.Arguments = *args
+	}
+
+	o, err := newTaskOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	task := newClusterTask(namespace, kcName, string(command), datacenters, []controlapi.CassandraJob{job}, o)
+	if err := o.create(ctx, kubeClient, task); err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+// CreateClusterJobsTask creates a single K8ssandraTask which runs the given jobs in order in each of the datacenters
+func CreateClusterJobsTask(ctx context.Context, kubeClient client.Client, namespace, kcName string, datacenters []string, jobs []controlapi.CassandraJob, opts ...TaskOption) (*k8ssandrataskapi.K8ssandraTask, error) {
+	if kcName == "" || namespace == "" {
+		return nil, fmt.Errorf("clusterName and namespace must be specified")
+	}
+
+	jobs, err := prepareJobs(kcName, jobs)
+	if err != nil {
+		return nil, err
+	}
+
+	o, err := newTaskOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	task := newClusterTask(namespace, kcName, pipelineNamePart(jobs), datacenters, jobs, o)
+	if err := o.create(ctx, kubeClient, task); err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+func newClusterTask(namespace, kcName, namePart string, datacenters []string, jobs []controlapi.CassandraJob, o *taskOptions) *k8ssandrataskapi.K8ssandraTask {
 	task := &k8ssandrataskapi.K8ssandraTask{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      createName(kcName, string(command)),
+			Name:      createName(kcName, namePart),
 			Namespace: namespace,
 		},
 		Spec: k8ssandrataskapi.K8ssandraTaskSpec{
@@ -26,27 +71,18 @@ func CreateClusterTask(ctx context.Context, kubeClient client.Client, command co
 				Name:      kcName,
 				Namespace: namespace,
 			},
+			DcConcurrencyPolicy: o.dcConcurrencyPolicy,
 			Template: controlapi.CassandraTaskTemplate{
-				Jobs: []controlapi.CassandraJob{
-					{
-						Command: command,
-					},
-				},
+				Jobs: jobs,
 			},
 		},
 	}
 
+	o.applyTemplate(&task.Spec.Template)
+
 	if len(datacenters) > 0 && datacenters[0] != "" {
 		task.Spec.Datacenters = datacenters
 	}
 
-	if args != nil {
-		task.Spec.Template.Jobs[0].Arguments = *args
-	}
-
-	if err := kubeClient.Create(ctx, task); err != nil {
-		return nil, err
-	}
-
-	return task, nil
+	return task
 }
@@ -3,10 +3,12 @@ package tasks
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
 	controlapi "github.com/k8ssandra/cass-operator/apis/control/v1alpha1"
 	k8ssandrataskapi "github.com/k8ssandra/k8ssandra-operator/apis/control/v1alpha1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -14,9 +16,9 @@ import (
 
 // Restart
 
-func CreateRestartTask(ctx context.Context, kubeClient client.Client, dc *cassdcapi.CassandraDatacenter, rackName string) (*controlapi.CassandraTask, error) {
+func CreateRestartTask(ctx context.Context, kubeClient client.Client, dc *cassdcapi.CassandraDatacenter, rackName string, opts ...TaskOption) (*controlapi.CassandraTask, error) {
 	args := restartArguments(rackName)
-	return CreateTask(ctx, kubeClient, controlapi.CommandRestart, dc, args)
+	return CreateTask(ctx, kubeClient, controlapi.CommandRestart, dc, args, opts...)
 }
 
 func restartArguments(rackName string) *controlapi.JobArguments {
@@ -27,20 +29,35 @@ func restartArguments(rackName string) *controlapi.JobArguments {
 	return args
 }
 
-func CreateClusterRestartTask(ctx context.Context, kubeClient client.Client, namespace, cluster, dcName, rackName string) (*k8ssandrataskapi.K8ssandraTask, error) {
+func CreateClusterRestartTask(ctx context.Context, kubeClient client.Client, namespace, cluster, dcName, rackName string, opts ...TaskOption) (*k8ssandrataskapi.K8ssandraTask, error) {
 	args := restartArguments(rackName)
-	return CreateClusterTask(ctx, kubeClient, controlapi.CommandRestart, namespace, cluster, []string{dcName}, args)
+	return CreateClusterTask(ctx, kubeClient, controlapi.CommandRestart, namespace, cluster, []string{dcName}, args, opts...)
+}
+
+// CreateClusterDatacentersRestartTask creates a single K8ssandraTask doing a rolling restart of the given datacenters one
+// datacenter at a time, in the given order. Every datacenter is restarted if none are given, in the order of the
+// K8ssandraCluster spec.
+func CreateClusterDatacentersRestartTask(ctx context.Context, kubeClient client.Client, namespace, cluster string, datacenters []string, rackName string, opts ...TaskOption) (*k8ssandrataskapi.K8ssandraTask, error) {
+	args := restartArguments(rackName)
+	opts = append([]TaskOption{WithDcConcurrencyPolicy(batchv1.ForbidConcurrent)}, opts...)
+
+	// k8ssandra-operator prepends every datacenter it finds in the spec of the task to the list it runs, so they are run
+	// in the reverse order of the spec
+	reversed := slices.Clone(datacenters)
+	slices.Reverse(reversed)
+
+	return CreateClusterTask(ctx, kubeClient, controlapi.CommandRestart, namespace, cluster, reversed, args, opts...)
 }
 
 // Replace
 
-func CreateReplaceTask(ctx context.Context, kubeClient client.Client, dc *cassdcapi.CassandraDatacenter, podName string) (*controlapi.CassandraTask, error) {
+func CreateReplaceTask(ctx context.Context, kubeClient client.Client, dc *cassdcapi.CassandraDatacenter, podName string, opts ...TaskOption) (*controlapi.CassandraTask, error) {
 	args, err := replaceArguments(podName)
 	if err != nil {
 		return nil, err
 	}
 
-	return CreateTask(ctx, kubeClient, controlapi.CommandReplaceNode, dc, args)
+	return CreateTask(ctx, kubeClient, controlapi.CommandReplaceNode, dc, args, opts...)
 }
 
 func replaceArguments(podName string) (*controlapi.JobArguments, error) {
@@ -50,71 +67,71 @@ func replaceArguments(podName string) (*controlapi.JobArguments, error) {
 	return &controlapi.JobArguments{PodName: podName}, nil
 }
 
-func CreateClusterReplaceTask(ctx context.Context, kubeClient client.Client, namespace, cluster, dcName, podName string) (*k8ssandrataskapi.K8ssandraTask, error) {
+func CreateClusterReplaceTask(ctx context.Context, kubeClient client.Client, namespace, cluster, dcName, podName string, opts ...TaskOption) (*k8ssandrataskapi.K8ssandraTask, error) {
 	args, err := replaceArguments(podName)
 	if err != nil {
 		return nil, err
 	}
 
-	return CreateClusterTask(ctx, kubeClient, controlapi.CommandReplaceNode, namespace, cluster, []string{dcName}, args)
+	return CreateClusterTask(ctx, kubeClient, controlapi.CommandReplaceNode, namespace, cluster, []string{dcName}, args, opts...)
 }
 
 // Flush
 
-func CreateFlushTask(ctx context.Context, kubeClient client.Client, dc *cassdcapi.CassandraDatacenter, rackName string, podName string) (*controlapi.CassandraTask, error) {
+func CreateFlushTask(ctx context.Context, kubeClient client.Client, dc *cassdcapi.CassandraDatacenter, rackName string, podName string, opts ...TaskOption) (*controlapi.CassandraTask, error) {
 	args := commonArguments(rackName, podName)
-	return CreateTask(ctx, kubeClient, controlapi.CommandFlush, dc, args)
+	return CreateTask(ctx, kubeClient, controlapi.CommandFlush, dc, args, opts...)
 }
 
-func CreateClusterFlushTask(ctx context.Context, kubeClient client.Client, namespace, cluster, dcName, rackName, podName string) (*k8ssandrataskapi.K8ssandraTask, error) {
+func CreateClusterFlushTask(ctx context.Context, kubeClient client.Client, namespace, cluster, dcName, rackName, podName string, opts ...TaskOption) (*k8ssandrataskapi.K8ssandraTask, error) {
 	args := commonArguments(rackName, podName)
-	return CreateClusterTask(ctx, kubeClient, controlapi.CommandFlush, namespace, cluster, []string{dcName}, args)
+	return CreateClusterTask(ctx, kubeClient, controlapi.CommandFlush, namespace, cluster, []string{dcName}, args, opts...)
 }
 
 // Cleanup
 
-func CreateCleanupTask(ctx context.Context, kubeClient client.Client, dc *cassdcapi.CassandraDatacenter, rackName string, podName string) (*controlapi.CassandraTask, error) {
+func CreateCleanupTask(ctx context.Context, kubeClient client.Client, dc *cassdcapi.CassandraDatacenter, rackName string, podName string, opts ...TaskOption) (*controlapi.CassandraTask, error) {
 	args := commonArguments(rackName, podName)
-	return CreateTask(ctx, kubeClient, controlapi.CommandCleanup, dc, args)
+	return CreateTask(ctx, kubeClient, controlapi.CommandCleanup, dc, args, opts...)
 }
 
-func CreateClusterCleanupTask(ctx context.Context, kubeClient client.Client, namespace, cluster, dcName, rackName, podName string) (*k8ssandrataskapi.K8ssandraTask, error) {
+func CreateClusterCleanupTask(ctx context.Context, kubeClient client.Client, namespace, cluster, dcName, rackName, podName string, opts ...TaskOption) (*k8ssandrataskapi.K8ssandraTask, error) {
 	args := commonArguments(rackName, podName)
-	return CreateClusterTask(ctx, kubeClient, controlapi.CommandCleanup, namespace, cluster, []string{dcName}, args)
+	return CreateClusterTask(ctx, kubeClient, controlapi.CommandCleanup, namespace, cluster, []string{dcName}, args, opts...)
 }
 
 // UpgradeSSTables
 
-func CreateUpgradeSSTablesTask(ctx context.Context, kubeClient client.Client, dc *cassdcapi.CassandraDatacenter, rackName string, podName string) (*controlapi.CassandraTask, error) {
+func CreateUpgradeSSTablesTask(ctx context.Context, kubeClient client.Client, dc *cassdcapi.CassandraDatacenter, rackName string, podName string, opts ...TaskOption) (*controlapi.CassandraTask, error) {
 	args := commonArguments(rackName, podName)
-	return CreateTask(ctx, kubeClient, controlapi.CommandUpgradeSSTables, dc, args)
+	return CreateTask(ctx, kubeClient, controlapi.CommandUpgradeSSTables, dc, args, opts...)
 }
 
-func CreateClusterUpgradeSSTablesTask(ctx context.Context, kubeClient client.Client, namespace, cluster, dcName, rackName, podName string) (*k8ssandrataskapi.K8ssandraTask, error) {
+func CreateClusterUpgradeSSTablesTask(ctx context.Context, kubeClient client.Client, namespace, cluster, dcName, rackName, podName string, opts ...TaskOption) (*k8ssandrataskapi.K8ssandraTask, error) {
 	args := commonArguments(rackName, podName)
-	return CreateClusterTask(ctx, kubeClient, controlapi.CommandUpgradeSSTables, namespace, cluster, []string{dcName}, args)
+	return CreateClusterTask(ctx, kubeClient, controlapi.CommandUpgradeSSTables, namespace, cluster, []string{dcName}, args, opts...)
 }
 
 // Scrub
 
-func CreateScrubTask(ctx context.Context, kubeClient client.Client, dc *cassdcapi.CassandraDatacenter, rackName string, podName string) (*controlapi.CassandraTask, error) {
+func CreateScrubTask(ctx context.Context, kubeClient client.Client, dc *cassdcapi.CassandraDatacenter, rackName string, podName string, opts ...TaskOption) (*controlapi.CassandraTask, error) {
 	args := commonArguments(rackName, podName)
-	return CreateTask(ctx, kubeClient, controlapi.CommandScrub, dc, args)
+	return CreateTask(ctx, kubeClient, controlapi.CommandScrub, dc, args, opts...)
 }
 
-func CreateClusterScrubTask(ctx context.Context, kubeClient client.Client, namespace, cluster, dcName, rackName, podName string) (*k8ssandrataskapi.K8ssandraTask, error) {
+func CreateClusterScrubTask(ctx context.Context, kubeClient client.Client, namespace, cluster, dcName, rackName, podName string, opts ...TaskOption) (*k8ssandrataskapi.K8ssandraTask, error) {
 	args := commonArguments(rackName, podName)
-	return CreateClusterTask(ctx, kubeClient, controlapi.CommandScrub, namespace, cluster, []string{dcName}, args)
+	return CreateClusterTask(ctx, kubeClient, controlapi.CommandScrub, namespace, cluster, []string{dcName}, args, opts...)
 }
 
 // Compaction
 
-func CreateCompactionTask(ctx context.Context, kubeClient client.Client, dc *cassdcapi.CassandraDatacenter, rackName string, podName, keyspaceName string, tables []string) (*controlapi.CassandraTask, error) {
+func CreateCompactionTask(ctx context.Context, kubeClient client.Client, dc *cassdcapi.CassandraDatacenter, rackName string, podName, keyspaceName string, tables []string, opts ...TaskOption) (*controlapi.CassandraTask, error) {
 	args, err := compactionArguments(rackName, podName, keyspaceName, tables)
 	if err != nil {
 		return nil, err
 	}
-	return CreateTask(ctx, kubeClient, controlapi.CommandCompaction, dc, args)
+	return CreateTask(ctx, kubeClient, controlapi.CommandCompaction, dc, args, opts...)
 }
 
 func compactionArguments(rackName, podName, keyspaceName string, tables []string) (*controlapi.JobArguments, error) {
@@ -132,36 +149,84 @@ func compactionArguments(rackName, podName, keyspaceName string, tables []string
 	return args, nil
 }
 
-func CreateClusterCompactionTask(ctx context.Context, kubeClient client.Client, namespace, cluster, dcName, rackName, podName, keyspaceName string, tables []string) (*k8ssandrataskapi.K8ssandraTask, error) {
+func CreateClusterCompactionTask(ctx context.Context, kubeClient client.Client, namespace, cluster, dcName, rackName, podName, keyspaceName string, tables []string, opts ...TaskOption) (*k8ssandrataskapi.K8ssandraTask, error) {
 	args, err := compactionArguments(rackName, podName, keyspaceName, tables)
 	if err != nil {
 		return nil, err
 	}
-	return CreateClusterTask(ctx, kubeClient, controlapi.CommandCompaction, namespace, cluster, []string{dcName}, args)
+	return CreateClusterTask(ctx, kubeClient, controlapi.CommandCompaction, namespace, cluster, []string{dcName}, args, opts...)
 }
 
 // Move
 
+// CreateMoveTask creates a task moving the node of each pod in newTokens to its new token. The datacenter must use
+// single tokens and the tokens are validated for its partitioner.
+func CreateMoveTask(ctx context.Context, kubeClient client.Client, dc *cassdcapi.CassandraDatacenter, newTokens map[string]string, opts ...TaskOption) (*controlapi.CassandraTask, error) {
+	if err := ValidateSingleToken(dc); err != nil {
+		return nil, err
+	}
+
+	args, err := moveArguments(Partitioner(dc), newTokens)
+	if err != nil {
+		return nil, err
+	}
+
+	return CreateTask(ctx, kubeClient, controlapi.CommandMove, dc, args, opts...)
+}
+
+func moveArguments(partitioner string, newTokens map[string]string) (*controlapi.JobArguments, error) {
+	if len(newTokens) == 0 {
+		return nil, fmt.Errorf("newTokens must be specified")
+	}
+
+	for pod, token := range newTokens {
+		if pod == "" {
+			return nil, fmt.Errorf("podName must be specified")
+		}
+
+		if err := ValidateToken(partitioner, token); err != nil {
+			return nil, fmt.Errorf("pod %s: %w", pod, err)
+		}
+	}
+
+	return &controlapi.JobArguments{NewTokens: newTokens}, nil
+}
+
+// CreateClusterMoveTask creates a K8ssandraTask moving the nodes of datacenter dcName. The partitioner is used to
+// validate the tokens, empty means the default Murmur3Partitioner.
+func CreateClusterMoveTask(ctx context.Context, kubeClient client.Client, namespace, cluster, dcName, partitioner string, newTokens map[string]string, opts ...TaskOption) (*k8ssandrataskapi.K8ssandraTask, error) {
+	if dcName == "" {
+		return nil, fmt.Errorf("dcName must be specified")
+	}
+
+	args, err := moveArguments(partitioner, newTokens)
+	if err != nil {
+		return nil, err
+	}
+
+	return CreateClusterTask(ctx, kubeClient, controlapi.CommandMove, namespace, cluster, []string{dcName}, args, opts...)
+}
+
 // GarbageCollect
 
-func CreateGCTask(ctx context.Context, kubeClient client.Client, dc *cassdcapi.CassandraDatacenter, rackName string, podName string) (*controlapi.CassandraTask, error) {
+func CreateGCTask(ctx context.Context, kubeClient client.Client, dc *cassdcapi.CassandraDatacenter, rackName string, podName string, opts ...TaskOption) (*controlapi.CassandraTask, error) {
 	args := commonArguments(rackName, podName)
-	return CreateTask(ctx, kubeClient, controlapi.CommandGarbageCollect, dc, args)
+	return CreateTask(ctx, kubeClient, controlapi.CommandGarbageCollect, dc, args, opts...)
 }
 
-func CreateClusterGCTask(ctx context.Context, kubeClient client.Client, namespace, cluster, dcName, rackName, podName string) (*k8ssandrataskapi.K8ssandraTask, error) {
+func CreateClusterGCTask(ctx context.Context, kubeClient client.Client, namespace, cluster, dcName, rackName, podName string, opts ...TaskOption) (*k8ssandrataskapi.K8ssandraTask, error) {
 	args := commonArguments(rackName, podName)
-	return CreateClusterTask(ctx, kubeClient, controlapi.CommandGarbageCollect, namespace, cluster, []string{dcName}, args)
+	return CreateClusterTask(ctx, kubeClient, controlapi.CommandGarbageCollect, namespace, cluster, []string{dcName}, args, opts...)
 }
 
 // Rebuild
 
-func CreateRebuildTask(ctx context.Context, kubeClient client.Client, dc *cassdcapi.CassandraDatacenter, rackName string, podName, sourceDatacenter string) (*controlapi.CassandraTask, error) {
+func CreateRebuildTask(ctx context.Context, kubeClient client.Client, dc *cassdcapi.CassandraDatacenter, rackName string, podName, sourceDatacenter string, opts ...TaskOption) (*controlapi.CassandraTask, error) {
 	args, err := rebuildArguments(rackName, podName, sourceDatacenter)
 	if err != nil {
 		return nil, err
 	}
-	return CreateTask(ctx, kubeClient, controlapi.CommandRebuild, dc, args)
+	return CreateTask(ctx, kubeClient, controlapi.CommandRebuild, dc, args, opts...)
 }
 
 func rebuildArguments(rackName, podName, sourceDatacenter string) (*controlapi.JobArguments, error) {
@@ -174,12 +239,12 @@ func rebuildArguments(rackName, podName, sourceDatacenter string) (*controlapi.J
 	return args, nil
 }
 
-func CreateClusterRebuildTask(ctx context.Context, kubeClient client.Client, namespace, cluster, dcName, rackName, podName, sourceDatacenter string) (*k8ssandrataskapi.K8ssandraTask, error) {
+func CreateClusterRebuildTask(ctx context.Context, kubeClient client.Client, namespace, cluster, dcName, rackName, podName, sourceDatacenter string, opts ...TaskOption) (*k8ssandrataskapi.K8ssandraTask, error) {
 	args, err := rebuildArguments(rackName, podName, sourceDatacenter)
 	if err != nil {
 		return nil, err
 	}
-	return CreateClusterTask(ctx, kubeClient, controlapi.CommandRebuild, namespace, cluster, []string{dcName}, args)
+	return CreateClusterTask(ctx, kubeClient, controlapi.CommandRebuild, namespace, cluster, []string{dcName}, args, opts...)
 }
 
 // Assistance methods
@@ -191,10 +256,52 @@ func commonArguments(rackName, podName string) *controlapi.JobArguments {
 	}
 }
 
-func CreateTask(ctx context.Context, kubeClient client.Client, command controlapi.CassandraCommand, dc *cassdcapi.CassandraDatacenter, args *controlapi.JobArguments) (*controlapi.CassandraTask, error) {
+func CreateTask(ctx context.Context, kubeClient client.Client, command controlapi.CassandraCommand, dc *cassdcapi.CassandraDatacenter, args *controlapi.JobArguments, opts ...TaskOption) (*controlapi.CassandraTask, error) {
+	job := controlapi.CassandraJob{
+		Name:    fmt.Sprintf("%s-%s", dc.Name, string(command)),
+		Command: command,
+	}
+	if args != nil {
+		job.Arguments = *args
+	}
+
+	o, err := newTaskOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	task := newTask(dc, string(command), []controlapi.CassandraJob{job}, o)
+	if err := o.create(ctx, kubeClient, task); err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+// CreateJobsTask creates a single CassandraTask which runs the given jobs in order
+func CreateJobsTask(ctx context.Context, kubeClient client.Client, dc *cassdcapi.CassandraDatacenter, jobs []controlapi.CassandraJob, opts ...TaskOption) (*controlapi.CassandraTask, error) {
+	jobs, err := prepareJobs(dc.Name, jobs)
+	if err != nil {
+		return nil, err
+	}
+
+	o, err := newTaskOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	task := newTask(dc, pipelineNamePart(jobs), jobs, o)
+	if err := o.create(ctx, kubeClient, task); err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+func newTask(dc *cassdcapi.CassandraDatacenter, namePart string, jobs []controlapi.CassandraJob, o *taskOptions) *controlapi.CassandraTask {
 	task := &controlapi.CassandraTask{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      createName(dc.Name, string(command)),
+			Name:      createName(dc.Name, namePart),
 			Namespace: dc.Namespace,
 		},
 		Spec: controlapi.CassandraTaskSpec{
@@ -203,22 +310,12 @@ func CreateTask(ctx context.Context, kubeClient client.Client, command controlap
 				Namespace: dc.Namespace,
 			},
 			CassandraTaskTemplate: controlapi.CassandraTaskTemplate{
-				Jobs: []controlapi.CassandraJob{
-					{
-						Name:    fmt.Sprintf("%s-%s", dc.Name, string(command)),
-						Command: command,
-					},
-				},
+				Jobs: jobs,
 			},
 		},
 	}
-	if args != nil {
-		task.Spec.Jobs[0].Arguments = *args
-	}
 
-	if err := kubeClient.Create(ctx, task); err != nil {
-		return nil, err
-	}
+	o.applyTemplate(&task.Spec.CassandraTaskTemplate)
 
-	return task, nil
+	return task
 }
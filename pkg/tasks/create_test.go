@@ -2,12 +2,16 @@ package tasks_test
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
+	"time"
 
 	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
 	controlapi "github.com/k8ssandra/cass-operator/apis/control/v1alpha1"
 	k8ssandrataskapi "github.com/k8ssandra/k8ssandra-operator/apis/control/v1alpha1"
 	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
 
 	"github.com/k8ssandra/k8ssandra-client/pkg/tasks"
 )
@@ -380,3 +384,161 @@ func TestCreateClusterWideTask(t *testing.T) {
 	assert.Equal(t, controlapi.CommandRestart, task.Spec.Template.Jobs[0].Command)
 	assert.Equal(t, 0, len(task.Spec.Datacenters))
 }
+
+func TestCreateJobsTask(t *testing.T) {
+	namespace := env.CreateNamespace(t)
+	kubeClient := env.GetClientInNamespace(namespace)
+
+	dc := &cassdcapi.CassandraDatacenter{}
+	dc.Name = "test-dc"
+	args := controlapi.JobArguments{KeyspaceName: "ks1"}
+	jobs := []controlapi.CassandraJob{
+		{Command: controlapi.CommandFlush, Arguments: args},
+		{Command: controlapi.CommandCleanup, Arguments: args},
+		{Command: controlapi.CommandCompaction, Arguments: args},
+	}
+
+	task, err := tasks.CreateJobsTask(context.Background(), kubeClient, dc, jobs)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, task)
+	assert.Equal(t, 3, len(task.Spec.Jobs))
+	assert.Equal(t, controlapi.CommandFlush, task.Spec.Jobs[0].Command)
+	assert.Equal(t, controlapi.CommandCleanup, task.Spec.Jobs[1].Command)
+	assert.Equal(t, controlapi.CommandCompaction, task.Spec.Jobs[2].Command)
+	assert.Equal(t, "test-dc-flush-0", task.Spec.Jobs[0].Name)
+
+	// Jobs are validated like the single job tasks
+	_, err = tasks.CreateJobsTask(context.Background(), kubeClient, dc, []controlapi.CassandraJob{{Command: controlapi.CommandRebuild}})
+	assert.Error(t, err)
+
+	_, err = tasks.CreateJobsTask(context.Background(), kubeClient, dc, nil)
+	assert.Error(t, err)
+}
+
+func TestCreateClusterJobsTask(t *testing.T) {
+	namespace := env.CreateNamespace(t)
+	kubeClient := env.GetClientInNamespace(namespace)
+
+	cluster := "test-cluster"
+	jobs := []controlapi.CassandraJob{
+		{Command: controlapi.CommandFlush},
+		{Command: controlapi.CommandCleanup},
+	}
+
+	task, err := tasks.CreateClusterJobsTask(context.Background(), kubeClient, namespace, cluster, []string{"dc1"}, jobs)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, task)
+	assert.Equal(t, 2, len(task.Spec.Template.Jobs))
+	assert.Equal(t, controlapi.CommandCleanup, task.Spec.Template.Jobs[1].Command)
+	assert.Equal(t, []string{"dc1"}, task.Spec.Datacenters)
+
+	_, err = tasks.CreateClusterJobsTask(context.Background(), kubeClient, namespace, cluster, nil, []controlapi.CassandraJob{{Command: "unknown"}})
+	assert.Error(t, err)
+}
+
+func TestCreateTaskWithOptions(t *testing.T) {
+	namespace := env.CreateNamespace(t)
+	kubeClient := env.GetClientInNamespace(namespace)
+
+	dc := &cassdcapi.CassandraDatacenter{}
+	dc.Name = "test-dc"
+	dc.Namespace = namespace
+	scheduledTime := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	task, err := tasks.CreateCleanupTask(context.Background(), kubeClient, dc, "", "",
+		tasks.WithScheduledTime(scheduledTime),
+		tasks.WithTTLSecondsAfterFinished(0),
+		tasks.WithConcurrencyPolicy(batchv1.ForbidConcurrent),
+		tasks.WithRestartPolicy(corev1.RestartPolicyOnFailure),
+	)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, task)
+	assert.True(t, scheduledTime.Equal(task.Spec.ScheduledTime.Time))
+	assert.Equal(t, int32(0), *task.Spec.TTLSecondsAfterFinished)
+	assert.Equal(t, batchv1.ForbidConcurrent, task.Spec.ConcurrencyPolicy)
+	assert.Equal(t, corev1.RestartPolicyOnFailure, task.Spec.RestartPolicy)
+
+	_, err = tasks.CreateCleanupTask(context.Background(), kubeClient, dc, "", "", tasks.WithRestartPolicy(corev1.RestartPolicyAlways))
+	assert.Error(t, err)
+
+	_, err = tasks.CreateCleanupTask(context.Background(), kubeClient, dc, "", "", tasks.WithConcurrencyPolicy(batchv1.ReplaceConcurrent))
+	assert.Error(t, err)
+}
+
+func TestCreateClusterTaskWithOptions(t *testing.T) {
+	namespace := env.CreateNamespace(t)
+	kubeClient := env.GetClientInNamespace(namespace)
+
+	task, err := tasks.CreateClusterGCTask(context.Background(), kubeClient, namespace, "test-cluster", "", "", "",
+		tasks.WithDcConcurrencyPolicy(batchv1.ForbidConcurrent),
+		tasks.WithConcurrencyPolicy(batchv1.AllowConcurrent),
+	)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, task)
+	assert.Equal(t, batchv1.ForbidConcurrent, task.Spec.DcConcurrencyPolicy)
+	assert.Equal(t, batchv1.AllowConcurrent, task.Spec.Template.ConcurrencyPolicy)
+}
+
+func TestCreateMoveTask(t *testing.T) {
+	namespace := env.CreateNamespace(t)
+	kubeClient := env.GetClientInNamespace(namespace)
+
+	dc := &cassdcapi.CassandraDatacenter{}
+	dc.Name = "test-dc"
+	dc.Namespace = namespace
+
+	// Nodes with vnodes can not be moved
+	dc.Spec.Config = json.RawMessage(`{"cassandra-yaml": {"num_tokens": 16}}`)
+	_, err := tasks.CreateMoveTask(context.Background(), kubeClient, dc, map[string]string{"pod-0": "3074457345618258602"})
+	assert.Error(t, err)
+
+	dc.Spec.Config = json.RawMessage(`{"cassandra-yaml": {"num_tokens": 1}}`)
+	task, err := tasks.CreateMoveTask(context.Background(), kubeClient, dc, map[string]string{"pod-0": "3074457345618258602"})
+	assert.NoError(t, err)
+	assert.NotNil(t, task)
+	assert.Equal(t, controlapi.CommandMove, task.Spec.Jobs[0].Command)
+	assert.Equal(t, map[string]string{"pod-0": "3074457345618258602"}, task.Spec.Jobs[0].Arguments.NewTokens)
+
+	_, err = tasks.CreateMoveTask(context.Background(), kubeClient, dc, map[string]string{"pod-0": "not-a-token"})
+	assert.Error(t, err)
+
+	_, err = tasks.CreateMoveTask(context.Background(), kubeClient, dc, nil)
+	assert.Error(t, err)
+
+	clusterTask, err := tasks.CreateClusterMoveTask(context.Background(), kubeClient, namespace, "test-cluster", "test-dc", tasks.PartitionerRandom, map[string]string{"pod-0": "42"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"test-dc"}, clusterTask.Spec.Datacenters)
+
+	_, err = tasks.CreateClusterMoveTask(context.Background(), kubeClient, namespace, "test-cluster", "", "", map[string]string{"pod-0": "42"})
+	assert.Error(t, err)
+}
+
+func TestCreateClusterDatacentersRestartTask(t *testing.T) {
+	namespace := env.CreateNamespace(t)
+	kubeClient := env.GetClientInNamespace(namespace)
+
+	task, err := tasks.CreateClusterDatacentersRestartTask(context.Background(), kubeClient, namespace, "test-cluster", []string{"dc2", "dc1"}, "r1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"dc2", "dc1"}, operatorRunOrder(task.Spec.Datacenters))
+	assert.Equal(t, batchv1.ForbidConcurrent, task.Spec.DcConcurrencyPolicy)
+	assert.Equal(t, controlapi.CommandRestart, task.Spec.Template.Jobs[0].Command)
+	assert.Equal(t, "r1", task.Spec.Template.Jobs[0].Arguments.RackName)
+
+	task, err = tasks.CreateClusterDatacentersRestartTask(context.Background(), kubeClient, namespace, "test-cluster", nil, "")
+	assert.NoError(t, err)
+	assert.Empty(t, task.Spec.Datacenters)
+}
+
+// operatorRunOrder returns the order k8ssandra-operator runs the datacenters of a K8ssandraTask in, its filterDcs
+// prepends each datacenter of the spec to the list
+func operatorRunOrder(datacenters []string) []string {
+	order := []string{}
+	for _, dc := range datacenters {
+		order = append([]string{dc}, order...)
+	}
+	return order
+}
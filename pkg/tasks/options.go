@@ -0,0 +1,125 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	controlapi "github.com/k8ssandra/cass-operator/apis/control/v1alpha1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type taskOptions struct {
+	scheduledTime       *metav1.Time
+	ttlSeconds          *int32
+	concurrencyPolicy   batchv1.ConcurrencyPolicy
+	restartPolicy       corev1.RestartPolicy
+	dcConcurrencyPolicy batchv1.ConcurrencyPolicy
+	dryRun              cmdutil.DryRunStrategy
+}
+
+// TaskOption modifies the CassandraTask or K8ssandraTask before it is created
+type TaskOption func(*taskOptions)
+
+// WithScheduledTime sets the earliest time the task is executed
+func WithScheduledTime(scheduledTime time.Time) TaskOption {
+	return func(o *taskOptions) {
+		o.scheduledTime = &metav1.Time{Time: scheduledTime}
+	}
+}
+
+// WithTTLSecondsAfterFinished sets how long the task is kept after it has finished. 0 keeps the task forever.
+func WithTTLSecondsAfterFinished(seconds int32) TaskOption {
+	return func(o *taskOptions) {
+		o.ttlSeconds = &seconds
+	}
+}
+
+// WithConcurrencyPolicy sets whether the task can run at the same time with other tasks in the datacenter
+func WithConcurrencyPolicy(policy batchv1.ConcurrencyPolicy) TaskOption {
+	return func(o *taskOptions) {
+		o.concurrencyPolicy = policy
+	}
+}
+
+// WithRestartPolicy sets the behavior of the task in case of a failure
+func WithRestartPolicy(policy corev1.RestartPolicy) TaskOption {
+	return func(o *taskOptions) {
+		o.restartPolicy = policy
+	}
+}
+
+// WithDcConcurrencyPolicy sets whether a K8ssandraTask runs in the datacenters in parallel or one datacenter at a time.
+// It has no effect on CassandraTasks.
+func WithDcConcurrencyPolicy(policy batchv1.ConcurrencyPolicy) TaskOption {
+	return func(o *taskOptions) {
+		o.dcConcurrencyPolicy = policy
+	}
+}
+
+// WithDryRun renders the task without persisting it. With DryRunClient the task is not sent to the server at all,
+// with DryRunServer the server validates it.
+func WithDryRun(strategy cmdutil.DryRunStrategy) TaskOption {
+	return func(o *taskOptions) {
+		o.dryRun = strategy
+	}
+}
+
+func newTaskOptions(opts []TaskOption) (*taskOptions, error) {
+	o := &taskOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if err := validateConcurrencyPolicy(o.concurrencyPolicy); err != nil {
+		return nil, err
+	}
+
+	if err := validateConcurrencyPolicy(o.dcConcurrencyPolicy); err != nil {
+		return nil, err
+	}
+
+	switch o.restartPolicy {
+	case "", corev1.RestartPolicyNever, corev1.RestartPolicyOnFailure:
+	default:
+		return nil, fmt.Errorf("unsupported restartPolicy %s, only %s and %s are allowed", o.restartPolicy, corev1.RestartPolicyNever, corev1.RestartPolicyOnFailure)
+	}
+
+	if o.ttlSeconds != nil && *o.ttlSeconds < 0 {
+		return nil, fmt.Errorf("ttlSecondsAfterFinished can not be negative")
+	}
+
+	return o, nil
+}
+
+func validateConcurrencyPolicy(policy batchv1.ConcurrencyPolicy) error {
+	switch policy {
+	case "", batchv1.AllowConcurrent, batchv1.ForbidConcurrent:
+		return nil
+	}
+
+	return fmt.Errorf("unsupported concurrencyPolicy %s, only %s and %s are allowed", policy, batchv1.AllowConcurrent, batchv1.ForbidConcurrent)
+}
+
+func (o *taskOptions) applyTemplate(template *controlapi.CassandraTaskTemplate) {
+	template.ScheduledTime = o.scheduledTime
+	template.TTLSecondsAfterFinished = o.ttlSeconds
+	template.ConcurrencyPolicy = o.concurrencyPolicy
+	template.RestartPolicy = o.restartPolicy
+}
+
+// create sends the task to the server, unless a client dry run was requested
+func (o *taskOptions) create(ctx context.Context, kubeClient client.Client, task client.Object) error {
+	switch o.dryRun {
+	case cmdutil.DryRunClient:
+		return nil
+	case cmdutil.DryRunServer:
+		return kubeClient.Create(ctx, task, client.DryRunAll)
+	}
+
+	return kubeClient.Create(ctx, task)
+}
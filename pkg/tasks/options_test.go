@@ -0,0 +1,44 @@
+package tasks_test
+
+import (
+	"context"
+	"testing"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/k8ssandra/k8ssandra-client/pkg/tasks"
+)
+
+func TestCreateTaskDryRun(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	kubeClient := fake.NewClientBuilder().WithScheme(taskScheme(t)).Build()
+	dc := &cassdcapi.CassandraDatacenter{ObjectMeta: metav1.ObjectMeta{Name: "dc1", Namespace: "ns1"}}
+
+	for _, dryRun := range []cmdutil.DryRunStrategy{cmdutil.DryRunClient, cmdutil.DryRunServer} {
+		task, err := tasks.CreateFlushTask(ctx, kubeClient, dc, "r1", "", tasks.WithDryRun(dryRun))
+		require.NoError(err)
+		require.Equal("dc1", task.Spec.Datacenter.Name)
+		require.Equal("r1", task.Spec.Jobs[0].Arguments.RackName)
+
+		clusterTask, err := tasks.CreateClusterFlushTask(ctx, kubeClient, "ns1", "demo", "dc1", "", "", tasks.WithDryRun(dryRun))
+		require.NoError(err)
+		require.Equal("demo", clusterTask.Spec.Cluster.Name)
+	}
+
+	summaries, err := tasks.ListTasks(ctx, kubeClient, "ns1")
+	require.NoError(err)
+	require.Empty(summaries)
+
+	_, err = tasks.CreateFlushTask(ctx, kubeClient, dc, "", "", tasks.WithDryRun(cmdutil.DryRunNone))
+	require.NoError(err)
+
+	summaries, err = tasks.ListTasks(ctx, kubeClient, "ns1")
+	require.NoError(err)
+	require.Len(summaries, 1)
+}
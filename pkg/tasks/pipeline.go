@@ -0,0 +1,131 @@
+package tasks
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	controlapi "github.com/k8ssandra/cass-operator/apis/control/v1alpha1"
+	"sigs.k8s.io/yaml"
+)
+
+// Pipeline describes an ordered list of jobs that are run by a single CassandraTask or K8ssandraTask
+type Pipeline struct {
+	// Datacenter is the target CassandraDatacenter, or the target datacenter of the K8ssandraCluster if Cluster is set
+	Datacenter string `json:"datacenter,omitempty"`
+
+	// Cluster is the target K8ssandraCluster
+	Cluster string `json:"cluster,omitempty"`
+
+	// Jobs are run in the given order
+	Jobs []controlapi.CassandraJob `json:"jobs"`
+}
+
+// ReadPipeline parses the Pipeline from a YAML or JSON file
+func ReadPipeline(path string) (*Pipeline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParsePipeline(data)
+}
+
+// ParsePipeline parses the Pipeline from YAML or JSON input, unknown fields are rejected
+func ParsePipeline(data []byte) (*Pipeline, error) {
+	pipeline := &Pipeline{}
+	if err := yaml.UnmarshalStrict(data, pipeline); err != nil {
+		return nil, err
+	}
+
+	if _, err := prepareJobs("", pipeline.Jobs); err != nil {
+		return nil, err
+	}
+
+	return pipeline, nil
+}
+
+// NewPipeline creates a Pipeline where every command shares the same arguments
+func NewPipeline(commands []controlapi.CassandraCommand, args controlapi.JobArguments) *Pipeline {
+	jobs := make([]controlapi.CassandraJob, 0, len(commands))
+	for _, command := range commands {
+		jobs = append(jobs, controlapi.CassandraJob{
+			Command:   command,
+			Arguments: args,
+		})
+	}
+
+	return &Pipeline{Jobs: jobs}
+}
+
+// prepareJobs validates the jobs and names the ones without a name
+func prepareJobs(prefix string, jobs []controlapi.CassandraJob) ([]controlapi.CassandraJob, error) {
+	if len(jobs) == 0 {
+		return nil, fmt.Errorf("at least one job must be specified")
+	}
+
+	prepared := make([]controlapi.CassandraJob, 0, len(jobs))
+	names := make(map[string]struct{}, len(jobs))
+
+	for i, job := range jobs {
+		if err := validateJob(job); err != nil {
+			return nil, fmt.Errorf("job %d: %w", i, err)
+		}
+
+		if job.Name == "" {
+			job.Name = fmt.Sprintf("%s-%s-%d", prefix, job.Command, i)
+		}
+
+		if _, found := names[job.Name]; found {
+			return nil, fmt.Errorf("job %d: duplicate job name %s", i, job.Name)
+		}
+		names[job.Name] = struct{}{}
+
+		prepared = append(prepared, job)
+	}
+
+	return prepared, nil
+}
+
+func validateJob(job controlapi.CassandraJob) error {
+	switch job.Command {
+	case controlapi.CommandReplaceNode:
+		if job.Arguments.PodName == "" {
+			return fmt.Errorf("podName must be specified")
+		}
+	case controlapi.CommandRebuild:
+		if job.Arguments.SourceDatacenter == "" {
+			return fmt.Errorf("sourceDatacenter must be specified")
+		}
+	case controlapi.CommandMove:
+		if len(job.Arguments.NewTokens) == 0 {
+			return fmt.Errorf("new_tokens must be specified")
+		}
+	case controlapi.CommandCleanup,
+		controlapi.CommandRestart,
+		controlapi.CommandUpgradeSSTables,
+		controlapi.CommandCompaction,
+		controlapi.CommandScrub,
+		controlapi.CommandGarbageCollect,
+		controlapi.CommandFlush,
+		controlapi.CommandRefresh,
+		controlapi.CommandTSReload:
+	default:
+		return fmt.Errorf("unknown command %q", job.Command)
+	}
+
+	if job.Arguments.KeyspaceName == "" && len(job.Arguments.Tables) > 0 {
+		return fmt.Errorf("keyspace must be specified when tables are specified")
+	}
+
+	return nil
+}
+
+func pipelineNamePart(jobs []controlapi.CassandraJob) string {
+	commands := make([]string, 0, len(jobs))
+	for _, job := range jobs {
+		commands = append(commands, string(job.Command))
+	}
+
+	return strings.Join(commands, "-")
+}
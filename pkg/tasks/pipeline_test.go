@@ -0,0 +1,64 @@
+package tasks_test
+
+import (
+	"testing"
+
+	controlapi "github.com/k8ssandra/cass-operator/apis/control/v1alpha1"
+	"github.com/stretchr/testify/require"
+
+	"github.com/k8ssandra/k8ssandra-client/pkg/tasks"
+)
+
+func TestParsePipeline(t *testing.T) {
+	require := require.New(t)
+
+	input := `
+datacenter: dc1
+jobs:
+  - command: flush
+    args:
+      keyspace_name: ks1
+  - command: cleanup
+    args:
+      keyspace_name: ks1
+  - name: compact-ks1
+    command: compact
+    args:
+      keyspace_name: ks1
+      tables:
+        - t1
+`
+	pipeline, err := tasks.ParsePipeline([]byte(input))
+	require.NoError(err)
+	require.Equal("dc1", pipeline.Datacenter)
+	require.Len(pipeline.Jobs, 3)
+	require.Equal(controlapi.CommandFlush, pipeline.Jobs[0].Command)
+	require.Equal("ks1", pipeline.Jobs[1].Arguments.KeyspaceName)
+	require.Equal("compact-ks1", pipeline.Jobs[2].Name)
+	require.Equal([]string{"t1"}, pipeline.Jobs[2].Arguments.Tables)
+
+	// Unknown fields are rejected
+	_, err = tasks.ParsePipeline([]byte("datacenter: dc1\nsteps:\n  - command: flush\n"))
+	require.Error(err)
+
+	// Unknown commands are rejected
+	_, err = tasks.ParsePipeline([]byte("datacenter: dc1\njobs:\n  - command: repair\n"))
+	require.Error(err)
+
+	// Tables require a keyspace
+	_, err = tasks.ParsePipeline([]byte("datacenter: dc1\njobs:\n  - command: flush\n    args:\n      tables: [t1]\n"))
+	require.Error(err)
+
+	// Job names must be unique
+	_, err = tasks.ParsePipeline([]byte("datacenter: dc1\njobs:\n  - name: a\n    command: flush\n  - name: a\n    command: cleanup\n"))
+	require.Error(err)
+}
+
+func TestNewPipeline(t *testing.T) {
+	require := require.New(t)
+
+	pipeline := tasks.NewPipeline([]controlapi.CassandraCommand{controlapi.CommandFlush, controlapi.CommandCleanup}, controlapi.JobArguments{KeyspaceName: "ks1"})
+	require.Len(pipeline.Jobs, 2)
+	require.Equal(controlapi.CommandCleanup, pipeline.Jobs[1].Command)
+	require.Equal("ks1", pipeline.Jobs[1].Arguments.KeyspaceName)
+}
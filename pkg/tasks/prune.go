@@ -0,0 +1,132 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	controlapi "github.com/k8ssandra/cass-operator/apis/control/v1alpha1"
+	k8ssandrataskapi "github.com/k8ssandra/k8ssandra-operator/apis/control/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// PruneOptions selects the completed tasks PruneTasks removes
+type PruneOptions struct {
+	// OlderThan prunes only tasks that completed at least this long ago, 0 prunes regardless of age
+	OlderThan time.Duration
+
+	// KeepLast is the number of most recently completed tasks kept in each namespace
+	KeepLast int
+
+	// OnlySucceeded keeps the failed tasks
+	OnlySucceeded bool
+
+	// DryRun with DryRunClient returns the tasks which would be pruned without deleting them, with DryRunServer the
+	// deletes are only validated by the server
+	DryRun cmdutil.DryRunStrategy
+}
+
+// PruneTasks deletes the completed CassandraTasks and K8ssandraTasks of the namespace, or of every namespace if namespace
+// is empty, that match the options. Tasks that have not completed are never pruned. It returns the pruned tasks.
+func PruneTasks(ctx context.Context, kubeClient client.Client, namespace string, opts PruneOptions) ([]*Summary, error) {
+	if opts.OlderThan < 0 || opts.KeepLast < 0 {
+		return nil, fmt.Errorf("olderThan and keepLast can not be negative")
+	}
+
+	summaries, err := ListTasks(ctx, kubeClient, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := pruneCandidates(summaries, opts, time.Now())
+
+	if opts.DryRun == cmdutil.DryRunClient {
+		return candidates, nil
+	}
+
+	return DeleteTasks(ctx, kubeClient, candidates, opts.DryRun)
+}
+
+// DeleteTasks deletes the tasks, skipping the ones already gone. It returns the deleted tasks.
+func DeleteTasks(ctx context.Context, kubeClient client.Client, summaries []*Summary, dryRun cmdutil.DryRunStrategy) ([]*Summary, error) {
+	if dryRun == cmdutil.DryRunClient {
+		return summaries, nil
+	}
+
+	deleted := make([]*Summary, 0, len(summaries))
+	for _, s := range summaries {
+		if err := deleteTask(ctx, kubeClient, s, dryRun); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return deleted, err
+		}
+		deleted = append(deleted, s)
+	}
+
+	return deleted, nil
+}
+
+func pruneCandidates(summaries []*Summary, opts PruneOptions, now time.Time) []*Summary {
+	completed := make(map[string][]*Summary)
+	for _, s := range summaries {
+		if s.Completed() {
+			completed[s.Namespace] = append(completed[s.Namespace], s)
+		}
+	}
+
+	namespaces := make([]string, 0, len(completed))
+	for ns := range completed {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+
+	candidates := make([]*Summary, 0)
+	for _, ns := range namespaces {
+		tasks := completed[ns]
+
+		// Newest first, the first KeepLast are kept
+		sort.SliceStable(tasks, func(i, j int) bool {
+			return tasks[j].Status.CompletionTime.Before(tasks[i].Status.CompletionTime)
+		})
+
+		for i, s := range tasks {
+			if i < opts.KeepLast {
+				continue
+			}
+
+			if opts.OnlySucceeded && s.Failed() {
+				continue
+			}
+
+			if opts.OlderThan > 0 && now.Sub(s.Status.CompletionTime.Time) < opts.OlderThan {
+				continue
+			}
+
+			candidates = append(candidates, s)
+		}
+	}
+
+	return candidates
+}
+
+func deleteTask(ctx context.Context, kubeClient client.Client, s *Summary, dryRun cmdutil.DryRunStrategy) error {
+	meta := metav1.ObjectMeta{Name: s.Name, Namespace: s.Namespace}
+
+	var obj client.Object = &controlapi.CassandraTask{ObjectMeta: meta}
+	if s.Kind == KindK8ssandraTask {
+		obj = &k8ssandrataskapi.K8ssandraTask{ObjectMeta: meta}
+	}
+
+	// The precondition prevents deleting a new task created with the same name
+	opts := []client.DeleteOption{client.Preconditions{UID: &s.UID}}
+	if dryRun == cmdutil.DryRunServer {
+		opts = append(opts, client.DryRunAll)
+	}
+
+	return kubeClient.Delete(ctx, obj, opts...)
+}
@@ -0,0 +1,103 @@
+package tasks_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	controlapi "github.com/k8ssandra/cass-operator/apis/control/v1alpha1"
+	k8ssandrataskapi "github.com/k8ssandra/k8ssandra-operator/apis/control/v1alpha1"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/k8ssandra/k8ssandra-client/pkg/tasks"
+)
+
+func completedTask(name string, age time.Duration, failed bool) *controlapi.CassandraTask {
+	task := &controlapi.CassandraTask{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns1", UID: types.UID("uid-" + name)},
+	}
+	completed := metav1.NewTime(time.Now().Add(-age))
+	task.Status.CompletionTime = &completed
+	if failed {
+		task.Status.Failed = 1
+	}
+	return task
+}
+
+func pruneNames(summaries []*tasks.Summary) []string {
+	names := make([]string, 0, len(summaries))
+	for _, s := range summaries {
+		names = append(names, s.Name)
+	}
+	return names
+}
+
+func TestPruneTasks(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	day := 24 * time.Hour
+
+	running := &controlapi.CassandraTask{ObjectMeta: metav1.ObjectMeta{Name: "running", Namespace: "ns1"}}
+	clusterTask := &k8ssandrataskapi.K8ssandraTask{ObjectMeta: metav1.ObjectMeta{Name: "cluster-old", Namespace: "ns1", UID: "uid-cluster-old"}}
+	completed := metav1.NewTime(time.Now().Add(-20 * day))
+	clusterTask.Status.CompletionTime = &completed
+
+	objs := []client.Object{
+		running,
+		clusterTask,
+		completedTask("new", time.Hour, false),
+		completedTask("week-old", 8*day, false),
+		completedTask("month-old", 30*day, false),
+		completedTask("failed-old", 10*day, true),
+	}
+	kubeClient := fake.NewClientBuilder().WithScheme(taskScheme(t)).WithObjects(objs...).Build()
+
+	pruned, err := tasks.PruneTasks(ctx, kubeClient, "ns1", tasks.PruneOptions{OlderThan: 7 * day, KeepLast: 1, OnlySucceeded: true, DryRun: cmdutil.DryRunClient})
+	require.NoError(err)
+	require.Equal([]string{"week-old", "cluster-old", "month-old"}, pruneNames(pruned))
+
+	remaining, err := tasks.ListTasks(ctx, kubeClient, "ns1")
+	require.NoError(err)
+	require.Len(remaining, 6)
+
+	pruned, err = tasks.PruneTasks(ctx, kubeClient, "ns1", tasks.PruneOptions{OlderThan: 7 * day, KeepLast: 2})
+	require.NoError(err)
+	require.Equal([]string{"failed-old", "cluster-old", "month-old"}, pruneNames(pruned))
+
+	remaining, err = tasks.ListTasks(ctx, kubeClient, "ns1")
+	require.NoError(err)
+	require.ElementsMatch([]string{"running", "new", "week-old"}, pruneNames(remaining))
+
+	// keep-last alone prunes regardless of age
+	pruned, err = tasks.PruneTasks(ctx, kubeClient, "ns1", tasks.PruneOptions{KeepLast: 1})
+	require.NoError(err)
+	require.Equal([]string{"week-old"}, pruneNames(pruned))
+
+	_, err = tasks.PruneTasks(ctx, kubeClient, "ns1", tasks.PruneOptions{KeepLast: -1})
+	require.Error(err)
+}
+
+func TestDeleteTasks(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	kubeClient := fake.NewClientBuilder().WithScheme(taskScheme(t)).WithObjects(completedTask("old", time.Hour, false)).Build()
+
+	candidates, err := tasks.PruneTasks(ctx, kubeClient, "ns1", tasks.PruneOptions{DryRun: cmdutil.DryRunClient})
+	require.NoError(err)
+	require.Equal([]string{"old"}, pruneNames(candidates))
+
+	deleted, err := tasks.DeleteTasks(ctx, kubeClient, candidates, cmdutil.DryRunNone)
+	require.NoError(err)
+	require.Equal([]string{"old"}, pruneNames(deleted))
+
+	// Tasks deleted in the meantime are skipped
+	deleted, err = tasks.DeleteTasks(ctx, kubeClient, candidates, cmdutil.DryRunNone)
+	require.NoError(err)
+	require.Empty(deleted)
+}
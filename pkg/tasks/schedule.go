@@ -0,0 +1,273 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	controlapi "github.com/k8ssandra/cass-operator/apis/control/v1alpha1"
+	k8ssandrataskapi "github.com/k8ssandra/k8ssandra-operator/apis/control/v1alpha1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// DefaultScheduleImage is the client image the scheduled CronJobs run unless another image is given
+	DefaultScheduleImage = "k8ssandra/k8ssandra-client:latest"
+
+	// ScheduleLabel marks the CronJobs created by CreateSchedule, the value is the name of the schedule
+	ScheduleLabel = "k8ssandra.io/task-schedule"
+
+	// scheduleServiceAccount is the name of the ServiceAccount, Role and RoleBinding shared by the schedules of a namespace
+	scheduleServiceAccount = "k8ssandra-client-tasks"
+	scheduleEntrypoint     = "/kubectl-k8ssandra"
+
+	managedByLabel      = "app.kubernetes.io/managed-by"
+	managedByLabelValue = "k8ssandra-client"
+)
+
+var errNotSchedule = fmt.Errorf("CronJob is not a task schedule")
+
+// Schedule describes a recurring task. Args are the arguments of the tasks command, such as
+// []string{"gc", "--dc", "dc1", "--rack", "r1"}.
+type Schedule struct {
+	Name      string
+	Namespace string
+	Cron      string
+	Image     string
+	Args      []string
+	Suspend   bool
+}
+
+// CreateSchedule creates a CronJob running the client image with the tasks command of the schedule. The ServiceAccount,
+// Role and RoleBinding used by the CronJob are created if they do not exist yet.
+func CreateSchedule(ctx context.Context, kubeClient client.Client, schedule *Schedule) (*batchv1.CronJob, error) {
+	if schedule.Cron == "" {
+		return nil, fmt.Errorf("schedule %s has no cron expression", schedule.Name)
+	}
+
+	if len(schedule.Args) == 0 {
+		return nil, fmt.Errorf("schedule %s has no tasks command", schedule.Name)
+	}
+
+	for _, obj := range scheduleRBAC(schedule.Namespace) {
+		if err := kubeClient.Create(ctx, obj); err != nil && !errors.IsAlreadyExists(err) {
+			return nil, err
+		}
+	}
+
+	cronJob := newScheduleCronJob(schedule)
+	if err := kubeClient.Create(ctx, cronJob); err != nil {
+		return nil, err
+	}
+
+	return cronJob, nil
+}
+
+func newScheduleCronJob(schedule *Schedule) *batchv1.CronJob {
+	image := schedule.Image
+	if image == "" {
+		image = DefaultScheduleImage
+	}
+
+	args := append([]string{"tasks"}, schedule.Args...)
+	args = append(args, "--namespace", schedule.Namespace)
+
+	return &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      schedule.Name,
+			Namespace: schedule.Namespace,
+			Labels:    scheduleLabels(schedule.Name),
+		},
+		Spec: batchv1.CronJobSpec{
+			Schedule:          schedule.Cron,
+			Suspend:           ptr.To(schedule.Suspend),
+			ConcurrencyPolicy: batchv1.ForbidConcurrent,
+			JobTemplate: batchv1.JobTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: scheduleLabels(schedule.Name),
+				},
+				Spec: batchv1.JobSpec{
+					BackoffLimit: ptr.To[int32](0),
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels: scheduleLabels(schedule.Name),
+						},
+						Spec: corev1.PodSpec{
+							ServiceAccountName: scheduleServiceAccount,
+							RestartPolicy:      corev1.RestartPolicyNever,
+							Containers: []corev1.Container{
+								{
+									Name:    "tasks",
+									Image:   image,
+									Command: []string{scheduleEntrypoint},
+									Args:    args,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func scheduleLabels(name string) map[string]string {
+	return map[string]string{
+		managedByLabel: managedByLabelValue,
+		ScheduleLabel:  name,
+	}
+}
+
+// scheduleRBAC returns the ServiceAccount and the minimal Role and RoleBinding needed to create, follow and prune tasks
+func scheduleRBAC(namespace string) []client.Object {
+	labels := map[string]string{managedByLabel: managedByLabelValue}
+	meta := metav1.ObjectMeta{Name: scheduleServiceAccount, Namespace: namespace, Labels: labels}
+
+	return []client.Object{
+		&corev1.ServiceAccount{ObjectMeta: meta},
+		&rbacv1.Role{
+			ObjectMeta: meta,
+			Rules: []rbacv1.PolicyRule{
+				{
+					APIGroups: []string{cassdcapi.GroupVersion.Group},
+					Resources: []string{"cassandradatacenters"},
+					Verbs:     []string{"get", "list"},
+				},
+				{
+					APIGroups: []string{controlapi.GroupVersion.Group},
+					Resources: []string{"cassandratasks"},
+					Verbs:     []string{"create", "get", "list", "watch", "delete"},
+				},
+				{
+					APIGroups: []string{k8ssandrataskapi.GroupVersion.Group},
+					Resources: []string{"k8ssandratasks"},
+					Verbs:     []string{"create", "get", "list", "watch", "delete"},
+				},
+				{
+					APIGroups: []string{""},
+					Resources: []string{"pods"},
+					Verbs:     []string{"get", "list", "watch"},
+				},
+			},
+		},
+		&rbacv1.RoleBinding{
+			ObjectMeta: meta,
+			RoleRef: rbacv1.RoleRef{
+				APIGroup: rbacv1.GroupName,
+				Kind:     "Role",
+				Name:     scheduleServiceAccount,
+			},
+			Subjects: []rbacv1.Subject{
+				{
+					Kind:      rbacv1.ServiceAccountKind,
+					Name:      scheduleServiceAccount,
+					Namespace: namespace,
+				},
+			},
+		},
+	}
+}
+
+// ListSchedules returns the task schedules of the namespace, or of every namespace if namespace is empty
+func ListSchedules(ctx context.Context, kubeClient client.Client, namespace string) ([]batchv1.CronJob, error) {
+	opts := []client.ListOption{client.HasLabels{ScheduleLabel}}
+	if namespace != "" {
+		opts = append(opts, client.InNamespace(namespace))
+	}
+
+	cronJobs := &batchv1.CronJobList{}
+	if err := kubeClient.List(ctx, cronJobs, opts...); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(cronJobs.Items, func(i, j int) bool {
+		if cronJobs.Items[i].Namespace != cronJobs.Items[j].Namespace {
+			return cronJobs.Items[i].Namespace < cronJobs.Items[j].Namespace
+		}
+		return cronJobs.Items[i].Name < cronJobs.Items[j].Name
+	})
+
+	return cronJobs.Items, nil
+}
+
+// SuspendSchedule suspends or resumes the task schedule
+func SuspendSchedule(ctx context.Context, kubeClient client.Client, key types.NamespacedName, suspend bool) error {
+	cronJob, err := getSchedule(ctx, kubeClient, key)
+	if err != nil {
+		return err
+	}
+
+	patch := client.MergeFrom(cronJob.DeepCopy())
+	cronJob.Spec.Suspend = ptr.To(suspend)
+	return kubeClient.Patch(ctx, cronJob, patch)
+}
+
+// DeleteSchedule deletes the task schedule and its Jobs. The shared ServiceAccount, Role and RoleBinding are removed
+// with the last schedule of the namespace.
+func DeleteSchedule(ctx context.Context, kubeClient client.Client, key types.NamespacedName) error {
+	cronJob, err := getSchedule(ctx, kubeClient, key)
+	if err != nil {
+		return err
+	}
+
+	if err := kubeClient.Delete(ctx, cronJob, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil {
+		return err
+	}
+
+	remaining, err := ListSchedules(ctx, kubeClient, key.Namespace)
+	if err != nil {
+		return err
+	}
+
+	if len(remaining) > 0 {
+		return nil
+	}
+
+	for _, obj := range scheduleRBAC(key.Namespace) {
+		if err := kubeClient.Delete(ctx, obj); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func getSchedule(ctx context.Context, kubeClient client.Client, key types.NamespacedName) (*batchv1.CronJob, error) {
+	cronJob := &batchv1.CronJob{}
+	if err := kubeClient.Get(ctx, key, cronJob); err != nil {
+		return nil, err
+	}
+
+	if _, found := cronJob.Labels[ScheduleLabel]; !found {
+		return nil, fmt.Errorf("%w: %s", errNotSchedule, key.Name)
+	}
+
+	return cronJob, nil
+}
+
+// ScheduledArgs returns the tasks command arguments the CronJob of the schedule runs
+func ScheduledArgs(cronJob *batchv1.CronJob) []string {
+	containers := cronJob.Spec.JobTemplate.Spec.Template.Spec.Containers
+	if len(containers) == 0 {
+		return nil
+	}
+
+	args := containers[0].Args
+	if len(args) > 0 && args[0] == "tasks" {
+		args = args[1:]
+	}
+
+	if len(args) >= 2 && args[len(args)-2] == "--namespace" {
+		args = args[:len(args)-2]
+	}
+
+	return args
+}
@@ -0,0 +1,92 @@
+package tasks_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/k8ssandra/k8ssandra-client/pkg/tasks"
+)
+
+func TestSchedules(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	unrelated := &batchv1.CronJob{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "ns1"}}
+	kubeClient := fake.NewClientBuilder().WithScheme(taskScheme(t)).WithObjects(unrelated).Build()
+
+	cronJob, err := tasks.CreateSchedule(ctx, kubeClient, &tasks.Schedule{
+		Name:      "weekly-gc-r1",
+		Namespace: "ns1",
+		Cron:      "0 3 * * 0",
+		Args:      []string{"gc", "--dc", "dc1", "--rack", "r1"},
+	})
+	require.NoError(err)
+	require.Equal("0 3 * * 0", cronJob.Spec.Schedule)
+	require.Equal([]string{"gc", "--dc", "dc1", "--rack", "r1"}, tasks.ScheduledArgs(cronJob))
+
+	podSpec := cronJob.Spec.JobTemplate.Spec.Template.Spec
+	require.Equal(tasks.DefaultScheduleImage, podSpec.Containers[0].Image)
+	require.Equal([]string{"tasks", "gc", "--dc", "dc1", "--rack", "r1", "--namespace", "ns1"}, podSpec.Containers[0].Args)
+
+	sa := &corev1.ServiceAccount{}
+	require.NoError(kubeClient.Get(ctx, types.NamespacedName{Name: podSpec.ServiceAccountName, Namespace: "ns1"}, sa))
+	role := &rbacv1.Role{}
+	require.NoError(kubeClient.Get(ctx, types.NamespacedName{Name: podSpec.ServiceAccountName, Namespace: "ns1"}, role))
+	// Scheduled prune deletes tasks
+	for _, rule := range role.Rules[1:3] {
+		require.Contains(rule.Verbs, "delete")
+	}
+
+	_, err = tasks.CreateSchedule(ctx, kubeClient, &tasks.Schedule{
+		Name:      "nightly",
+		Namespace: "ns1",
+		Cron:      "0 1 * * *",
+		Image:     "k8ssandra/k8ssandra-client:v0.8.1",
+		Args:      []string{"cleanup", "--k8ssandra-cluster", "demo"},
+		Suspend:   true,
+	})
+	require.NoError(err)
+
+	schedules, err := tasks.ListSchedules(ctx, kubeClient, "ns1")
+	require.NoError(err)
+	require.Len(schedules, 2)
+	require.Equal("nightly", schedules[0].Name)
+	require.True(*schedules[0].Spec.Suspend)
+	require.Equal("weekly-gc-r1", schedules[1].Name)
+
+	key := types.NamespacedName{Name: "weekly-gc-r1", Namespace: "ns1"}
+	require.NoError(tasks.SuspendSchedule(ctx, kubeClient, key, true))
+	require.NoError(kubeClient.Get(ctx, key, cronJob))
+	require.True(*cronJob.Spec.Suspend)
+
+	require.Error(tasks.SuspendSchedule(ctx, kubeClient, types.NamespacedName{Name: "other", Namespace: "ns1"}, true))
+	require.Error(tasks.DeleteSchedule(ctx, kubeClient, types.NamespacedName{Name: "other", Namespace: "ns1"}))
+
+	require.NoError(tasks.DeleteSchedule(ctx, kubeClient, key))
+	require.NoError(kubeClient.Get(ctx, types.NamespacedName{Name: podSpec.ServiceAccountName, Namespace: "ns1"}, sa))
+
+	// The shared RBAC is removed with the last schedule
+	require.NoError(tasks.DeleteSchedule(ctx, kubeClient, types.NamespacedName{Name: "nightly", Namespace: "ns1"}))
+	err = kubeClient.Get(ctx, types.NamespacedName{Name: podSpec.ServiceAccountName, Namespace: "ns1"}, sa)
+	require.True(errors.IsNotFound(err))
+	require.NoError(kubeClient.Get(ctx, types.NamespacedName{Name: "other", Namespace: "ns1"}, unrelated))
+}
+
+func TestCreateScheduleValidation(t *testing.T) {
+	kubeClient := fake.NewClientBuilder().WithScheme(taskScheme(t)).Build()
+
+	_, err := tasks.CreateSchedule(context.Background(), kubeClient, &tasks.Schedule{Name: "a", Namespace: "ns1", Args: []string{"flush"}})
+	require.Error(t, err)
+
+	_, err = tasks.CreateSchedule(context.Background(), kubeClient, &tasks.Schedule{Name: "a", Namespace: "ns1", Cron: "@daily"})
+	require.Error(t, err)
+}
@@ -0,0 +1,259 @@
+package tasks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	controlapi "github.com/k8ssandra/cass-operator/apis/control/v1alpha1"
+	k8ssandrataskapi "github.com/k8ssandra/k8ssandra-operator/apis/control/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	KindCassandraTask = "CassandraTask"
+	KindK8ssandraTask = "K8ssandraTask"
+
+	// podJobAnnotationPrefix is the prefix cass-operator uses to track the job of a task in the pod annotations
+	podJobAnnotationPrefix = "control.k8ssandra.io/job"
+	podJobError            = "ERROR"
+)
+
+// Summary is a kind independent view of a CassandraTask or a K8ssandraTask
+type Summary struct {
+	Kind      string
+	Name      string
+	Namespace string
+	UID       types.UID
+	Created   metav1.Time
+
+	Commands []string
+
+	// Datacenters are the target datacenters, empty for a K8ssandraTask targeting every datacenter
+	Datacenters []string
+	Rack        string
+	Pod         string
+
+	Status controlapi.CassandraTaskStatus
+
+	// DatacenterStatus is only set for K8ssandraTasks
+	DatacenterStatus map[string]controlapi.CassandraTaskStatus
+
+	// Task is the CassandraTask or K8ssandraTask the summary was made of
+	Task client.Object
+}
+
+// PodJobStatus is the progress of a task on a single pod as tracked by cass-operator
+type PodJobStatus struct {
+	Pod     string `json:"-"`
+	Id      string `json:"id,omitempty"`
+	Status  string `json:"status,omitempty"`
+	Handler string `json:"handler,omitempty"`
+	Retries int    `json:"retries,omitempty"`
+}
+
+// SummaryFromTask creates a Summary of the CassandraTask
+func SummaryFromTask(task *controlapi.CassandraTask) *Summary {
+	s := &Summary{
+		Kind:      KindCassandraTask,
+		Name:      task.Name,
+		Namespace: task.Namespace,
+		UID:       task.UID,
+		Created:   task.CreationTimestamp,
+		Status:    task.Status,
+		Task:      task,
+	}
+
+	if task.Spec.Datacenter.Name != "" {
+		s.Datacenters = []string{task.Spec.Datacenter.Name}
+	}
+
+	s.setJobs(task.Spec.Jobs)
+	return s
+}
+
+// SummaryFromClusterTask creates a Summary of the K8ssandraTask
+func SummaryFromClusterTask(task *k8ssandrataskapi.K8ssandraTask) *Summary {
+	s := &Summary{
+		Kind:             KindK8ssandraTask,
+		Name:             task.Name,
+		Namespace:        task.Namespace,
+		UID:              task.UID,
+		Created:          task.CreationTimestamp,
+		Datacenters:      task.Spec.Datacenters,
+		Status:           task.Status.CassandraTaskStatus,
+		DatacenterStatus: task.Status.Datacenters,
+		Task:             task,
+	}
+
+	s.setJobs(task.Spec.Template.Jobs)
+	return s
+}
+
+func (s *Summary) setJobs(jobs []controlapi.CassandraJob) {
+	s.Commands = make([]string, 0, len(jobs))
+	for _, job := range jobs {
+		s.Commands = append(s.Commands, string(job.Command))
+	}
+
+	if len(jobs) > 0 {
+		s.Rack = jobs[0].Arguments.RackName
+		s.Pod = jobs[0].Arguments.PodName
+	}
+}
+
+// Completed returns true if the task has finished, successfully or not
+func (s *Summary) Completed() bool {
+	return s.Status.CompletionTime != nil
+}
+
+// Failed returns true if the task has a Failed condition set or any of its jobs have failed
+func (s *Summary) Failed() bool {
+	if meta.IsStatusConditionTrue(s.Status.Conditions, string(controlapi.JobFailed)) || s.Status.Failed > 0 {
+		return true
+	}
+
+	for _, status := range s.DatacenterStatus {
+		if meta.IsStatusConditionTrue(status.Conditions, string(controlapi.JobFailed)) || status.Failed > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ActiveConditions returns the types of the conditions that are currently true
+func (s *Summary) ActiveConditions() []string {
+	return activeConditions(s.Status.Conditions)
+}
+
+func activeConditions(conditions []metav1.Condition) []string {
+	active := make([]string, 0, len(conditions))
+	for _, cond := range conditions {
+		if cond.Status == metav1.ConditionTrue {
+			active = append(active, cond.Type)
+		}
+	}
+
+	return active
+}
+
+// ListTasks returns the CassandraTasks and K8ssandraTasks of the namespace, or of every namespace if namespace is empty.
+// K8ssandraTasks are skipped if their CRD is not installed.
+func ListTasks(ctx context.Context, kubeClient client.Client, namespace string) ([]*Summary, error) {
+	opts := []client.ListOption{}
+	if namespace != "" {
+		opts = append(opts, client.InNamespace(namespace))
+	}
+
+	cassTasks := &controlapi.CassandraTaskList{}
+	if err := kubeClient.List(ctx, cassTasks, opts...); err != nil {
+		return nil, err
+	}
+
+	summaries := make([]*Summary, 0, len(cassTasks.Items))
+	for i := range cassTasks.Items {
+		summaries = append(summaries, SummaryFromTask(&cassTasks.Items[i]))
+	}
+
+	clusterTasks := &k8ssandrataskapi.K8ssandraTaskList{}
+	if err := kubeClient.List(ctx, clusterTasks, opts...); err != nil {
+		if !meta.IsNoMatchError(err) {
+			return nil, err
+		}
+	}
+
+	for i := range clusterTasks.Items {
+		summaries = append(summaries, SummaryFromClusterTask(&clusterTasks.Items[i]))
+	}
+
+	sort.SliceStable(summaries, func(i, j int) bool {
+		if summaries[i].Namespace != summaries[j].Namespace {
+			return summaries[i].Namespace < summaries[j].Namespace
+		}
+		return summaries[i].Created.Before(&summaries[j].Created)
+	})
+
+	return summaries, nil
+}
+
+// GetTask fetches the CassandraTask with the given key or, if there is none, the K8ssandraTask
+func GetTask(ctx context.Context, kubeClient client.Client, key types.NamespacedName) (*Summary, error) {
+	task := &controlapi.CassandraTask{}
+	err := kubeClient.Get(ctx, key, task)
+	if err == nil {
+		return SummaryFromTask(task), nil
+	}
+
+	if !errors.IsNotFound(err) {
+		return nil, err
+	}
+
+	clusterTask := &k8ssandrataskapi.K8ssandraTask{}
+	if errCluster := kubeClient.Get(ctx, key, clusterTask); errCluster != nil {
+		if errors.IsNotFound(errCluster) || meta.IsNoMatchError(errCluster) {
+			return nil, err
+		}
+		return nil, errCluster
+	}
+
+	return SummaryFromClusterTask(clusterTask), nil
+}
+
+// PodJobStatuses returns the job progress of every pod in the target datacenter of the CassandraTask. cass-operator
+// removes the tracking once the task has completed, thus pods without any tracking have an empty Status.
+func PodJobStatuses(ctx context.Context, kubeClient client.Client, s *Summary) ([]PodJobStatus, error) {
+	if s.Kind != KindCassandraTask || len(s.Datacenters) == 0 {
+		return nil, nil
+	}
+
+	pods := &corev1.PodList{}
+	if err := kubeClient.List(ctx, pods, client.InNamespace(s.Namespace), client.MatchingLabels{cassdcapi.DatacenterLabel: s.Datacenters[0]}); err != nil {
+		return nil, err
+	}
+
+	annotationKey := fmt.Sprintf("%s-%s", podJobAnnotationPrefix, s.UID)
+	statuses := make([]PodJobStatus, 0, len(pods.Items))
+	for _, pod := range pods.Items {
+		status := PodJobStatus{}
+		if jobData, found := pod.Annotations[annotationKey]; found {
+			if err := json.Unmarshal([]byte(jobData), &status); err != nil {
+				return nil, err
+			}
+		}
+		status.Pod = pod.Name
+		statuses = append(statuses, status)
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Pod < statuses[j].Pod
+	})
+
+	return statuses, nil
+}
+
+// TargetString returns a short description of the datacenters, rack and pod the task targets
+func (s *Summary) TargetString() string {
+	target := "*"
+	if len(s.Datacenters) > 0 {
+		target = strings.Join(s.Datacenters, ",")
+	}
+
+	if s.Rack != "" {
+		target = fmt.Sprintf("%s/%s", target, s.Rack)
+	}
+
+	if s.Pod != "" {
+		target = fmt.Sprintf("%s/%s", target, s.Pod)
+	}
+
+	return target
+}
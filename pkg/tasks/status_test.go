@@ -0,0 +1,154 @@
+package tasks_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	controlapi "github.com/k8ssandra/cass-operator/apis/control/v1alpha1"
+	k8ssandrataskapi "github.com/k8ssandra/k8ssandra-operator/apis/control/v1alpha1"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/k8ssandra/k8ssandra-client/pkg/tasks"
+)
+
+func taskScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, cassdcapi.AddToScheme(scheme))
+	require.NoError(t, controlapi.AddToScheme(scheme))
+	require.NoError(t, k8ssandrataskapi.AddToScheme(scheme))
+	return scheme
+}
+
+func TestListTasks(t *testing.T) {
+	require := require.New(t)
+
+	task := &controlapi.CassandraTask{
+		ObjectMeta: metav1.ObjectMeta{Name: "dc1-flush", Namespace: "ns1", UID: "abc"},
+		Spec: controlapi.CassandraTaskSpec{
+			Datacenter: corev1.ObjectReference{Name: "dc1", Namespace: "ns1"},
+			CassandraTaskTemplate: controlapi.CassandraTaskTemplate{
+				Jobs: []controlapi.CassandraJob{{Name: "a", Command: controlapi.CommandFlush, Arguments: controlapi.JobArguments{RackName: "r1"}}},
+			},
+		},
+	}
+	clusterTask := &k8ssandrataskapi.K8ssandraTask{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo-cleanup", Namespace: "ns2"},
+		Spec: k8ssandrataskapi.K8ssandraTaskSpec{
+			Cluster: corev1.ObjectReference{Name: "demo"},
+			Template: controlapi.CassandraTaskTemplate{
+				Jobs: []controlapi.CassandraJob{{Name: "a", Command: controlapi.CommandCleanup}},
+			},
+		},
+		Status: k8ssandrataskapi.K8ssandraTaskStatus{
+			Datacenters: map[string]controlapi.CassandraTaskStatus{"dc1": {Succeeded: 3}},
+		},
+	}
+
+	kubeClient := fake.NewClientBuilder().WithScheme(taskScheme(t)).WithObjects(task, clusterTask).Build()
+
+	summaries, err := tasks.ListTasks(context.TODO(), kubeClient, "")
+	require.NoError(err)
+	require.Len(summaries, 2)
+	require.Equal(tasks.KindCassandraTask, summaries[0].Kind)
+	require.Equal("dc1/r1", summaries[0].TargetString())
+	require.Equal([]string{"flush"}, summaries[0].Commands)
+	require.Equal(tasks.KindK8ssandraTask, summaries[1].Kind)
+	require.Equal("*", summaries[1].TargetString())
+	require.Equal(3, summaries[1].DatacenterStatus["dc1"].Succeeded)
+
+	summaries, err = tasks.ListTasks(context.TODO(), kubeClient, "ns2")
+	require.NoError(err)
+	require.Len(summaries, 1)
+
+	summary, err := tasks.GetTask(context.TODO(), kubeClient, types.NamespacedName{Name: "demo-cleanup", Namespace: "ns2"})
+	require.NoError(err)
+	require.Equal(tasks.KindK8ssandraTask, summary.Kind)
+
+	_, err = tasks.GetTask(context.TODO(), kubeClient, types.NamespacedName{Name: "missing", Namespace: "ns2"})
+	require.Error(err)
+}
+
+func TestPodJobStatuses(t *testing.T) {
+	require := require.New(t)
+
+	task := &controlapi.CassandraTask{
+		ObjectMeta: metav1.ObjectMeta{Name: "dc1-flush", Namespace: "ns1", UID: "abc"},
+		Spec: controlapi.CassandraTaskSpec{
+			Datacenter: corev1.ObjectReference{Name: "dc1", Namespace: "ns1"},
+		},
+	}
+	podRunning := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "pod-1",
+			Namespace:   "ns1",
+			Labels:      map[string]string{cassdcapi.DatacenterLabel: "dc1"},
+			Annotations: map[string]string{"control.k8ssandra.io/job-abc": `{"id":"1","status":"WAITING","handler":"management-api","retries":1}`},
+		},
+	}
+	podIdle := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pod-0",
+			Namespace: "ns1",
+			Labels:    map[string]string{cassdcapi.DatacenterLabel: "dc1"},
+		},
+	}
+
+	kubeClient := fake.NewClientBuilder().WithScheme(taskScheme(t)).WithObjects(task, podRunning, podIdle).Build()
+
+	statuses, err := tasks.PodJobStatuses(context.TODO(), kubeClient, tasks.SummaryFromTask(task))
+	require.NoError(err)
+	require.Len(statuses, 2)
+	require.Equal("pod-0", statuses[0].Pod)
+	require.Equal("", statuses[0].Status)
+	require.Equal("pod-1", statuses[1].Pod)
+	require.Equal("WAITING", statuses[1].Status)
+	require.Equal(1, statuses[1].Retries)
+}
+
+func TestWatchTask(t *testing.T) {
+	require := require.New(t)
+
+	task := &controlapi.CassandraTask{
+		ObjectMeta: metav1.ObjectMeta{Name: "dc1-flush", Namespace: "ns1"},
+		Spec: controlapi.CassandraTaskSpec{
+			Datacenter: corev1.ObjectReference{Name: "dc1", Namespace: "ns1"},
+		},
+	}
+
+	kubeClient := fake.NewClientBuilder().WithScheme(taskScheme(t)).WithObjects(task).WithStatusSubresource(task).Build()
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Second)
+	defer cancel()
+
+	updates := make(chan *tasks.Summary, 10)
+	done := make(chan error)
+	go func() {
+		done <- tasks.WatchTask(ctx, kubeClient, types.NamespacedName{Name: "dc1-flush", Namespace: "ns1"}, func(s *tasks.Summary, _ []tasks.PodJobStatus) {
+			updates <- s
+		})
+	}()
+
+	require.False((<-updates).Completed())
+
+	now := metav1.Now()
+	task.Status.CompletionTime = &now
+	require.NoError(kubeClient.Status().Update(ctx, task))
+
+	require.NoError(<-done)
+
+	var last *tasks.Summary
+	for len(updates) > 0 {
+		last = <-updates
+	}
+	require.NotNil(last)
+	require.True(last.Completed())
+}
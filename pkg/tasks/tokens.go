@@ -0,0 +1,96 @@
+package tasks
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/Jeffail/gabs/v2"
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+)
+
+const (
+	PartitionerMurmur3           = "Murmur3Partitioner"
+	PartitionerRandom            = "RandomPartitioner"
+	PartitionerByteOrdered       = "ByteOrderedPartitioner"
+	PartitionerOrderPreserving   = "OrderPreservingPartitioner"
+	defaultPartitioner           = PartitionerMurmur3
+	partitionerConfigPath        = "cassandra-yaml.partitioner"
+	numTokensConfigPath          = "cassandra-yaml.num_tokens"
+	randomPartitionerMaxTokenExp = 127
+)
+
+var errNotSingleToken = fmt.Errorf("only nodes of single token datacenters can be moved")
+
+// Partitioner returns the short class name of the partitioner configured for the datacenter, Murmur3Partitioner if
+// nothing is set
+func Partitioner(dc *cassdcapi.CassandraDatacenter) string {
+	config, err := gabs.ParseJSON(dc.Spec.Config)
+	if err != nil {
+		return defaultPartitioner
+	}
+
+	partitioner, ok := config.Path(partitionerConfigPath).Data().(string)
+	if !ok || partitioner == "" {
+		return defaultPartitioner
+	}
+
+	return partitionerName(partitioner)
+}
+
+// ValidateSingleToken checks the datacenter sets num_tokens to 1. Without it the nodes use vnodes, which can not be
+// moved.
+func ValidateSingleToken(dc *cassdcapi.CassandraDatacenter) error {
+	numTokens := "unset"
+	if config, err := gabs.ParseJSON(dc.Spec.Config); err == nil {
+		if n, ok := config.Path(numTokensConfigPath).Data().(float64); ok {
+			if n == 1 {
+				return nil
+			}
+			numTokens = strconv.FormatFloat(n, 'f', -1, 64)
+		}
+	}
+
+	return fmt.Errorf("%w, cassandradatacenter/%s has num_tokens %s", errNotSingleToken, dc.Name, numTokens)
+}
+
+func partitionerName(partitioner string) string {
+	if partitioner == "" {
+		return defaultPartitioner
+	}
+
+	return partitioner[strings.LastIndex(partitioner, ".")+1:]
+}
+
+// ValidateToken checks the token is a single valid token of the partitioner. Tokens of unknown partitioners are
+// accepted as is.
+func ValidateToken(partitioner, token string) error {
+	if token == "" {
+		return fmt.Errorf("token must be specified")
+	}
+
+	if strings.ContainsAny(token, ", ") {
+		return fmt.Errorf("only a single token can be moved to, got %q", token)
+	}
+
+	switch partitionerName(partitioner) {
+	case PartitionerMurmur3:
+		if _, err := strconv.ParseInt(token, 10, 64); err != nil {
+			return fmt.Errorf("invalid %s token %q, expected an integer between -2^63 and 2^63-1", PartitionerMurmur3, token)
+		}
+	case PartitionerRandom:
+		value, ok := new(big.Int).SetString(token, 10)
+		maxToken := new(big.Int).Lsh(big.NewInt(1), randomPartitionerMaxTokenExp)
+		if !ok || value.Sign() < 0 || value.Cmp(maxToken) > 0 {
+			return fmt.Errorf("invalid %s token %q, expected an integer between 0 and 2^127", PartitionerRandom, token)
+		}
+	case PartitionerByteOrdered:
+		if _, err := hex.DecodeString(token); err != nil {
+			return fmt.Errorf("invalid %s token %q, expected a hex string", PartitionerByteOrdered, token)
+		}
+	}
+
+	return nil
+}
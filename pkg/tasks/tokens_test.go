@@ -0,0 +1,58 @@
+package tasks_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	"github.com/stretchr/testify/require"
+
+	"github.com/k8ssandra/k8ssandra-client/pkg/tasks"
+)
+
+func TestPartitioner(t *testing.T) {
+	require := require.New(t)
+
+	dc := &cassdcapi.CassandraDatacenter{}
+	require.Equal(tasks.PartitionerMurmur3, tasks.Partitioner(dc))
+
+	dc.Spec.Config = json.RawMessage(`{"cassandra-yaml": {"num_tokens": 1}}`)
+	require.Equal(tasks.PartitionerMurmur3, tasks.Partitioner(dc))
+
+	dc.Spec.Config = json.RawMessage(`{"cassandra-yaml": {"partitioner": "org.apache.cassandra.dht.RandomPartitioner"}}`)
+	require.Equal(tasks.PartitionerRandom, tasks.Partitioner(dc))
+}
+
+func TestValidateSingleToken(t *testing.T) {
+	require := require.New(t)
+
+	dc := &cassdcapi.CassandraDatacenter{}
+	dc.Name = "dc1"
+	require.EqualError(tasks.ValidateSingleToken(dc), "only nodes of single token datacenters can be moved, cassandradatacenter/dc1 has num_tokens unset")
+
+	dc.Spec.Config = json.RawMessage(`{"cassandra-yaml": {"num_tokens": 16}}`)
+	require.EqualError(tasks.ValidateSingleToken(dc), "only nodes of single token datacenters can be moved, cassandradatacenter/dc1 has num_tokens 16")
+
+	dc.Spec.Config = json.RawMessage(`{"cassandra-yaml": {"num_tokens": 1}}`)
+	require.NoError(tasks.ValidateSingleToken(dc))
+}
+
+func TestValidateToken(t *testing.T) {
+	require := require.New(t)
+
+	require.NoError(tasks.ValidateToken("", "-9223372036854775808"))
+	require.NoError(tasks.ValidateToken(tasks.PartitionerMurmur3, "3074457345618258602"))
+	require.Error(tasks.ValidateToken(tasks.PartitionerMurmur3, "9223372036854775808"))
+	require.Error(tasks.ValidateToken(tasks.PartitionerMurmur3, "abc"))
+	require.Error(tasks.ValidateToken(tasks.PartitionerMurmur3, "1,2"))
+	require.Error(tasks.ValidateToken(tasks.PartitionerMurmur3, ""))
+
+	require.NoError(tasks.ValidateToken("org.apache.cassandra.dht.RandomPartitioner", "170141183460469231731687303715884105728"))
+	require.Error(tasks.ValidateToken(tasks.PartitionerRandom, "170141183460469231731687303715884105729"))
+	require.Error(tasks.ValidateToken(tasks.PartitionerRandom, "-1"))
+
+	require.NoError(tasks.ValidateToken(tasks.PartitionerByteOrdered, "0a1b"))
+	require.Error(tasks.ValidateToken(tasks.PartitionerByteOrdered, "xyz"))
+
+	require.NoError(tasks.ValidateToken(tasks.PartitionerOrderPreserving, "anything"))
+}
@@ -2,32 +2,208 @@ package tasks
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	controlapi "github.com/k8ssandra/cass-operator/apis/control/v1alpha1"
 	"github.com/k8ssandra/k8ssandra-client/pkg/util"
+	k8ssandrataskapi "github.com/k8ssandra/k8ssandra-operator/apis/control/v1alpha1"
 	"k8s.io/apimachinery/pkg/types"
 	waitutil "k8s.io/apimachinery/pkg/util/wait"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-func WaitForCompletion(ctx context.Context, kubeClient client.Client, task *controlapi.CassandraTask) error {
-	taskKey := types.NamespacedName{Name: task.Name, Namespace: task.Namespace}
-	return WaitForCompletionKey(ctx, kubeClient, taskKey)
+const (
+	defaultWaitInterval = 5 * time.Second
+	defaultWaitTimeout  = 10 * time.Minute
+)
+
+// Outcome is the final state of a waited task
+type Outcome string
+
+const (
+	OutcomeSucceeded Outcome = "Succeeded"
+	OutcomeFailed    Outcome = "Failed"
+	OutcomeTimedOut  Outcome = "TimedOut"
+)
+
+// Result is the outcome of waiting for a CassandraTask or K8ssandraTask
+type Result struct {
+	Outcome Outcome
+
+	// Summary is the last seen state of the task
+	Summary *Summary
+
+	// FailedPods are the pods where a job of the task ended in an error
+	FailedPods []string
+
+	// Messages are the condition messages of the task and, for K8ssandraTasks, of its datacenters
+	Messages []string
+}
+
+// Err returns nil if the task succeeded and otherwise an error describing the failure
+func (r *Result) Err() error {
+	switch r.Outcome {
+	case OutcomeSucceeded:
+		return nil
+	case OutcomeTimedOut:
+		return fmt.Errorf("timed out waiting for %s %s to complete", r.Summary.Kind, r.Summary.Name)
+	}
+
+	details := make([]string, 0, 2)
+	if len(r.FailedPods) > 0 {
+		details = append(details, fmt.Sprintf("failed pods: %s", strings.Join(r.FailedPods, ", ")))
+	}
+	if len(r.Messages) > 0 {
+		details = append(details, strings.Join(r.Messages, "; "))
+	}
+
+	if len(details) == 0 {
+		return fmt.Errorf("%s %s failed, %d jobs failed", r.Summary.Kind, r.Summary.Name, r.Summary.Status.Failed)
+	}
+
+	return fmt.Errorf("%s %s failed, %s", r.Summary.Kind, r.Summary.Name, strings.Join(details, ", "))
 }
 
-func WaitForCompletionKey(ctx context.Context, kubeClient client.Client, taskKey types.NamespacedName) error {
-	err := waitutil.PollUntilContextTimeout(ctx, 5*time.Second, 10*time.Minute, true, func(context.Context) (done bool, err error) {
-		task := &controlapi.CassandraTask{}
-		if err := kubeClient.Get(ctx, taskKey, task); err != nil {
+type waitOptions struct {
+	interval time.Duration
+	timeout  time.Duration
+}
+
+// WaitOption modifies the behavior of WaitForTask
+type WaitOption func(*waitOptions)
+
+// WithInterval sets how often the task status is checked
+func WithInterval(interval time.Duration) WaitOption {
+	return func(o *waitOptions) {
+		if interval > 0 {
+			o.interval = interval
+		}
+	}
+}
+
+// WithTimeout sets how long to wait for the task to complete
+func WithTimeout(timeout time.Duration) WaitOption {
+	return func(o *waitOptions) {
+		if timeout > 0 {
+			o.timeout = timeout
+		}
+	}
+}
+
+// WaitForTask waits until the CassandraTask or K8ssandraTask has completed and reports whether it succeeded, failed
+// or did not complete in time. An error is only returned if the task could not be fetched.
+func WaitForTask(ctx context.Context, kubeClient client.Client, taskKey types.NamespacedName, opts ...WaitOption) (*Result, error) {
+	o := &waitOptions{
+		interval: defaultWaitInterval,
+		timeout:  defaultWaitTimeout,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	var summary *Summary
+
+	// cass-operator removes the pod tracking once the task completes, so the failures are collected while waiting
+	failedPods := make(map[string]struct{})
+
+	err := waitutil.PollUntilContextTimeout(ctx, o.interval, o.timeout, true, func(ctx context.Context) (done bool, err error) {
+		summary, err = GetTask(ctx, kubeClient, taskKey)
+		if err != nil {
 			return false, err
 		}
 
-		return task.Status.CompletionTime != nil, nil
+		pods, err := PodJobStatuses(ctx, kubeClient, summary)
+		if err != nil {
+			return false, err
+		}
+
+		for _, pod := range pods {
+			if pod.Status == podJobError {
+				failedPods[pod.Pod] = struct{}{}
+			}
+		}
+
+		return summary.Completed(), nil
 	})
 
-	return err
+	if err != nil {
+		if summary != nil && errors.Is(err, context.DeadlineExceeded) {
+			return &Result{Outcome: OutcomeTimedOut, Summary: summary}, nil
+		}
+		return nil, err
+	}
+
+	result := &Result{
+		Outcome:  OutcomeSucceeded,
+		Summary:  summary,
+		Messages: conditionMessages(summary),
+	}
+
+	for pod := range failedPods {
+		result.FailedPods = append(result.FailedPods, pod)
+	}
+	sort.Strings(result.FailedPods)
+
+	if summary.Failed() || len(result.FailedPods) > 0 {
+		result.Outcome = OutcomeFailed
+	}
+
+	return result, nil
+}
+
+func conditionMessages(s *Summary) []string {
+	messages := make([]string, 0)
+	for _, cond := range s.Status.Conditions {
+		if cond.Message != "" {
+			messages = append(messages, fmt.Sprintf("%s: %s", cond.Type, cond.Message))
+		}
+	}
+
+	dcs := make([]string, 0, len(s.DatacenterStatus))
+	for dc := range s.DatacenterStatus {
+		dcs = append(dcs, dc)
+	}
+	sort.Strings(dcs)
+
+	for _, dc := range dcs {
+		status := s.DatacenterStatus[dc]
+		if status.Failed > 0 {
+			messages = append(messages, fmt.Sprintf("%s: %d jobs failed", dc, status.Failed))
+		}
+		for _, cond := range status.Conditions {
+			if cond.Message != "" {
+				messages = append(messages, fmt.Sprintf("%s: %s: %s", dc, cond.Type, cond.Message))
+			}
+		}
+	}
+
+	return messages
+}
+
+// WaitForCompletion waits until the CassandraTask has completed and returns an error if it failed or timed out
+func WaitForCompletion(ctx context.Context, kubeClient client.Client, task *controlapi.CassandraTask, opts ...WaitOption) error {
+	taskKey := types.NamespacedName{Name: task.Name, Namespace: task.Namespace}
+	return WaitForCompletionKey(ctx, kubeClient, taskKey, opts...)
+}
+
+// WaitForClusterCompletion waits until the K8ssandraTask has completed and returns an error if it failed or timed out
+func WaitForClusterCompletion(ctx context.Context, kubeClient client.Client, task *k8ssandrataskapi.K8ssandraTask, opts ...WaitOption) error {
+	taskKey := types.NamespacedName{Name: task.Name, Namespace: task.Namespace}
+	return WaitForCompletionKey(ctx, kubeClient, taskKey, opts...)
+}
+
+// WaitForCompletionKey waits until the task has completed and returns an error if it failed or timed out
+func WaitForCompletionKey(ctx context.Context, kubeClient client.Client, taskKey types.NamespacedName, opts ...WaitOption) error {
+	result, err := WaitForTask(ctx, kubeClient, taskKey, opts...)
+	if err != nil {
+		return err
+	}
+
+	return result.Err()
 }
 
 func createName(first, second string) string {
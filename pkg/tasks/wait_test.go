@@ -0,0 +1,131 @@
+package tasks_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	controlapi "github.com/k8ssandra/cass-operator/apis/control/v1alpha1"
+	k8ssandrataskapi "github.com/k8ssandra/k8ssandra-operator/apis/control/v1alpha1"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/k8ssandra/k8ssandra-client/pkg/tasks"
+)
+
+func TestWaitForTaskSucceeded(t *testing.T) {
+	require := require.New(t)
+
+	now := metav1.Now()
+	task := &controlapi.CassandraTask{
+		ObjectMeta: metav1.ObjectMeta{Name: "dc1-flush", Namespace: "ns1"},
+		Spec: controlapi.CassandraTaskSpec{
+			Datacenter: corev1.ObjectReference{Name: "dc1", Namespace: "ns1"},
+		},
+		Status: controlapi.CassandraTaskStatus{
+			CompletionTime: &now,
+			Succeeded:      3,
+			Conditions: []metav1.Condition{
+				{Type: string(controlapi.JobComplete), Status: metav1.ConditionTrue},
+			},
+		},
+	}
+
+	kubeClient := fake.NewClientBuilder().WithScheme(taskScheme(t)).WithObjects(task).Build()
+
+	result, err := tasks.WaitForTask(context.TODO(), kubeClient, types.NamespacedName{Name: "dc1-flush", Namespace: "ns1"})
+	require.NoError(err)
+	require.Equal(tasks.OutcomeSucceeded, result.Outcome)
+	require.NoError(result.Err())
+	require.NoError(tasks.WaitForCompletion(context.TODO(), kubeClient, task))
+}
+
+func TestWaitForTaskFailed(t *testing.T) {
+	require := require.New(t)
+
+	task := &controlapi.CassandraTask{
+		ObjectMeta: metav1.ObjectMeta{Name: "dc1-restart", Namespace: "ns1", UID: "abc"},
+		Spec: controlapi.CassandraTaskSpec{
+			Datacenter: corev1.ObjectReference{Name: "dc1", Namespace: "ns1"},
+		},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "pod-0",
+			Namespace:   "ns1",
+			Labels:      map[string]string{cassdcapi.DatacenterLabel: "dc1"},
+			Annotations: map[string]string{"control.k8ssandra.io/job-abc": `{"id":"1","status":"ERROR"}`},
+		},
+	}
+
+	kubeClient := fake.NewClientBuilder().WithScheme(taskScheme(t)).WithObjects(task, pod).WithStatusSubresource(task).Build()
+
+	go func() {
+		// cass-operator removes the pod tracking when the task completes
+		time.Sleep(100 * time.Millisecond)
+		delete(pod.Annotations, "control.k8ssandra.io/job-abc")
+		_ = kubeClient.Update(context.TODO(), pod)
+
+		now := metav1.Now()
+		task.Status.CompletionTime = &now
+		task.Status.Failed = 1
+		task.Status.Conditions = []metav1.Condition{
+			{Type: string(controlapi.JobFailed), Status: metav1.ConditionTrue, Message: "restart failed"},
+		}
+		_ = kubeClient.Status().Update(context.TODO(), task)
+	}()
+
+	result, err := tasks.WaitForTask(context.TODO(), kubeClient, types.NamespacedName{Name: "dc1-restart", Namespace: "ns1"}, tasks.WithInterval(50*time.Millisecond))
+	require.NoError(err)
+	require.Equal(tasks.OutcomeFailed, result.Outcome)
+	require.Equal([]string{"pod-0"}, result.FailedPods)
+	require.Equal([]string{"Failed: restart failed"}, result.Messages)
+	require.Error(result.Err())
+	require.Contains(result.Err().Error(), "pod-0")
+}
+
+func TestWaitForTaskTimedOut(t *testing.T) {
+	require := require.New(t)
+
+	task := &k8ssandrataskapi.K8ssandraTask{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo-cleanup", Namespace: "ns1"},
+		Spec: k8ssandrataskapi.K8ssandraTaskSpec{
+			Cluster: corev1.ObjectReference{Name: "demo"},
+		},
+	}
+
+	kubeClient := fake.NewClientBuilder().WithScheme(taskScheme(t)).WithObjects(task).Build()
+
+	result, err := tasks.WaitForTask(context.TODO(), kubeClient, types.NamespacedName{Name: "demo-cleanup", Namespace: "ns1"}, tasks.WithInterval(10*time.Millisecond), tasks.WithTimeout(50*time.Millisecond))
+	require.NoError(err)
+	require.Equal(tasks.OutcomeTimedOut, result.Outcome)
+	require.Equal(tasks.KindK8ssandraTask, result.Summary.Kind)
+	require.Error(tasks.WaitForClusterCompletion(context.TODO(), kubeClient, task, tasks.WithInterval(10*time.Millisecond), tasks.WithTimeout(50*time.Millisecond)))
+}
+
+func TestWaitForClusterTaskFailedDatacenter(t *testing.T) {
+	require := require.New(t)
+
+	now := metav1.Now()
+	task := &k8ssandrataskapi.K8ssandraTask{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo-cleanup", Namespace: "ns1"},
+		Status: k8ssandrataskapi.K8ssandraTaskStatus{
+			CassandraTaskStatus: controlapi.CassandraTaskStatus{CompletionTime: &now},
+			Datacenters: map[string]controlapi.CassandraTaskStatus{
+				"dc1": {Succeeded: 3},
+				"dc2": {Failed: 1},
+			},
+		},
+	}
+
+	kubeClient := fake.NewClientBuilder().WithScheme(taskScheme(t)).WithObjects(task).Build()
+
+	result, err := tasks.WaitForTask(context.TODO(), kubeClient, types.NamespacedName{Name: "demo-cleanup", Namespace: "ns1"})
+	require.NoError(err)
+	require.Equal(tasks.OutcomeFailed, result.Outcome)
+	require.Equal([]string{"dc2: 1 jobs failed"}, result.Messages)
+}
@@ -0,0 +1,111 @@
+package tasks
+
+import (
+	"context"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	controlapi "github.com/k8ssandra/cass-operator/apis/control/v1alpha1"
+	k8ssandrataskapi "github.com/k8ssandra/k8ssandra-operator/apis/control/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// WatchTask calls onUpdate with the current state of the task every time the task, or for a CassandraTask one of the
+// pods of its datacenter, changes. It returns once the task has completed or the context is cancelled.
+func WatchTask(ctx context.Context, kubeClient client.WithWatch, key types.NamespacedName, onUpdate func(*Summary, []PodJobStatus)) error {
+	summary, err := GetTask(ctx, kubeClient, key)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	changes := make(chan struct{}, 1)
+	errs := make(chan error, 2)
+
+	var taskList client.ObjectList = &controlapi.CassandraTaskList{}
+	if summary.Kind == KindK8ssandraTask {
+		taskList = &k8ssandrataskapi.K8ssandraTaskList{}
+	}
+
+	if err := watchChanges(ctx, kubeClient, taskList, changes, errs, client.InNamespace(key.Namespace), client.MatchingFields{"metadata.name": key.Name}); err != nil {
+		return err
+	}
+
+	if summary.Kind == KindCassandraTask && len(summary.Datacenters) > 0 {
+		if err := watchChanges(ctx, kubeClient, &corev1.PodList{}, changes, errs, client.InNamespace(key.Namespace), client.MatchingLabels{cassdcapi.DatacenterLabel: summary.Datacenters[0]}); err != nil {
+			return err
+		}
+	}
+
+	for {
+		// Always refetch, the first state could have changed before the watches were established
+		summary, err = GetTask(ctx, kubeClient, key)
+		if err != nil {
+			return err
+		}
+
+		pods, err := PodJobStatuses(ctx, kubeClient, summary)
+		if err != nil {
+			return err
+		}
+
+		onUpdate(summary, pods)
+
+		if summary.Completed() {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-errs:
+			return err
+		case <-changes:
+		}
+	}
+}
+
+// watchChanges opens a watch and signals every change of the listed objects until the context is cancelled. The watch
+// is reopened if the server closes it.
+func watchChanges(ctx context.Context, kubeClient client.WithWatch, list client.ObjectList, changes chan<- struct{}, errs chan<- error, opts ...client.ListOption) error {
+	w, err := kubeClient.Watch(ctx, list, opts...)
+	if err != nil {
+		return err
+	}
+
+	signal := func() {
+		select {
+		case changes <- struct{}{}:
+		default:
+		}
+	}
+
+	go func() {
+		for {
+			for range w.ResultChan() {
+				signal()
+			}
+			w.Stop()
+
+			if ctx.Err() != nil {
+				return
+			}
+
+			w, err = kubeClient.Watch(ctx, list, opts...)
+			if err != nil {
+				if ctx.Err() == nil {
+					errs <- err
+				}
+				return
+			}
+
+			// Something could have changed while the watch was closed
+			signal()
+		}
+	}()
+
+	return nil
+}
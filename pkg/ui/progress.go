@@ -0,0 +1,232 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/k8ssandra/k8ssandra-client/pkg/cassdcutil"
+	"golang.org/x/term"
+)
+
+const defaultRefreshInterval = time.Second
+
+var errInterrupted = errors.New("interrupted")
+
+// RefreshFunc fetches the current state of the pods taking part in the operation
+type RefreshFunc func(ctx context.Context) ([]cassdcutil.PodProgress, error)
+
+// ProgressView shows the state of the pods while an operation is running. On a terminal the pods are shown as a
+// table updated in place, otherwise every change of a pod is logged as a line.
+type ProgressView struct {
+	out         io.Writer
+	title       string
+	refresh     RefreshFunc
+	interval    time.Duration
+	interactive bool
+}
+
+// NewProgressView creates a ProgressView writing to out, interactive only if out is a terminal
+func NewProgressView(out io.Writer, title string, refresh RefreshFunc) *ProgressView {
+	return &ProgressView{
+		out:         out,
+		title:       title,
+		refresh:     refresh,
+		interval:    defaultRefreshInterval,
+		interactive: IsTerminal(out),
+	}
+}
+
+// IsTerminal reports if the stream, the input or an output of the command, is a terminal
+func IsTerminal(stream any) bool {
+	f, ok := stream.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+// Run runs the operation while showing the progress and returns its error
+func (v *ProgressView) Run(ctx context.Context, op func(ctx context.Context) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- op(ctx)
+	}()
+
+	if v.interactive {
+		return v.runInteractive(ctx, cancel, done)
+	}
+
+	return v.runPlain(ctx, done)
+}
+
+// runPlain logs a line every time the state of a pod changes
+func (v *ProgressView) runPlain(ctx context.Context, done <-chan error) error {
+	fmt.Fprintln(v.out, v.title)
+
+	previous := make(map[string]string)
+	logChanges := func() {
+		pods, err := v.refresh(ctx)
+		if err != nil {
+			return
+		}
+
+		for _, pod := range pods {
+			line := podLine(pod)
+			if previous[pod.Pod] == line {
+				continue
+			}
+			previous[pod.Pod] = line
+			fmt.Fprintf(v.out, "%s %s\n", time.Now().Format(time.TimeOnly), line)
+		}
+	}
+
+	ticker := time.NewTicker(v.interval)
+	defer ticker.Stop()
+
+	logChanges()
+	for {
+		select {
+		case err := <-done:
+			logChanges()
+			return err
+		case <-ticker.C:
+			logChanges()
+		}
+	}
+}
+
+func podLine(pod cassdcutil.PodProgress) string {
+	return fmt.Sprintf("pod %s: phase=%s ready=%t restarts=%d job=%s", pod.Pod, orDash(pod.Phase), pod.Ready, pod.Restarts, orDash(pod.Job))
+}
+
+func (v *ProgressView) runInteractive(ctx context.Context, cancel context.CancelFunc, done <-chan error) error {
+	model := &progressModel{title: v.title, started: time.Now()}
+	program := tea.NewProgram(model, tea.WithOutput(v.out), tea.WithContext(ctx))
+
+	go func() {
+		ticker := time.NewTicker(v.interval)
+		defer ticker.Stop()
+
+		for {
+			pods, err := v.refresh(ctx)
+			program.Send(progressMsg{pods: pods, err: err})
+
+			select {
+			case err := <-done:
+				pods, _ := v.refresh(ctx)
+				program.Send(progressMsg{pods: pods})
+				program.Send(doneMsg{err: err})
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	if _, err := program.Run(); err != nil && !errors.Is(err, tea.ErrProgramKilled) {
+		return err
+	}
+
+	if model.interrupted {
+		cancel()
+		return errInterrupted
+	}
+
+	return model.err
+}
+
+type progressMsg struct {
+	pods []cassdcutil.PodProgress
+	err  error
+}
+
+type doneMsg struct {
+	err error
+}
+
+type progressModel struct {
+	title       string
+	started     time.Time
+	pods        []cassdcutil.PodProgress
+	refreshErr  error
+	finished    bool
+	interrupted bool
+	err         error
+}
+
+func (m *progressModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *progressModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if msg.Type == tea.KeyCtrlC || msg.Type == tea.KeyEsc {
+			m.interrupted = true
+			return m, tea.Quit
+		}
+	case progressMsg:
+		m.refreshErr = msg.err
+		if msg.err == nil {
+			m.pods = msg.pods
+		}
+	case doneMsg:
+		m.finished = true
+		m.err = msg.err
+		return m, tea.Quit
+	}
+
+	return m, nil
+}
+
+var (
+	titleStyle = lipgloss.NewStyle().Bold(true)
+	errorStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+	dimStyle   = lipgloss.NewStyle().Faint(true)
+)
+
+func (m *progressModel) View() string {
+	var sb strings.Builder
+
+	elapsed := time.Since(m.started).Truncate(time.Second)
+	sb.WriteString(titleStyle.Render(fmt.Sprintf("%s (%s)", m.title, elapsed)))
+	sb.WriteString("\n\n")
+
+	w := tabwriter.NewWriter(&sb, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "POD\tPHASE\tREADY\tRESTARTS\tJOB")
+	for _, pod := range m.pods {
+		fmt.Fprintf(w, "%s\t%s\t%t\t%s\t%s\n", pod.Pod, orDash(pod.Phase), pod.Ready, strconv.Itoa(int(pod.Restarts)), orDash(pod.Job))
+	}
+	_ = w.Flush()
+
+	switch {
+	case m.refreshErr != nil:
+		sb.WriteString(errorStyle.Render(fmt.Sprintf("\nunable to refresh: %v", m.refreshErr)))
+		sb.WriteString("\n")
+	case m.finished && m.err != nil:
+		sb.WriteString(errorStyle.Render(fmt.Sprintf("\nfailed: %v", m.err)))
+		sb.WriteString("\n")
+	case !m.finished:
+		sb.WriteString(dimStyle.Render("\npress ctrl+c to stop waiting"))
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/k8ssandra/k8ssandra-client/pkg/cassdcutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgressViewPlain(t *testing.T) {
+	require := require.New(t)
+
+	var mu sync.Mutex
+	job := "Running"
+	refresh := func(ctx context.Context) ([]cassdcutil.PodProgress, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return []cassdcutil.PodProgress{
+			{Pod: "dc1-r1-sts-0", Phase: "Running", Ready: true, Job: job},
+			{Pod: "dc1-r1-sts-1", Phase: "Running", Ready: true},
+		}, nil
+	}
+
+	var out bytes.Buffer
+	view := NewProgressView(&out, "Waiting for task", refresh)
+	require.False(view.interactive)
+	view.interval = 10 * time.Millisecond
+
+	errWait := errors.New("timed out")
+	err := view.Run(context.Background(), func(ctx context.Context) error {
+		time.Sleep(50 * time.Millisecond)
+		mu.Lock()
+		job = "Completed"
+		mu.Unlock()
+		return errWait
+	})
+	require.ErrorIs(err, errWait)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Equal("Waiting for task", lines[0])
+	// Unchanged pods are only logged once
+	require.Len(lines, 4)
+	require.Contains(lines[1], "pod dc1-r1-sts-0: phase=Running ready=true restarts=0 job=Running")
+	require.Contains(lines[2], "pod dc1-r1-sts-1: phase=Running ready=true restarts=0 job=-")
+	require.Contains(lines[3], "pod dc1-r1-sts-0: phase=Running ready=true restarts=0 job=Completed")
+}
+
+func TestProgressModel(t *testing.T) {
+	require := require.New(t)
+
+	model := &progressModel{title: "Waiting for datacenter dc1 to be stopped", started: time.Now()}
+	_, cmd := model.Update(progressMsg{pods: []cassdcutil.PodProgress{{Pod: "dc1-r1-sts-0", Phase: "Terminating", Restarts: 2}}})
+	require.Nil(cmd)
+
+	view := model.View()
+	require.Contains(view, "Waiting for datacenter dc1 to be stopped")
+	require.Contains(view, "RESTARTS")
+	require.Contains(view, "dc1-r1-sts-0")
+	require.Contains(view, "Terminating")
+
+	_, cmd = model.Update(doneMsg{err: errors.New("timed out")})
+	require.NotNil(cmd)
+	require.True(model.finished)
+	require.Contains(model.View(), "failed: timed out")
+}
@@ -0,0 +1,71 @@
+package upgrade
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+const serverTypeCassandra = "cassandra"
+
+// cassandraUpgradePath lists the Cassandra minor versions in the order they have to be upgraded through
+var cassandraUpgradePath = []string{"3.11", "4.0", "4.1", "5.0"}
+
+var (
+	errAlreadyAtVersion   = errors.New("datacenter already runs the target version")
+	errDowngrade          = errors.New("downgrading is not supported")
+	errSkippedVersion     = errors.New("upgrades can not skip versions")
+	errUnsupportedVersion = errors.New("version is not on the supported upgrade path")
+	errMajorUpgrade       = errors.New("only upgrades within the same major version are supported for this server type")
+)
+
+// ValidateUpgradePath checks the datacenter can be upgraded directly from version from to version to. Cassandra has to
+// be upgraded one minor version at a time, 3.11 → 4.0 → 4.1 → 5.0, other server types only within the same major
+// version.
+func ValidateUpgradePath(serverType, from, to string) error {
+	fromVersion, err := semver.NewVersion(from)
+	if err != nil {
+		return fmt.Errorf("invalid current version %q: %w", from, err)
+	}
+
+	toVersion, err := semver.NewVersion(to)
+	if err != nil {
+		return fmt.Errorf("invalid target version %q: %w", to, err)
+	}
+
+	switch toVersion.Compare(fromVersion) {
+	case 0:
+		return fmt.Errorf("%w: %s", errAlreadyAtVersion, from)
+	case -1:
+		return fmt.Errorf("%w: %s to %s", errDowngrade, from, to)
+	}
+
+	if serverType != "" && serverType != serverTypeCassandra {
+		if toVersion.Major() != fromVersion.Major() {
+			return fmt.Errorf("%w: %s %s to %s", errMajorUpgrade, serverType, from, to)
+		}
+		return nil
+	}
+
+	fromIndex := slices.Index(cassandraUpgradePath, minorVersion(fromVersion))
+	if fromIndex < 0 {
+		return fmt.Errorf("%w: %s", errUnsupportedVersion, from)
+	}
+
+	toIndex := slices.Index(cassandraUpgradePath, minorVersion(toVersion))
+	if toIndex < 0 {
+		return fmt.Errorf("%w: %s", errUnsupportedVersion, to)
+	}
+
+	if toIndex > fromIndex+1 {
+		return fmt.Errorf("%w: upgrade %s to %s.x first", errSkippedVersion, from, cassandraUpgradePath[fromIndex+1])
+	}
+
+	return nil
+}
+
+func minorVersion(version *semver.Version) string {
+	return fmt.Sprintf("%d.%d", version.Major(), version.Minor())
+}
@@ -0,0 +1,41 @@
+package upgrade
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateUpgradePath(t *testing.T) {
+	tests := []struct {
+		name       string
+		serverType string
+		from       string
+		to         string
+		err        error
+	}{
+		{name: "next minor", serverType: "cassandra", from: "4.0.12", to: "4.1.5"},
+		{name: "next major", serverType: "cassandra", from: "4.1.5", to: "5.0.2"},
+		{name: "patch", serverType: "cassandra", from: "4.1.4", to: "4.1.5"},
+		{name: "3.11 to 4.0", from: "3.11.17", to: "4.0.13"},
+		{name: "skipped minor", serverType: "cassandra", from: "4.0.12", to: "5.0.2", err: errSkippedVersion},
+		{name: "same version", serverType: "cassandra", from: "4.1.5", to: "4.1.5", err: errAlreadyAtVersion},
+		{name: "downgrade", serverType: "cassandra", from: "5.0.2", to: "4.1.5", err: errDowngrade},
+		{name: "unknown version", serverType: "cassandra", from: "4.1.5", to: "6.0.0", err: errUnsupportedVersion},
+		{name: "dse minor", serverType: "dse", from: "6.8.40", to: "6.9.0"},
+		{name: "dse major", serverType: "dse", from: "6.8.40", to: "7.0.0", err: errMajorUpgrade},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateUpgradePath(tt.serverType, tt.from, tt.to)
+			if tt.err == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+
+	require.Error(t, ValidateUpgradePath("cassandra", "4.1.5", "latest"))
+}
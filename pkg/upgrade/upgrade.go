@@ -0,0 +1,517 @@
+package upgrade
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	controlapi "github.com/k8ssandra/cass-operator/apis/control/v1alpha1"
+	"github.com/k8ssandra/k8ssandra-client/pkg/cassdcutil"
+	"github.com/k8ssandra/k8ssandra-client/pkg/kubernetes"
+	"github.com/k8ssandra/k8ssandra-client/pkg/mgmtapi"
+	"github.com/k8ssandra/k8ssandra-client/pkg/tasks"
+	k8ssandraapi "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	waitutil "k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// StateAnnotation stores the progress of the upgrade on the CassandraDatacenter, allowing it to be resumed.
+	// k8ssandra-operator overwrites the annotations of the CassandraDatacenters it renders, thus for a datacenter of a
+	// K8ssandraCluster it is stored on the cluster instead, as a map of the datacenter names to their State.
+	StateAnnotation = "k8ssandra.io/upgrade-version"
+
+	defaultInterval = 5 * time.Second
+	defaultTimeout  = 30 * time.Minute
+)
+
+// Phase is a step of the upgrade workflow, run in the order they are declared
+type Phase string
+
+const (
+	PhaseFlush           Phase = "Flush"
+	PhaseSnapshot        Phase = "Snapshot"
+	PhaseUpdate          Phase = "Update"
+	PhaseUpgradeSSTables Phase = "UpgradeSSTables"
+	PhaseCompleted       Phase = "Completed"
+)
+
+var (
+	errUpgradeInProgress = errors.New("another upgrade is in progress")
+	errNoPods            = errors.New("datacenter has no pods")
+)
+
+// State is the progress of an upgrade, stored in the StateAnnotation
+type State struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Image string `json:"image,omitempty"`
+	Phase Phase  `json:"phase"`
+
+	// Task is the task created for the current phase, waited for instead of creating a new one when resuming
+	Task string `json:"task,omitempty"`
+
+	Snapshot     string   `json:"snapshot,omitempty"`
+	SnapshotPods []string `json:"snapshotPods,omitempty"`
+}
+
+// PodFunc runs an action against the node of the pod
+type PodFunc func(ctx context.Context, pod *corev1.Pod) error
+
+// SnapshotFunc takes a named snapshot on the node of the pod
+type SnapshotFunc func(ctx context.Context, pod *corev1.Pod, name string) error
+
+// Options modify the behavior of Run
+type Options struct {
+	// To is the target server version
+	To string
+
+	// Image overrides the server image, defaults to the image cass-operator picks for the version
+	Image string
+
+	// Timeout limits each phase
+	Timeout time.Duration
+
+	// Interval is the polling interval of the task and datacenter checks
+	Interval time.Duration
+
+	// HealthChecker defaults to the management-api gossip and schema agreement check
+	HealthChecker PodFunc
+
+	// Snapshotter defaults to taking the snapshot through the management-api
+	Snapshotter SnapshotFunc
+
+	// OnPhase is called when a phase starts
+	OnPhase func(state *State)
+}
+
+type upgrader struct {
+	kubeClient client.Client
+	dc         *cassdcapi.CassandraDatacenter
+	kc         *k8ssandraapi.K8ssandraCluster
+	opts       Options
+	state      *State
+}
+
+// CurrentState returns the state of the upgrade in progress on the datacenter or nil. kc is the K8ssandraCluster owning
+// the datacenter, nil for a standalone datacenter.
+func CurrentState(dc *cassdcapi.CassandraDatacenter, kc *k8ssandraapi.K8ssandraCluster) (*State, error) {
+	if kc != nil {
+		states, err := clusterStates(kc)
+		if err != nil {
+			return nil, err
+		}
+		return states[dc.Name], nil
+	}
+
+	data, found := dc.Annotations[StateAnnotation]
+	if !found {
+		return nil, nil
+	}
+
+	state := &State{}
+	if err := json.Unmarshal([]byte(data), state); err != nil {
+		return nil, fmt.Errorf("invalid %s annotation: %w", StateAnnotation, err)
+	}
+
+	return state, nil
+}
+
+// clusterStates returns the states of the upgrades in progress on the datacenters of the K8ssandraCluster
+func clusterStates(kc *k8ssandraapi.K8ssandraCluster) (map[string]*State, error) {
+	states := make(map[string]*State)
+	data, found := kc.Annotations[StateAnnotation]
+	if !found {
+		return states, nil
+	}
+
+	if err := json.Unmarshal([]byte(data), &states); err != nil {
+		return nil, fmt.Errorf("invalid %s annotation: %w", StateAnnotation, err)
+	}
+
+	return states, nil
+}
+
+// Run upgrades the server version of the datacenter. It verifies the upgrade path and that every node is UP, then
+// flushes and snapshots the nodes, updates the version, waits for the rolling update and finally upgrades the
+// SSTables. The progress is stored on the datacenter, or on its K8ssandraCluster, and running it again with the same target version resumes an
+// interrupted upgrade from the phase it stopped at.
+func Run(ctx context.Context, kubeClient client.Client, dc *cassdcapi.CassandraDatacenter, opts Options) (*State, error) {
+	if opts.Timeout == 0 {
+		opts.Timeout = defaultTimeout
+	}
+
+	if opts.Interval == 0 {
+		opts.Interval = defaultInterval
+	}
+
+	if opts.HealthChecker == nil || opts.Snapshotter == nil {
+		mgmtClient, err := mgmtapi.NewManagementClient(ctx, kubeClient, dc.Namespace, dc.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		if opts.HealthChecker == nil {
+			opts.HealthChecker = func(ctx context.Context, pod *corev1.Pod) error {
+				return mgmtapi.CheckNodeHealth(mgmtClient, pod)
+			}
+		}
+
+		if opts.Snapshotter == nil {
+			opts.Snapshotter = func(ctx context.Context, pod *corev1.Pod, name string) error {
+				return mgmtapi.TakeSnapshot(mgmtClient, pod, name)
+			}
+		}
+	}
+
+	kc, err := cassdcutil.NewManager(kubeClient).DatacenterCluster(ctx, dc)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := CurrentState(dc, kc)
+	if err != nil {
+		return nil, err
+	}
+
+	if state != nil {
+		if state.To != opts.To {
+			return state, fmt.Errorf("%w: %s to %s, run it again with --to %s to resume", errUpgradeInProgress, state.From, state.To, state.To)
+		}
+	} else {
+		if err := ValidateUpgradePath(dc.Spec.ServerType, dc.Spec.ServerVersion, opts.To); err != nil {
+			return nil, err
+		}
+
+		state = &State{
+			From:  dc.Spec.ServerVersion,
+			To:    opts.To,
+			Image: opts.Image,
+			Phase: PhaseFlush,
+		}
+	}
+
+	u := &upgrader{
+		kubeClient: kubeClient,
+		dc:         dc,
+		kc:         kc,
+		opts:       opts,
+		state:      state,
+	}
+
+	return state, u.run(ctx)
+}
+
+func (u *upgrader) run(ctx context.Context) error {
+	// Nodes restart during the update, they are only required to be UP before anything has changed
+	if u.state.Phase == PhaseFlush || u.state.Phase == PhaseSnapshot {
+		if err := u.checkHealth(ctx); err != nil {
+			return err
+		}
+
+		if err := u.saveState(ctx, nil); err != nil {
+			return err
+		}
+	}
+
+	for u.state.Phase != PhaseCompleted {
+		if u.opts.OnPhase != nil {
+			u.opts.OnPhase(u.state)
+		}
+
+		var err error
+		switch u.state.Phase {
+		case PhaseFlush:
+			err = u.runTask(ctx, PhaseSnapshot, func() (*controlapi.CassandraTask, error) {
+				return tasks.CreateFlushTask(ctx, u.kubeClient, u.dc, "", "")
+			})
+		case PhaseSnapshot:
+			err = u.snapshot(ctx)
+		case PhaseUpdate:
+			err = u.update(ctx)
+		case PhaseUpgradeSSTables:
+			err = u.runTask(ctx, PhaseCompleted, func() (*controlapi.CassandraTask, error) {
+				return tasks.CreateUpgradeSSTablesTask(ctx, u.kubeClient, u.dc, "", "")
+			})
+		default:
+			err = fmt.Errorf("unknown upgrade phase %s", u.state.Phase)
+		}
+
+		if err != nil {
+			return fmt.Errorf("upgrade to %s failed in phase %s: %w", u.state.To, u.state.Phase, err)
+		}
+	}
+
+	if u.opts.OnPhase != nil {
+		u.opts.OnPhase(u.state)
+	}
+
+	return nil
+}
+
+// checkHealth verifies every node of the datacenter sees the whole cluster UP with schema agreement
+func (u *upgrader) checkHealth(ctx context.Context) error {
+	pods, err := u.pods(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i := range pods {
+		if err := u.opts.HealthChecker(ctx, &pods[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// runTask creates the task of the phase, or continues waiting for the one created earlier, and moves to the next phase
+// once it has succeeded
+func (u *upgrader) runTask(ctx context.Context, next Phase, create func() (*controlapi.CassandraTask, error)) error {
+	if u.state.Task == "" {
+		task, err := create()
+		if err != nil {
+			return err
+		}
+
+		if err := u.saveState(ctx, func(s *State) { s.Task = task.Name }); err != nil {
+			return err
+		}
+	}
+
+	result, err := tasks.WaitForTask(ctx, u.kubeClient, types.NamespacedName{Name: u.state.Task, Namespace: u.dc.Namespace}, tasks.WithInterval(u.opts.Interval), tasks.WithTimeout(u.opts.Timeout))
+	if err != nil {
+		return err
+	}
+
+	if err := result.Err(); err != nil {
+		// Create a new task when resuming
+		if errSave := u.saveState(ctx, func(s *State) { s.Task = "" }); errSave != nil {
+			return errors.Join(err, errSave)
+		}
+		return err
+	}
+
+	return u.saveState(ctx, func(s *State) {
+		s.Phase = next
+		s.Task = ""
+	})
+}
+
+// snapshot takes the same named snapshot on every node, skipping the nodes already done
+func (u *upgrader) snapshot(ctx context.Context) error {
+	if u.state.Snapshot == "" {
+		name := fmt.Sprintf("upgrade-%s-%s", u.state.To, time.Now().UTC().Format("20060102150405"))
+		if err := u.saveState(ctx, func(s *State) { s.Snapshot = name }); err != nil {
+			return err
+		}
+	}
+
+	pods, err := u.pods(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i := range pods {
+		pod := &pods[i]
+		if slices.Contains(u.state.SnapshotPods, pod.Name) {
+			continue
+		}
+
+		if err := u.opts.Snapshotter(ctx, pod, u.state.Snapshot); err != nil {
+			return err
+		}
+
+		if err := u.saveState(ctx, func(s *State) { s.SnapshotPods = append(s.SnapshotPods, pod.Name) }); err != nil {
+			return err
+		}
+	}
+
+	return u.saveState(ctx, func(s *State) { s.Phase = PhaseUpdate })
+}
+
+// update sets the new version and waits for cass-operator to finish the rolling update. k8ssandra-operator renders the
+// CassandraDatacenters of a K8ssandraCluster and would revert a version set on the datacenter, thus the version is set
+// on the datacenter template of the cluster instead.
+func (u *upgrader) update(ctx context.Context) error {
+	if u.dc.Spec.ServerVersion != u.state.To || (u.state.Image != "" && u.dc.Spec.ServerImage != u.state.Image) {
+		manager := cassdcutil.NewManager(u.kubeClient)
+		kc, err := manager.DatacenterCluster(ctx, u.dc)
+		if err != nil {
+			return err
+		}
+
+		if kc != nil {
+			if _, err := manager.SetClusterDatacenterVersion(ctx, kc, u.dc.Name, u.state.To, u.state.Image, cmdutil.DryRunNone); err != nil {
+				return err
+			}
+		} else {
+			dc := u.dc.DeepCopy()
+			patch := client.MergeFrom(u.dc)
+
+			dc.Spec.ServerVersion = u.state.To
+			if u.state.Image != "" {
+				dc.Spec.ServerImage = u.state.Image
+			}
+
+			if err := u.kubeClient.Patch(ctx, dc, patch); err != nil {
+				return err
+			}
+			u.dc = dc
+		}
+	}
+
+	if err := u.waitForRollingUpdate(ctx); err != nil {
+		return err
+	}
+
+	return u.saveState(ctx, func(s *State) { s.Phase = PhaseUpgradeSSTables })
+}
+
+// waitForRollingUpdate waits until the new version has reached the datacenter, cass-operator has observed the change
+// and every pod is ready again
+func (u *upgrader) waitForRollingUpdate(ctx context.Context) error {
+	return waitutil.PollUntilContextTimeout(ctx, u.opts.Interval, u.opts.Timeout, false, func(ctx context.Context) (bool, error) {
+		dc := &cassdcapi.CassandraDatacenter{}
+		if err := u.kubeClient.Get(ctx, client.ObjectKeyFromObject(u.dc), dc); err != nil {
+			return false, err
+		}
+
+		// The version of a K8ssandraCluster datacenter reaches the CassandraDatacenter only once the operator reconciles it
+		if dc.Spec.ServerVersion != u.state.To || (u.state.Image != "" && dc.Spec.ServerImage != u.state.Image) {
+			return false, nil
+		}
+
+		if dc.Status.ObservedGeneration < dc.Generation ||
+			dc.Status.GetConditionStatus(cassdcapi.DatacenterUpdating) == corev1.ConditionTrue ||
+			dc.Status.GetConditionStatus(cassdcapi.DatacenterReady) != corev1.ConditionTrue {
+			return false, nil
+		}
+
+		pods, err := u.pods(ctx)
+		if err != nil {
+			return false, err
+		}
+
+		for _, pod := range pods {
+			if !kubernetes.PodReady(&pod) {
+				return false, nil
+			}
+		}
+
+		if len(pods) != int(dc.Spec.Size) {
+			return false, nil
+		}
+
+		u.dc = dc
+		return true, nil
+	})
+}
+
+// saveState applies the change to the state and stores it, removing the state once completed
+func (u *upgrader) saveState(ctx context.Context, change func(*State)) error {
+	if change != nil {
+		change(u.state)
+	}
+
+	if u.kc != nil {
+		return u.saveClusterState(ctx)
+	}
+
+	dc := u.dc.DeepCopy()
+	patch := client.MergeFrom(u.dc)
+
+	if err := setStateAnnotation(dc, u.state, u.state.Phase == PhaseCompleted); err != nil {
+		return err
+	}
+
+	if err := u.kubeClient.Patch(ctx, dc, patch); err != nil {
+		return err
+	}
+
+	u.dc = dc
+	return nil
+}
+
+// saveClusterState stores the state on the K8ssandraCluster. The annotation holds the upgrades of every datacenter of
+// the cluster, thus the cluster is read again and patched with an optimistic lock.
+func (u *upgrader) saveClusterState(ctx context.Context) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		kc := &k8ssandraapi.K8ssandraCluster{}
+		if err := u.kubeClient.Get(ctx, client.ObjectKeyFromObject(u.kc), kc); err != nil {
+			return err
+		}
+
+		states, err := clusterStates(kc)
+		if err != nil {
+			return err
+		}
+
+		if u.state.Phase == PhaseCompleted {
+			delete(states, u.dc.Name)
+		} else {
+			states[u.dc.Name] = u.state
+		}
+
+		patch := client.MergeFromWithOptions(kc.DeepCopy(), client.MergeFromWithOptimisticLock{})
+		if err := setStateAnnotation(kc, states, len(states) == 0); err != nil {
+			return err
+		}
+
+		if err := u.kubeClient.Patch(ctx, kc, patch); err != nil {
+			return err
+		}
+
+		u.kc = kc
+		return nil
+	})
+}
+
+// setStateAnnotation sets the StateAnnotation of the object to the value, or removes it
+func setStateAnnotation(obj client.Object, value any, remove bool) error {
+	annotations := obj.GetAnnotations()
+	if remove {
+		delete(annotations, StateAnnotation)
+		obj.SetAnnotations(annotations)
+		return nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[StateAnnotation] = string(data)
+	obj.SetAnnotations(annotations)
+	return nil
+}
+
+func (u *upgrader) pods(ctx context.Context) ([]corev1.Pod, error) {
+	podList, err := cassdcutil.NewManager(u.kubeClient).CassandraDatacenterPods(ctx, u.dc)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(podList.Items) == 0 {
+		return nil, errNoPods
+	}
+
+	pods := podList.Items
+	sort.Slice(pods, func(i, j int) bool {
+		return pods[i].Name < pods[j].Name
+	})
+
+	return pods, nil
+}
@@ -0,0 +1,313 @@
+package upgrade
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	cassdcapi "github.com/k8ssandra/cass-operator/apis/cassandra/v1beta1"
+	controlapi "github.com/k8ssandra/cass-operator/apis/control/v1alpha1"
+	k8ssandraapi "github.com/k8ssandra/k8ssandra-operator/apis/k8ssandra/v1alpha1"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func testDatacenter() *cassdcapi.CassandraDatacenter {
+	return &cassdcapi.CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{Name: "dc1", Namespace: "ns1"},
+		Spec: cassdcapi.CassandraDatacenterSpec{
+			ServerType:    "cassandra",
+			ServerVersion: "4.1.5",
+			Size:          2,
+		},
+		Status: cassdcapi.CassandraDatacenterStatus{
+			Conditions: []cassdcapi.DatacenterCondition{{Type: cassdcapi.DatacenterReady, Status: corev1.ConditionTrue}},
+		},
+	}
+}
+
+func testPod(name string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns1", Labels: map[string]string{cassdcapi.DatacenterLabel: "dc1"}},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+		},
+	}
+}
+
+func testClient(t *testing.T, objs ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, cassdcapi.AddToScheme(scheme))
+	require.NoError(t, controlapi.AddToScheme(scheme))
+	require.NoError(t, k8ssandraapi.AddToScheme(scheme))
+
+	return fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(append(objs, testPod("dc1-r1-sts-0"), testPod("dc1-r1-sts-1"))...).
+		WithStatusSubresource(&controlapi.CassandraTask{}).
+		Build()
+}
+
+// fakeOperator completes the tasks and renders the K8ssandraCluster datacenter versions until the context is cancelled.
+// Like k8ssandra-operator, rendering a datacenter replaces its annotations.
+func fakeOperator(ctx context.Context, kubeClient client.Client) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(10 * time.Millisecond):
+		}
+
+		kcList := &k8ssandraapi.K8ssandraClusterList{}
+		if err := kubeClient.List(ctx, kcList); err == nil {
+			for _, kc := range kcList.Items {
+				for _, template := range kc.Spec.Cassandra.Datacenters {
+					dc := &cassdcapi.CassandraDatacenter{}
+					if err := kubeClient.Get(ctx, types.NamespacedName{Name: template.Meta.Name, Namespace: kc.Namespace}, dc); err != nil {
+						continue
+					}
+					if template.ServerVersion != "" && dc.Spec.ServerVersion != template.ServerVersion {
+						patch := client.MergeFrom(dc.DeepCopy())
+						dc.Spec.ServerVersion = template.ServerVersion
+						dc.Annotations = nil
+						_ = kubeClient.Patch(ctx, dc, patch)
+					}
+				}
+			}
+		}
+
+		taskList := &controlapi.CassandraTaskList{}
+		if err := kubeClient.List(ctx, taskList); err != nil {
+			continue
+		}
+		for i := range taskList.Items {
+			task := &taskList.Items[i]
+			if task.Status.CompletionTime != nil {
+				continue
+			}
+			now := metav1.Now()
+			task.Status.CompletionTime = &now
+			task.Status.Succeeded = 1
+			_ = kubeClient.Status().Update(ctx, task)
+		}
+	}
+}
+
+type recorder struct {
+	mu        sync.Mutex
+	checked   []string
+	snapshots []string
+	phases    []Phase
+}
+
+func (r *recorder) options(to string) Options {
+	return Options{
+		To:       to,
+		Interval: 10 * time.Millisecond,
+		Timeout:  2 * time.Second,
+		HealthChecker: func(ctx context.Context, pod *corev1.Pod) error {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			r.checked = append(r.checked, pod.Name)
+			return nil
+		},
+		Snapshotter: func(ctx context.Context, pod *corev1.Pod, name string) error {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			r.snapshots = append(r.snapshots, pod.Name)
+			return nil
+		},
+		OnPhase: func(state *State) {
+			r.phases = append(r.phases, state.Phase)
+		},
+	}
+}
+
+func runUpgrade(t *testing.T, kubeClient client.Client, opts Options) (*State, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		fakeOperator(ctx, kubeClient)
+	}()
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	dc := &cassdcapi.CassandraDatacenter{}
+	require.NoError(t, kubeClient.Get(context.Background(), types.NamespacedName{Name: "dc1", Namespace: "ns1"}, dc))
+	return Run(context.Background(), kubeClient, dc, opts)
+}
+
+func TestUpgrade(t *testing.T) {
+	require := require.New(t)
+	kubeClient := testClient(t, testDatacenter())
+	r := &recorder{}
+
+	state, err := runUpgrade(t, kubeClient, r.options("5.0.2"))
+	require.NoError(err)
+	require.Equal(PhaseCompleted, state.Phase)
+	require.Equal([]Phase{PhaseFlush, PhaseSnapshot, PhaseUpdate, PhaseUpgradeSSTables, PhaseCompleted}, r.phases)
+	require.Equal([]string{"dc1-r1-sts-0", "dc1-r1-sts-1"}, r.checked)
+	require.Equal([]string{"dc1-r1-sts-0", "dc1-r1-sts-1"}, r.snapshots)
+
+	dc := &cassdcapi.CassandraDatacenter{}
+	require.NoError(kubeClient.Get(context.Background(), types.NamespacedName{Name: "dc1", Namespace: "ns1"}, dc))
+	require.Equal("5.0.2", dc.Spec.ServerVersion)
+	require.NotContains(dc.Annotations, StateAnnotation)
+
+	taskList := &controlapi.CassandraTaskList{}
+	require.NoError(kubeClient.List(context.Background(), taskList))
+	require.Len(taskList.Items, 2)
+	commands := make([]controlapi.CassandraCommand, 0, len(taskList.Items))
+	for _, task := range taskList.Items {
+		commands = append(commands, task.Spec.Jobs[0].Command)
+	}
+	require.ElementsMatch([]controlapi.CassandraCommand{controlapi.CommandFlush, controlapi.CommandUpgradeSSTables}, commands)
+}
+
+func TestUpgradeInvalidPath(t *testing.T) {
+	kubeClient := testClient(t, testDatacenter())
+	r := &recorder{}
+
+	_, err := runUpgrade(t, kubeClient, r.options("6.0.0"))
+	require.ErrorIs(t, err, errUnsupportedVersion)
+	require.Empty(t, r.checked)
+}
+
+func TestUpgradeUnhealthyNode(t *testing.T) {
+	require := require.New(t)
+	kubeClient := testClient(t, testDatacenter())
+	r := &recorder{}
+
+	errDown := errors.New("10.0.0.1 is DOWN")
+	opts := r.options("5.0.2")
+	opts.HealthChecker = func(ctx context.Context, pod *corev1.Pod) error { return errDown }
+
+	_, err := runUpgrade(t, kubeClient, opts)
+	require.ErrorIs(err, errDown)
+
+	dc := &cassdcapi.CassandraDatacenter{}
+	require.NoError(kubeClient.Get(context.Background(), types.NamespacedName{Name: "dc1", Namespace: "ns1"}, dc))
+	require.Equal("4.1.5", dc.Spec.ServerVersion)
+	require.NotContains(dc.Annotations, StateAnnotation)
+}
+
+func TestUpgradeResume(t *testing.T) {
+	require := require.New(t)
+
+	// The previous run was interrupted after the first node was snapshotted
+	dc := testDatacenter()
+	data, err := json.Marshal(&State{From: "4.1.5", To: "5.0.2", Phase: PhaseSnapshot, Snapshot: "upgrade-5.0.2", SnapshotPods: []string{"dc1-r1-sts-0"}})
+	require.NoError(err)
+	dc.Annotations = map[string]string{StateAnnotation: string(data)}
+
+	kubeClient := testClient(t, dc)
+	r := &recorder{}
+
+	_, err = runUpgrade(t, kubeClient, r.options("5.0.3"))
+	require.ErrorIs(err, errUpgradeInProgress)
+
+	state, err := runUpgrade(t, kubeClient, r.options("5.0.2"))
+	require.NoError(err)
+	require.Equal("4.1.5", state.From)
+	require.Equal([]Phase{PhaseSnapshot, PhaseUpdate, PhaseUpgradeSSTables, PhaseCompleted}, r.phases)
+	require.Equal([]string{"dc1-r1-sts-1"}, r.snapshots)
+
+	taskList := &controlapi.CassandraTaskList{}
+	require.NoError(kubeClient.List(context.Background(), taskList))
+	require.Len(taskList.Items, 1)
+	require.Equal(controlapi.CommandUpgradeSSTables, taskList.Items[0].Spec.Jobs[0].Command)
+}
+
+func testClusterDatacenter() *cassdcapi.CassandraDatacenter {
+	dc := testDatacenter()
+	dc.Labels = map[string]string{
+		k8ssandraapi.K8ssandraClusterNameLabel:      "demo",
+		k8ssandraapi.K8ssandraClusterNamespaceLabel: "ns1",
+	}
+	return dc
+}
+
+func testCluster() *k8ssandraapi.K8ssandraCluster {
+	return &k8ssandraapi.K8ssandraCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "ns1"},
+		Spec: k8ssandraapi.K8ssandraClusterSpec{
+			Cassandra: &k8ssandraapi.CassandraClusterTemplate{
+				DatacenterOptions: k8ssandraapi.DatacenterOptions{ServerVersion: "4.1.5"},
+				Datacenters: []k8ssandraapi.CassandraDatacenterTemplate{
+					{Meta: k8ssandraapi.EmbeddedObjectMeta{Name: "dc1"}, Size: 2},
+				},
+			},
+		},
+	}
+}
+
+func TestUpgradeClusterDatacenter(t *testing.T) {
+	require := require.New(t)
+
+	dc := testClusterDatacenter()
+	kc := testCluster()
+	kubeClient := testClient(t, dc, kc)
+	r := &recorder{}
+
+	state, err := runUpgrade(t, kubeClient, r.options("5.0.2"))
+	require.NoError(err)
+	require.Equal(PhaseCompleted, state.Phase)
+	require.Equal([]Phase{PhaseFlush, PhaseSnapshot, PhaseUpdate, PhaseUpgradeSSTables, PhaseCompleted}, r.phases)
+
+	// The version is set on the datacenter template, the operator renders it to the CassandraDatacenter
+	require.NoError(kubeClient.Get(context.Background(), client.ObjectKeyFromObject(kc), kc))
+	require.Equal("5.0.2", kc.Spec.Cassandra.Datacenters[0].ServerVersion)
+	require.Equal("4.1.5", kc.Spec.Cassandra.ServerVersion)
+
+	require.NoError(kubeClient.Get(context.Background(), client.ObjectKeyFromObject(dc), dc))
+	require.Equal("5.0.2", dc.Spec.ServerVersion)
+	require.NotContains(dc.Annotations, StateAnnotation)
+	require.NotContains(kc.Annotations, StateAnnotation)
+}
+
+func TestUpgradeClusterDatacenterResume(t *testing.T) {
+	require := require.New(t)
+
+	// The previous run was interrupted after the operator had rendered the new version, replacing the annotations
+	// of the CassandraDatacenter
+	dc := testClusterDatacenter()
+	dc.Spec.ServerVersion = "5.0.2"
+
+	kc := testCluster()
+	kc.Spec.Cassandra.Datacenters[0].ServerVersion = "5.0.2"
+	data, err := json.Marshal(map[string]*State{"dc1": {From: "4.1.5", To: "5.0.2", Phase: PhaseUpdate, Snapshot: "upgrade-5.0.2"}})
+	require.NoError(err)
+	kc.Annotations = map[string]string{StateAnnotation: string(data)}
+
+	kubeClient := testClient(t, dc, kc)
+	r := &recorder{}
+
+	state, err := runUpgrade(t, kubeClient, r.options("5.0.2"))
+	require.NoError(err)
+	require.Equal("4.1.5", state.From)
+	require.Equal("upgrade-5.0.2", state.Snapshot)
+	require.Equal([]Phase{PhaseUpdate, PhaseUpgradeSSTables, PhaseCompleted}, r.phases)
+	require.Empty(r.snapshots)
+
+	taskList := &controlapi.CassandraTaskList{}
+	require.NoError(kubeClient.List(context.Background(), taskList))
+	require.Len(taskList.Items, 1)
+	require.Equal(controlapi.CommandUpgradeSSTables, taskList.Items[0].Spec.Jobs[0].Command)
+
+	require.NoError(kubeClient.Get(context.Background(), client.ObjectKeyFromObject(kc), kc))
+	require.NotContains(kc.Annotations, StateAnnotation)
+}
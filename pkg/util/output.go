@@ -0,0 +1,69 @@
+package util
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// OutputFlags are the kubectl compatible --dry-run and --output flags of the commands creating or modifying objects
+type OutputFlags struct {
+	printFlags *genericclioptions.PrintFlags
+	DryRun     cmdutil.DryRunStrategy
+}
+
+// NewOutputFlags returns OutputFlags which print only the name of the object unless --output is set
+func NewOutputFlags() *OutputFlags {
+	return &OutputFlags{
+		printFlags: genericclioptions.NewPrintFlags(""),
+	}
+}
+
+// AddFlags registers --dry-run and --output to the command
+func (o *OutputFlags) AddFlags(cmd *cobra.Command) {
+	cmdutil.AddDryRunFlag(cmd)
+	o.printFlags.AddFlags(cmd)
+}
+
+// Complete parses the --dry-run flag of the command
+func (o *OutputFlags) Complete(cmd *cobra.Command) error {
+	var err error
+	o.DryRun, err = cmdutil.GetDryRunStrategy(cmd)
+	return err
+}
+
+// PrintObj prints the object in the requested output format, or a kubectl style "kind/name operation" line if none
+// was requested
+func (o *OutputFlags) PrintObj(out io.Writer, scheme *runtime.Scheme, obj client.Object, operation string) error {
+	if o.printFlags.OutputFormat != nil && *o.printFlags.OutputFormat != "" {
+		printer, err := o.printFlags.ToPrinter()
+		if err != nil {
+			return err
+		}
+
+		return printers.NewTypeSetter(scheme).ToPrinter(printer).PrintObj(obj, out)
+	}
+
+	gvk, err := apiutil.GVKForObject(obj, scheme)
+	if err != nil {
+		return err
+	}
+
+	switch o.DryRun {
+	case cmdutil.DryRunClient:
+		operation = fmt.Sprintf("%s (dry run)", operation)
+	case cmdutil.DryRunServer:
+		operation = fmt.Sprintf("%s (server dry run)", operation)
+	}
+
+	_, err = fmt.Fprintf(out, "%s/%s %s\n", strings.ToLower(gvk.Kind), obj.GetName(), operation)
+	return err
+}
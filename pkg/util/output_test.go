@@ -0,0 +1,55 @@
+package util
+
+import (
+	"bytes"
+	"testing"
+
+	controlapi "github.com/k8ssandra/cass-operator/apis/control/v1alpha1"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+func TestOutputFlags(t *testing.T) {
+	require := require.New(t)
+
+	scheme := runtime.NewScheme()
+	require.NoError(controlapi.AddToScheme(scheme))
+	task := &controlapi.CassandraTask{ObjectMeta: metav1.ObjectMeta{Name: "dc1-flush", Namespace: "ns1"}}
+
+	parse := func(args ...string) *OutputFlags {
+		o := NewOutputFlags()
+		cmd := &cobra.Command{}
+		o.AddFlags(cmd)
+		require.NoError(cmd.ParseFlags(args))
+		require.NoError(o.Complete(cmd))
+		return o
+	}
+
+	out := &bytes.Buffer{}
+	o := parse()
+	require.Equal(cmdutil.DryRunNone, o.DryRun)
+	require.NoError(o.PrintObj(out, scheme, task, "created"))
+	require.Equal("cassandratask/dc1-flush created\n", out.String())
+
+	out.Reset()
+	o = parse("--dry-run=client")
+	require.Equal(cmdutil.DryRunClient, o.DryRun)
+	require.NoError(o.PrintObj(out, scheme, task, "created"))
+	require.Equal("cassandratask/dc1-flush created (dry run)\n", out.String())
+
+	out.Reset()
+	o = parse("--dry-run=server", "-o", "yaml")
+	require.Equal(cmdutil.DryRunServer, o.DryRun)
+	require.NoError(o.PrintObj(out, scheme, task, "created"))
+	require.Contains(out.String(), "kind: CassandraTask")
+	require.Contains(out.String(), "apiVersion: control.k8ssandra.io/v1alpha1")
+	require.Empty(task.GetObjectKind().GroupVersionKind().Kind)
+
+	out.Reset()
+	o = parse("-o", "json")
+	require.NoError(o.PrintObj(out, scheme, task, "created"))
+	require.Contains(out.String(), `"kind": "CassandraTask"`)
+}